@@ -5,16 +5,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 
 	gitadapter "github.com/rafaelvolkmer/codeaudit/internal/adapter/git"
 	outputadapter "github.com/rafaelvolkmer/codeaudit/internal/adapter/output"
 	parser "github.com/rafaelvolkmer/codeaudit/internal/adapter/parser"
+	"github.com/rafaelvolkmer/codeaudit/internal/adapter/tui"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
 	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
 	"github.com/rafaelvolkmer/codeaudit/internal/infrastructure"
 	"github.com/rafaelvolkmer/codeaudit/internal/usecase"
@@ -45,6 +51,16 @@ func main() {
 			log.Printf("error: %v", err)
 			os.Exit(1)
 		}
+	case "doctor":
+		if err := runDoctor(os.Args[2:]); err != nil {
+			log.Printf("error: %v", err)
+			os.Exit(1)
+		}
+	case "tui":
+		if err := runTUI(os.Args[2:]); err != nil {
+			log.Printf("error: %v", err)
+			os.Exit(1)
+		}
 	case "-h", "--help", "help":
 		usage()
 	default:
@@ -61,25 +77,119 @@ Usage:
   codeaudit analyze [options] [path]
   codeaudit report  [options] [path]
   codeaudit metrics
+  codeaudit tui     [options] [path]
 
 Commands:
   analyze   Analyze a source tree and persist a report under .codeaudit/report.json
   report    Render the last report (text or json)
   metrics   List supported metrics
+  tui       Browse the last report interactively (requires a build with -tags tui)
 
 Run "codeaudit <command> -h" for command-specific flags.
 `)
 }
 
-func runAnalyze(args []string) error {
+func runAnalyze(args []string) (err error) {
 	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
-	pathFlag := fs.String("path", ".", "Path to project root (can also be given as positional argument)")
+	pathFlag := fs.String("path", ".", "Path to project root or a single source file (can also be given as positional argument)")
 	workersFlag := fs.Int("workers", 0, "Number of worker goroutines (0 = use NumCPU)")
-	extsFlag := fs.String("ext", ".go,.c,.h,.cpp,.hpp", "Comma-separated list of file extensions to include")
+	extsFlag := fs.String("ext", "", "Comma-separated list of file extensions to include (default: every extension handled by the enabled parsers)")
+	includeVendoredFlag := fs.Bool("include-vendored", false, "Also analyze vendor/ and node_modules/ (can be much slower on large dependency trees)")
+	noDefaultSkipsFlag := fs.Bool("no-default-skips", false, "Disable the built-in directory skip list (.git, .codeaudit, vendor, node_modules); slowest option")
+	skipHiddenFlag := fs.Bool("skip-hidden", false, "Skip every file and directory whose name starts with a dot")
+	ownerFlag := fs.String("owner", "", "Only analyze files owned by this CODEOWNERS owner, e.g. @org/team (last matching rule wins)")
+	encodingFlag := fs.String("encoding", "utf-8", "Source encoding to transcode from before parsing, e.g. latin1 or windows-1252 (a UTF-16 byte order mark is always honored)")
+	maxDepthFlag := fs.Int("max-depth", -1, "Do not descend more than N directory levels below the root (0 = root only, -1 = unlimited)")
+	treeFlag := fs.Bool("tree", false, "Group per-function metrics under their file in a tree layout")
+	compareFlag := fs.Bool("compare-to-average", false, "Mark each function as above/below the project mean CCN and size")
+	groupByPackageFlag := fs.Bool("group-by-package", false, "Group Go files by package with per-package CCN/function subtotals")
+	listDeclOnlyFlag := fs.Bool("list-declaration-only", false, "List files that contain no functions (text format)")
+	commentWarnFlag := fs.Float64("comment-warn-below", outputadapter.DefaultCommentWarnBelow, "Color comment density as a warning below this ratio")
+	commentDangerFlag := fs.Float64("comment-danger-below", outputadapter.DefaultCommentDangerBelow, "Color comment density as a danger below this ratio")
+	indentFlag := fs.String("indent", "2", "JSON indentation for the saved report: number of spaces, \"tab\" or \"none\"")
+	strictFlag := fs.Bool("strict", false, "Fail on the first read or parse error instead of recording a warning")
+	authorComplexityFlag := fs.Bool("author-complexity", false, "Attribute file complexity to each file's dominant git author (extra git processing)")
+	hotspotFormulaFlag := fs.String("hotspot-formula", usecase.DefaultHotspotFormula,
+		"Hotspot score expression over ccn, churn, commits, authors, bugfixes, smells, nloc (+ - * /, log1p, log, sqrt, abs, min, max, pow)")
+	healthWeightsFlag := fs.String("health-weights", "cyclomatic=0.5,cognitive=0.5",
+		"Blend of cyclomatic and cognitive complexity in the health grade's complexity component, e.g. \"cognitive=1\" or \"cyclomatic=0.7,cognitive=0.3\"")
+	hotspotModelFlag := fs.String("hotspot-model", "",
+		"Hotspot scoring preset: \"complexity-churn\" (ccn * log1p(churn)) or \"authors\" (also multiplied by 1 + log1p(authors)); cannot be combined with --hotspot-formula")
+	omitIsolatedFlag := fs.Bool("omit-isolated-instability", false, "Leave instability unset for Go packages with no coupling (Ca+Ce=0) instead of scoring them 0")
+	coChangeFlag := fs.Bool("co-change", false, "Report file pairs that are often committed together (reads full git history)")
+	maxFanOutFilesFlag := fs.Int("max-fanout-files", 8, "Flag functions calling into more than N other project files (0 disables)")
+	maxPaddingRatioFlag := fs.Float64("max-padding-ratio", 2.0, "Flag functions whose physical lines exceed N times their logical lines (0 disables)")
+	maxFunctionsPerFileFlag := fs.Int("max-functions-per-file", 0,
+		"Keep only the N most complex functions per file in the report and fold the rest into an \"otherFunctions\" aggregate; project totals still count every function (0 = unlimited)")
+	maxFunctionNLOCFlag := fs.Int("max-function-nloc", 80, "Flag functions with more than N logical lines (0 disables); Go data functions whose body is mostly one composite literal are exempt")
+	maxSurfaceAreaFlag := fs.Int("max-surface-area", 16, "Flag functions whose parameters + results + locals exceed N (0 disables)")
+	maxDivergenceFlag := fs.Float64("max-complexity-divergence", 3,
+		"Note functions whose cognitive/CCN ratio is above N (deeply nested) or below 1/N (flat branching); values <= 1 disable")
+	maxCommentDensityFlag := fs.Float64("max-comment-density", 0, "Flag files whose comment density exceeds this ratio (e.g. 0.6) and that contain commented-out code (0 disables)")
+	mixedIndentFlag := fs.Bool("mixed-indentation", false, "Flag files that mix tab and space indentation")
+	pagerFlag := fs.Bool("pager", false, "Always pipe text output through $PAGER when stdout is a terminal")
+	noPagerFlag := fs.Bool("no-pager", false, "Never pipe output through $PAGER")
+	cognitiveModelFlag := fs.String("cognitive-model", parser.CognitiveModelLine,
+		"Cognitive complexity model: \"line\" (per-line decisions weighted by block depth) or \"sonar\" (Go AST, SonarSource rules: +1 per flow break plus nesting, +1 per boolean operator sequence)")
+	cognitiveLineCapFlag := fs.Int("max-cognitive-per-line", 0,
+		"Cap each line's cognitive increment in the line-based model so deep but simple boilerplate does not dominate (0 = uncapped; not applied to --cognitive-model sonar)")
+	skipGeneratedCogFlag := fs.Bool("skip-generated-cognitive", false, "Score cognitive complexity as 0 in files marked \"Code generated ... DO NOT EDIT.\"")
+	cppModeFlag := fs.String("cpp-mode", parser.CppModeHeuristic,
+		"C++ analysis mode for C++ sources and C++-looking .h headers: \"heuristic\" (line-based scanner shared with C) or \"strict\" (token-based parser aware of classes, templates, raw strings and lambdas)")
+	preprocessorFlag := fs.Bool("preprocessor-complexity", false, "Count C/C++ conditional preprocessor branches per file as preprocessorComplexity (kept separate from CCN)")
+	closureNamesFlag := fs.Bool("qualified-closure-names", false, "Name Go closures after their enclosing function like the runtime does (Execute.func1, Execute.func1.1) instead of @start-end")
+	maxReturnsFlag := fs.Int("max-returns", parser.DefaultMaxReturnValues, "Flag Go functions returning more than N values (a trailing error is not counted)")
+	maxCtorParamsFlag := fs.Int("max-ctor-params", parser.DefaultMaxConstructorParams, "Flag C++ constructors taking more than N parameters")
+	statsFlag := fs.Bool("stats", false, "Append wall time, throughput and peak heap in use to the output")
+	formatFlag := fs.String("format", "text", "Output format (text|json|scatter|gitlab|csv-stable)")
+	outputFlag := fs.String("output", "", "Write the --format output to this file instead of stdout")
+	summaryFormatFlag := fs.String("summary-format", "", "Also render a summary in this format to stdout (the --format output then goes only to --output)")
+	ciBranchFlag := fs.String("ci-branch", "", "Branch recorded in the report's CI metadata (default: from CI env vars such as GITHUB_REF_NAME, CI_COMMIT_REF_NAME)")
+	ciCommitFlag := fs.String("ci-commit", "", "Commit SHA recorded in the report's CI metadata (default: GITHUB_SHA, CI_COMMIT_SHA, ...)")
+	ciBuildIDFlag := fs.String("ci-build-id", "", "Build ID recorded in the report's CI metadata (default: GITHUB_RUN_ID, CI_PIPELINE_ID, ...)")
+	ciPRFlag := fs.String("ci-pr", "", "Pull/merge request number recorded in the report's CI metadata (default: from GITHUB_REF, CI_MERGE_REQUEST_IID, ...)")
+	generatedAtFlag := fs.String("generated-at", "", "Fixed report timestamp (RFC 3339 or unix seconds); defaults to $SOURCE_DATE_EPOCH, then now")
+	maxLineLengthFlag := fs.Int("max-line-length", 1000, "Skip files with a line longer than N chars as minified/generated (0 disables)")
+	repoFlag := fs.String("repo", "", "Clone this git URL into a temporary directory, analyze it and remove it afterwards (auth via the usual git environment/credential helpers); the report is not saved")
+	revFlag := fs.String("rev", "", "Branch or tag to check out with --repo (default: the remote HEAD)")
+	cloneDepthFlag := fs.Int("clone-depth", 100, "History depth fetched with --repo; git metrics only cover these commits (0 = full history)")
+	dirtyFlag := fs.Bool("dirty", false, "Only analyze files with uncommitted changes (git status); the stored report is left untouched")
+	dbFlag := fs.String("db", "", "Also append the report to this SQLite database (tables: project, files, functions, smells)")
+	perFileDirFlag := fs.String("per-file-dir", "", "Also write each file's metrics as JSON under this directory, mirroring the source tree (<path>.json)")
+	baselineFlag := fs.String("baseline-report", "", "Path to a previous report.json; adds a project-level metrics delta to the output")
+	seedReportFlag := fs.String("seed-report", "", "Path to a previous full report.json whose call graph supplies fan-in from files outside this run (useful with --dirty)")
+	allowEmptyFlag := fs.Bool("allow-empty", false, "Produce an empty report instead of failing when no source files match")
+	shadowErrFlag := fs.Bool("shadow-err", false, "Also report shadowed `err` variables (ignored by default)")
+	nolintPrefixFlag := fs.String("nolint-prefix", parser.DefaultNolintPrefix, "Directive that suppresses smells on a Go function, e.g. //nolint:deep_nesting or //nolint:codeaudit")
+	missingDefaultFlag := fs.Bool("missing-default", false, "Report Go switch statements without a default case (off by default: exhaustive enum switches omit it)")
+	explainFlag := fs.String("explain", "", "Print a line-by-line breakdown of CCN/cognitive for one function, as <file>:<function>, and exit")
+	disableParsersFlag := fs.String("disable-parsers", "", "Comma-separated parser names to disable (e.g. \"c/c++\")")
+	var parserExtFlags stringList
+	fs.Var(&parserExtFlags, "parser-ext", "Override a parser's extensions as name=.ext1,.ext2 (repeatable)")
+	parserPriorityFlag := fs.String("parser-priority", "",
+		"Comma-separated parser names tried first, in order. A file goes to the parser with the most specific (longest) matching extension; ties go to the earlier parser, unlisted parsers keep the default order (go, c++, csharp, c/c++). .cpp/.cc/.cxx/.hpp/.hh/.hxx go to the c++ parser and .cs to the csharp parser; a .h header using class, namespace or template is reported as C++")
+	testsFlag := fs.String("tests", usecase.TestsInclude, "Test files: \"include\" (analyze and mark them), \"exclude\" or \"only\"")
+	testDirsFlag := fs.String("test-dirs", strings.Join(usecase.DefaultTestDirs, ","), "Comma-separated directory names whose files count as tests")
+	var testPatternFlags stringList
+	fs.Var(&testPatternFlags, "test-patterns", "Override a language's test file name globs as lang=glob1,glob2, e.g. go=*_test.go (repeatable)")
+	runSummaryFlag := fs.String("run-summary", "", "Write a JSON run summary (files, errors, threshold result, exit code) to this path, even on failure")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
+	summary := infrastructure.NewRunSummaryWriter(*runSummaryFlag, "analyze")
+	defer func() { err = summary.Finish(err) }()
+
+	hotspotFormula := *hotspotFormulaFlag
+	if *hotspotModelFlag != "" && !flagSet(fs, "hotspot-formula") {
+		hotspotFormula = ""
+	}
+
+	healthWeights, err := usecase.ParseHealthWeights(*healthWeightsFlag)
+	if err != nil {
+		return err
+	}
+
 	root := *pathFlag
 	if fs.NArg() > 0 {
 		root = fs.Arg(0)
@@ -93,15 +203,193 @@ func runAnalyze(args []string) error {
 		}
 	}
 
-	includeExt := parseExts(*extsFlag)
+	indent, err := parseIndent(*indentFlag)
+	if err != nil {
+		return err
+	}
+	if err := infrastructure.ValidateEncoding(*encodingFlag); err != nil {
+		return err
+	}
 
-	scanner := infrastructure.NewFSScanner()
-	storage := infrastructure.NewFileStorage()
+	storage := infrastructure.NewFileStorageWithIndent(indent)
 	gitClient := gitadapter.NewGitCLI()
 
-	parsers := []ports.CodeParser{
-		parser.NewGoParser(),
-		parser.NewCParser(),
+	if err := parser.ValidateCognitiveModel(*cognitiveModelFlag); err != nil {
+		return err
+	}
+	if err := parser.ValidateCppMode(*cppModeFlag); err != nil {
+		return err
+	}
+
+	testCfg := usecase.TestFileConfig{
+		Mode:     *testsFlag,
+		Dirs:     parseList(*testDirsFlag),
+		Patterns: make(map[model.Language][]string),
+	}
+	for lang, patterns := range usecase.DefaultTestFilePatterns {
+		testCfg.Patterns[lang] = patterns
+	}
+	for _, spec := range testPatternFlags {
+		lang, patterns, ok := strings.Cut(spec, "=")
+		if !ok {
+			return fmt.Errorf("invalid --test-patterns %q: want lang=glob1,glob2", spec)
+		}
+		testCfg.Patterns[model.Language(strings.ToLower(strings.TrimSpace(lang)))] = parseList(patterns)
+	}
+	if err := usecase.ValidateTestFileConfig(testCfg); err != nil {
+		return err
+	}
+	formulaSource, err := usecase.HotspotModelFormula(*hotspotModelFlag, hotspotFormula)
+	if err != nil {
+		return err
+	}
+	if _, err := usecase.ParseHotspotFormula(formulaSource); err != nil {
+		return err
+	}
+
+	parserCfg := parser.Config{Extensions: make(map[string][]string)}
+	if *disableParsersFlag != "" {
+		parserCfg.Disabled = strings.Split(*disableParsersFlag, ",")
+	}
+	if *parserPriorityFlag != "" {
+		parserCfg.Priority = strings.Split(*parserPriorityFlag, ",")
+	}
+	for _, spec := range parserExtFlags {
+		name, exts, ok := strings.Cut(spec, "=")
+		if !ok {
+			return fmt.Errorf("invalid --parser-ext %q: want name=.ext1,.ext2", spec)
+		}
+		parserCfg.Extensions[name] = parseExts(exts)
+	}
+
+	parsers, err := parser.Configure([]ports.CodeParser{
+		parser.NewGoParserWithOptions(parser.GoParserOptions{
+			ReportErrShadowing: *shadowErrFlag,
+			CognitiveModel:     *cognitiveModelFlag,
+			MissingDefault:     *missingDefaultFlag,
+			NolintPrefix:       *nolintPrefixFlag,
+			MaxReturnValues:    *maxReturnsFlag,
+
+			QualifiedClosureNames: *closureNamesFlag,
+
+			CognitiveLineCap:       *cognitiveLineCapFlag,
+			SkipGeneratedCognitive: *skipGeneratedCogFlag,
+		}),
+		parser.NewCppParserWithOptions(parser.CppParserOptions{
+			CppMode:              *cppModeFlag,
+			MaxConstructorParams: *maxCtorParamsFlag,
+
+			CognitiveLineCap:       *cognitiveLineCapFlag,
+			SkipGeneratedCognitive: *skipGeneratedCogFlag,
+			PreprocessorComplexity: *preprocessorFlag,
+		}),
+		parser.NewCSharpParserWithOptions(parser.CSharpParserOptions{
+			SkipGeneratedCognitive: *skipGeneratedCogFlag,
+		}),
+		parser.NewCParserWithOptions(parser.CParserOptions{
+			MaxConstructorParams: *maxCtorParamsFlag,
+
+			CognitiveLineCap:       *cognitiveLineCapFlag,
+			SkipGeneratedCognitive: *skipGeneratedCogFlag,
+
+			PreprocessorComplexity: *preprocessorFlag,
+		}),
+	}, parserCfg)
+	if err != nil {
+		return err
+	}
+	includeExt := parser.Extensions(parsers)
+	if *extsFlag != "" {
+		includeExt = parser.WithMappedExtensions(parseExts(*extsFlag), parserCfg)
+	}
+
+	var explainTarget usecase.ExplainFunctionRequest
+	if *explainFlag != "" {
+		if explainTarget, err = usecase.ParseExplainTarget(*explainFlag); err != nil {
+			return err
+		}
+	}
+
+	var baseline *model.ProjectReport
+	if *baselineFlag != "" {
+		baseline, err = infrastructure.LoadReportFile(*baselineFlag)
+		if err != nil {
+			return fmt.Errorf("load baseline report: %w", err)
+		}
+	}
+
+	var seedReport *model.ProjectReport
+	if *seedReportFlag != "" {
+		seedReport, err = infrastructure.LoadReportFile(*seedReportFlag)
+		if err != nil {
+			return fmt.Errorf("load seed report: %w", err)
+		}
+	}
+
+	generatedAt, err := infrastructure.ResolveGeneratedAt(*generatedAtFlag)
+	if err != nil {
+		return err
+	}
+
+	rendererRegistry := newRendererRegistry(rendererConfig{
+		text: outputadapter.TextRendererOptions{
+			Tree:             *treeFlag || *dirtyFlag,
+			CompareToAverage: *compareFlag,
+			GroupByPackage:   *groupByPackageFlag,
+
+			ListDeclarationOnly: *listDeclOnlyFlag,
+
+			CommentWarnBelow:   *commentWarnFlag,
+			CommentDangerBelow: *commentDangerFlag,
+		},
+		jsonIndent: indent,
+	})
+	for _, format := range []string{*formatFlag, *summaryFormatFlag} {
+		if _, ok := rendererRegistry.Get(format); format != "" && !ok {
+			return fmt.Errorf("unknown format %q", format)
+		}
+	}
+
+	if *repoFlag != "" {
+		cloneDir, err := os.MkdirTemp("", "codeaudit-clone-*")
+		if err != nil {
+			return fmt.Errorf("create clone directory: %w", err)
+		}
+		defer os.RemoveAll(cloneDir)
+		if err := gitClient.Clone(context.Background(), *repoFlag, *revFlag, *cloneDepthFlag, cloneDir); err != nil {
+			return err
+		}
+		root = cloneDir
+	}
+
+	var codeOwners *infrastructure.CodeOwners
+	if *ownerFlag != "" {
+		if codeOwners, err = infrastructure.LoadCodeOwners(root); err != nil {
+			return err
+		}
+	}
+
+	scanner := infrastructure.NewFSScannerWithOptions(infrastructure.FSScannerOptions{
+		IncludeVendored: *includeVendoredFlag,
+		NoDefaultSkips:  *noDefaultSkipsFlag,
+		SkipHidden:      *skipHiddenFlag,
+
+		LimitDepth: *maxDepthFlag >= 0,
+		MaxDepth:   *maxDepthFlag,
+
+		Encoding: *encodingFlag,
+
+		CodeOwners: codeOwners,
+		Owner:      *ownerFlag,
+	})
+
+	if *explainFlag != "" {
+		exp, err := usecase.NewExplainFunctionUseCase(scanner, parsers).Execute(context.Background(), explainTarget)
+		if err != nil {
+			return err
+		}
+		printExplanation(exp)
+		return nil
 	}
 
 	uc := usecase.NewAnalyzeProjectUseCase(
@@ -114,61 +402,308 @@ func runAnalyze(args []string) error {
 	)
 
 	ctx := context.Background()
+
+	var onlyPaths []string
+	if *dirtyFlag {
+		onlyPaths, err = gitClient.DirtyFiles(ctx, root)
+		if err != nil || onlyPaths == nil {
+			onlyPaths = []string{}
+		}
+	}
+
 	report, err := uc.Execute(ctx, usecase.AnalyzeProjectRequest{
 		RootPath:   root,
 		IncludeExt: includeExt,
+		Strict:     *strictFlag,
+		AllowEmpty: *allowEmptyFlag || *dirtyFlag,
+		Stats:      *statsFlag,
+
+		AuthorComplexity: *authorComplexityFlag,
+		CoChange:         *coChangeFlag,
+
+		Smells: usecase.SmellConfig{
+			MaxFanOutFiles:   *maxFanOutFilesFlag,
+			MaxPaddingRatio:  *maxPaddingRatioFlag,
+			MaxFunctionNLOC:  *maxFunctionNLOCFlag,
+			MaxSurfaceArea:   *maxSurfaceAreaFlag,
+			MixedIndentation: *mixedIndentFlag,
+
+			MaxCommentDensity: *maxCommentDensityFlag,
+
+			MaxComplexityDivergence: *maxDivergenceFlag,
+		},
+
+		Baseline:     baseline,
+		BaselinePath: *baselineFlag,
+		SeedReport:   seedReport,
+
+		OnlyPaths: onlyPaths,
+		NoSave:    *dirtyFlag || *repoFlag != "",
+
+		MaxLineLength:       *maxLineLengthFlag,
+		MaxFunctionsPerFile: *maxFunctionsPerFileFlag,
+		GeneratedAt:         generatedAt,
+		CI: infrastructure.ResolveCIMetadata(model.CIMetadata{
+			Branch:      *ciBranchFlag,
+			Commit:      *ciCommitFlag,
+			BuildID:     *ciBuildIDFlag,
+			PullRequest: *ciPRFlag,
+		}, os.Getenv),
+
+		HotspotFormula: hotspotFormula,
+		HotspotModel:   *hotspotModelFlag,
+
+		HealthWeights: healthWeights,
+
+		OmitIsolatedInstability: *omitIsolatedFlag,
+
+		Tests: testCfg,
 	})
 	if err != nil {
 		return err
 	}
+	summary.RecordReport(report)
 
-	rendererRegistry := outputadapter.NewRendererRegistry(
-		outputadapter.NewTextRenderer(),
-		outputadapter.NewJSONRenderer(),
-	)
-	textRenderer, ok := rendererRegistry.Get("text")
-	if !ok {
-		return fmt.Errorf("text renderer not registered")
+	if *dbFlag != "" {
+		if err := infrastructure.ExportToSQLite(ctx, *dbFlag, report); err != nil {
+			return fmt.Errorf("export to %s: %w", *dbFlag, err)
+		}
+	}
+	if *perFileDirFlag != "" {
+		if _, err := infrastructure.WritePerFileReports(*perFileDirFlag, report, indent); err != nil {
+			return fmt.Errorf("write per-file reports: %w", err)
+		}
 	}
 
-	out, err := textRenderer.Render(report)
+	publish := usecase.NewPublishReportUseCase(rendererRegistry, func(path string, data []byte) error {
+		return os.WriteFile(path, data, 0o644)
+	})
+	out, err := publish.Execute(report, usecase.PublishReportRequest{
+		Format:        *formatFlag,
+		OutputPath:    *outputFlag,
+		SummaryFormat: *summaryFormatFlag,
+	})
 	if err != nil {
 		return err
 	}
-	fmt.Println(out)
-	return nil
+	if out == "" {
+		return nil
+	}
+	printed := *formatFlag
+	if *summaryFormatFlag != "" {
+		printed = *summaryFormatFlag
+	}
+	return infrastructure.NewPager(pagerMode(*pagerFlag, *noPagerFlag, printed)).Print(out)
 }
 
-func runReport(args []string) error {
+func runReport(args []string) (err error) {
 	fs := flag.NewFlagSet("report", flag.ExitOnError)
 	pathFlag := fs.String("path", ".", "Path to project root (can also be given as positional argument)")
-	formatFlag := fs.String("format", "text", "Output format (text|json)")
+	formatFlag := fs.String("format", "text", "Output format (text|json|scatter|gitlab|csv-stable|template)")
+	templateFlag := fs.String("template", "", "Path to a Go text/template rendered against the report (use with --format template)")
+	treeFlag := fs.Bool("tree", false, "Group per-function metrics under their file in a tree layout (text format)")
+	compareFlag := fs.Bool("compare-to-average", false, "Mark each function as above/below the project mean CCN and size (text format)")
+	groupByPackageFlag := fs.Bool("group-by-package", false, "Group Go files by package with per-package CCN/function subtotals (text format)")
+	listDeclOnlyFlag := fs.Bool("list-declaration-only", false, "List files that contain no functions (text format)")
+	commentWarnFlag := fs.Float64("comment-warn-below", outputadapter.DefaultCommentWarnBelow, "Color comment density as a warning below this ratio (text format)")
+	commentDangerFlag := fs.Float64("comment-danger-below", outputadapter.DefaultCommentDangerBelow, "Color comment density as a danger below this ratio (text format)")
+	indentFlag := fs.String("indent", "2", "JSON indentation: number of spaces, \"tab\" or \"none\"")
+	fieldsFlag := fs.String("fields", "", "Comma-separated JSON field paths to keep, e.g. files.path,files.functions.ccn,project (json format)")
+	filterFlag := fs.String("filter", "", "Only render files whose root-relative path (or a parent directory) matches this glob")
+	worstFlag := fs.Int("worst", 0, "Only render the N highest-complexity files")
+	failFlag := fs.Bool("fail", false, "With --worst, exit nonzero if any listed file has a function above --fail-ccn")
+	failCCNFlag := fs.Int("fail-ccn", 20, "Function CCN above which --worst --fail reports a failure")
+	var gateFlags stringList
+	fs.Var(&gateFlags, "gate", "Fail unless a project metric meets a threshold, e.g. public-doc-coverage>=80 (repeatable; metrics: "+strings.Join(usecase.GateMetricNames(), ", ")+"; percentages are 0-100)")
+	pagerFlag := fs.Bool("pager", false, "Always pipe text output through $PAGER when stdout is a terminal")
+	noPagerFlag := fs.Bool("no-pager", false, "Never pipe output through $PAGER")
+	runSummaryFlag := fs.String("run-summary", "", "Write a JSON run summary (files, errors, threshold result, exit code) to this path, even on failure")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
+	summary := infrastructure.NewRunSummaryWriter(*runSummaryFlag, "report")
+	defer func() { err = summary.Finish(err) }()
+
 	root := *pathFlag
 	if fs.NArg() > 0 {
 		root = fs.Arg(0)
 	}
 
+	indent, err := parseIndent(*indentFlag)
+	if err != nil {
+		return err
+	}
+
+	var jsonFields []string
+	if *fieldsFlag != "" {
+		jsonFields = strings.Split(*fieldsFlag, ",")
+		if err := outputadapter.ValidateJSONFields(jsonFields); err != nil {
+			return err
+		}
+	}
+
+	if *templateFlag == "" && strings.EqualFold(*formatFlag, "template") {
+		return fmt.Errorf("--format template requires --template <path>")
+	}
+	if (*failFlag || flagSet(fs, "fail-ccn")) && *worstFlag <= 0 {
+		return fmt.Errorf("--fail and --fail-ccn require --worst N with N > 0")
+	}
+
+	var gates []usecase.GateRule
+	for _, spec := range gateFlags {
+		rule, err := usecase.ParseGateRule(spec)
+		if err != nil {
+			return err
+		}
+		gates = append(gates, rule)
+	}
+
 	storage := infrastructure.NewFileStorage()
-	rendererRegistry := outputadapter.NewRendererRegistry(
-		outputadapter.NewTextRenderer(),
-		outputadapter.NewJSONRenderer(),
-	)
+	rendererRegistry := newRendererRegistry(rendererConfig{
+		text: outputadapter.TextRendererOptions{
+			Tree:             *treeFlag,
+			CompareToAverage: *compareFlag,
+			GroupByPackage:   *groupByPackageFlag,
+
+			ListDeclarationOnly: *listDeclOnlyFlag,
+
+			CommentWarnBelow:   *commentWarnFlag,
+			CommentDangerBelow: *commentDangerFlag,
+		},
+		jsonIndent: indent,
+		jsonFields: jsonFields,
+	})
+	if *templateFlag != "" {
+		src, err := os.ReadFile(*templateFlag)
+		if err != nil {
+			return fmt.Errorf("read template: %w", err)
+		}
+		tmplRenderer, err := outputadapter.NewTemplateRenderer(filepath.Base(*templateFlag), string(src))
+		if err != nil {
+			return err
+		}
+		if err := rendererRegistry.Register(tmplRenderer); err != nil {
+			return err
+		}
+	}
 	uc := usecase.NewGenerateReportUseCase(storage, rendererRegistry)
 
 	ctx := context.Background()
-	out, err := uc.Execute(ctx, usecase.GenerateReportRequest{
-		RootPath: root,
-		Format:   *formatFlag,
-	})
+	var failing []string
+	var worstErr error
+	if *worstFlag > 0 {
+		failCCN := 0
+		if *failFlag {
+			failCCN = *failCCNFlag
+		}
+		res, err := uc.ExecuteWorst(ctx, usecase.WorstFilesRequest{
+			RootPath: root,
+			Format:   *formatFlag,
+			Count:    *worstFlag,
+			FailCCN:  failCCN,
+			Filter:   *filterFlag,
+		})
+		if err != nil {
+			return err
+		}
+		summary.RecordFiles(len(res.Files))
+		if err := infrastructure.NewPager(pagerMode(*pagerFlag, *noPagerFlag, *formatFlag)).Print(res.Output); err != nil {
+			return err
+		}
+		failing = res.Failing
+		if len(res.Failing) > 0 {
+			worstErr = fmt.Errorf("%d file(s) have functions above CCN %d: %s",
+				len(res.Failing), failCCN, strings.Join(res.Failing, ", "))
+		}
+	} else {
+		out, err := uc.Execute(ctx, usecase.GenerateReportRequest{
+			RootPath: root,
+			Format:   *formatFlag,
+			Filter:   *filterFlag,
+		})
+		if err != nil {
+			return err
+		}
+		if err := infrastructure.NewPager(pagerMode(*pagerFlag, *noPagerFlag, *formatFlag)).Print(out); err != nil {
+			return err
+		}
+	}
+
+	var gateErr error
+	if len(gates) > 0 {
+		report, err := uc.Load(ctx, usecase.GenerateReportRequest{RootPath: root, Filter: *filterFlag})
+		if err != nil {
+			return err
+		}
+		gateFailing := usecase.EvaluateGates(report, gates)
+		failing = append(failing, gateFailing...)
+		if len(gateFailing) > 0 {
+			gateErr = fmt.Errorf("quality gate failed: %s", strings.Join(gateFailing, ", "))
+		}
+	}
+	if *failFlag || len(gates) > 0 {
+		summary.RecordThreshold(failing)
+	}
+	return errors.Join(worstErr, gateErr)
+}
+
+func printExplanation(exp *model.FunctionExplanation) {
+	fmt.Printf("%s:%d-%d %s\n", exp.FilePath, exp.StartLine, exp.EndLine, exp.Function)
+	fmt.Printf("  %6s %5s %5s  %s\n", "line", "ccn", "cog", "constructs")
+	fmt.Printf("  %6s %5d %5s  base\n", "-", exp.BaseCCN, "-")
+	for _, l := range exp.Lines {
+		fmt.Printf("  %6d %+5d %+5d  %-24s %s\n", l.Line, l.CCN, l.Cognitive, strings.Join(l.Constructs, " "), l.Code)
+	}
+	fmt.Printf("  %6s %5d %5d\n", "total", exp.CCN, exp.Cognitive)
+}
+
+func runTUI(args []string) error {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+	pathFlag := fs.String("path", ".", "Path to project root (can also be given as positional argument)")
+	filterFlag := fs.String("filter", "", "Only browse files whose root-relative path (or a parent directory) matches this glob")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	root := *pathFlag
+	if fs.NArg() > 0 {
+		root = fs.Arg(0)
+	}
+
+	uc := usecase.NewGenerateReportUseCase(infrastructure.NewFileStorage(), outputadapter.NewRendererRegistry())
+	report, err := uc.Load(context.Background(), usecase.GenerateReportRequest{RootPath: root, Filter: *filterFlag})
 	if err != nil {
 		return err
 	}
+	return tui.Run(report)
+}
+
+func runDoctor(args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	parsers := []ports.CodeParser{parser.NewGoParser(), parser.NewCppParser(), parser.NewCSharpParser(), parser.NewCParser()}
+	registry := newRendererRegistry(rendererConfig{jsonIndent: infrastructure.DefaultJSONIndent})
+	report := usecase.NewDoctorUseCase(parsers, registry, exec.LookPath).Execute(context.Background())
 
-	fmt.Println(out)
+	fmt.Printf("Go version: %s\n", report.GoVersion)
+	if report.GitPath != "" {
+		fmt.Printf("git:        %s\n", report.GitPath)
+	} else {
+		fmt.Printf("git:        not found (%s); git metrics will be empty\n", report.GitError)
+	}
+	fmt.Println("Parsers:")
+	for _, p := range report.Parsers {
+		fmt.Printf("  - %-8s %s\n", p.Name, strings.Join(p.Extensions, " "))
+	}
+	fmt.Printf("Renderers:  %s\n", strings.Join(report.Renderers, ", "))
+	if !report.Healthy() {
+		return fmt.Errorf("self-check failed: %s", report.SampleError)
+	}
+	fmt.Printf("Self-check: ok (%d files, %d functions)\n", report.SampleFiles, report.SampleFunctions)
 	return nil
 }
 
@@ -190,6 +725,67 @@ func runMetrics(args []string) error {
 	return nil
 }
 
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ";")
+}
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+type rendererConfig struct {
+	text       outputadapter.TextRendererOptions
+	jsonIndent string
+	jsonFields []string
+}
+
+func newRendererRegistry(cfg rendererConfig) *outputadapter.RendererRegistry {
+	return outputadapter.NewRendererRegistry(
+		outputadapter.NewTextRendererWithOptions(cfg.text),
+		outputadapter.NewJSONRendererWithOptions(outputadapter.JSONRendererOptions{
+			Indent: cfg.jsonIndent,
+			Fields: cfg.jsonFields,
+		}),
+		outputadapter.NewScatterRenderer(),
+		outputadapter.NewGitLabRenderer(),
+		outputadapter.NewStableCSVRenderer(),
+	)
+}
+
+func pagerMode(always, never bool, format string) infrastructure.PagerMode {
+	switch {
+	case never || !strings.EqualFold(format, "text"):
+		return infrastructure.PagerNever
+	case always:
+		return infrastructure.PagerAlways
+	default:
+		return infrastructure.PagerAuto
+	}
+}
+
+func flagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func parseList(s string) []string {
+	items := []string{}
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			items = append(items, p)
+		}
+	}
+	return items
+}
+
 func parseExts(s string) []string {
 	parts := strings.Split(s, ",")
 	var exts []string
@@ -205,3 +801,17 @@ func parseExts(s string) []string {
 	}
 	return exts
 }
+
+func parseIndent(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "tab", "\\t":
+		return "\t", nil
+	case "none", "", "0":
+		return "", nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 || n > 16 {
+		return "", fmt.Errorf("invalid --indent %q: want a number of spaces (0-16), \"tab\" or \"none\"", s)
+	}
+	return strings.Repeat(" ", n), nil
+}
@@ -7,7 +7,12 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"fmt"
+	"net/url"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -15,16 +20,41 @@ import (
 	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
 )
 
+const (
+	commitMarker  = "\x1ecommit:"
+	bodyEndMarker = "\x1f"
+)
+
+var (
+	fixReferenceRe = regexp.MustCompile(`(?i)\b(fix(es|ed)?|close[sd]?|resolve[sd]?)\b:?\s+(#\d+|[A-Z][A-Z0-9]+-\d+|https?://\S+)`)
+	fixTrailerRe   = regexp.MustCompile(`(?im)^fixes:\s*\S+`)
+	issueLinkRe    = regexp.MustCompile(`https?://\S+/(issues|bugs?)/\d+`)
+)
+
 type GitCLI struct{}
 
 func NewGitCLI() *GitCLI {
 	return &GitCLI{}
 }
 
-var _ ports.GitClient = (*GitCLI)(nil)
+var (
+	_ ports.GitClient              = (*GitCLI)(nil)
+	_ ports.DirtyFileLister        = (*GitCLI)(nil)
+	_ ports.CoChangeCollector      = (*GitCLI)(nil)
+	_ ports.AuthorMetricsCollector = (*GitCLI)(nil)
+)
 
 func (g *GitCLI) CollectFileMetrics(ctx context.Context, root string) (map[string]*model.GitFileMetrics, error) {
-	cmd := exec.CommandContext(ctx, "git", "-C", root, "log", "--numstat", "--format=commit:%H:%an:%s")
+	return g.collectFileMetrics(ctx, root, false)
+}
+
+func (g *GitCLI) CollectFileMetricsWithAuthors(ctx context.Context, root string) (map[string]*model.GitFileMetrics, error) {
+	return g.collectFileMetrics(ctx, root, true)
+}
+
+func (g *GitCLI) collectFileMetrics(ctx context.Context, root string, withAuthors bool) (map[string]*model.GitFileMetrics, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", root, "log", "--numstat", "--relative",
+		"--format="+commitMarker+"%H:%an:%s%n%b"+bodyEndMarker)
 	out, err := cmd.Output()
 	if err != nil {
 		return map[string]*model.GitFileMetrics{}, nil
@@ -33,25 +63,42 @@ func (g *GitCLI) CollectFileMetrics(ctx context.Context, root string) (map[strin
 	type agg struct {
 		added, deleted, commits, bugfixCommits int
 		authors                                map[string]struct{}
+		addedByAuthor                          map[string]int
 	}
 
 	aggs := make(map[string]*agg)
 	var currentAuthor string
 	var currentSubject string
+	var body strings.Builder
+	var inBody bool
 	var isBugfix bool
 
 	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 	for scanner.Scan() {
 		line := scanner.Text()
-		if strings.HasPrefix(line, "commit:") {
-			parts := strings.SplitN(line, ":", 4)
-			if len(parts) >= 4 {
-				currentAuthor = parts[2]
-				currentSubject = parts[3]
-				lower := strings.ToLower(currentSubject)
-				isBugfix = strings.Contains(lower, "fix") ||
-					strings.Contains(lower, "bug") ||
-					strings.Contains(lower, "issue")
+		if strings.HasPrefix(line, commitMarker) {
+			parts := strings.SplitN(strings.TrimPrefix(line, commitMarker), ":", 3)
+			currentAuthor = ""
+			currentSubject = ""
+			if len(parts) == 3 {
+				currentAuthor = parts[1]
+				currentSubject = parts[2]
+			}
+			body.Reset()
+			inBody = true
+			isBugfix = false
+		}
+
+		if inBody {
+			if !strings.HasPrefix(line, commitMarker) {
+				text, _, _ := strings.Cut(line, bodyEndMarker)
+				body.WriteString(text)
+				body.WriteByte('\n')
+			}
+			if strings.Contains(line, bodyEndMarker) {
+				inBody = false
+				isBugfix = isBugfixCommit(currentSubject, body.String())
 			}
 			continue
 		}
@@ -73,6 +120,9 @@ func (g *GitCLI) CollectFileMetrics(ctx context.Context, root string) (map[strin
 		a := aggs[path]
 		if a == nil {
 			a = &agg{authors: make(map[string]struct{})}
+			if withAuthors {
+				a.addedByAuthor = make(map[string]int)
+			}
 			aggs[path] = a
 		}
 		a.added += added
@@ -80,6 +130,9 @@ func (g *GitCLI) CollectFileMetrics(ctx context.Context, root string) (map[strin
 		a.commits++
 		if currentAuthor != "" {
 			a.authors[currentAuthor] = struct{}{}
+			if withAuthors {
+				a.addedByAuthor[currentAuthor] += added
+			}
 		}
 		if isBugfix {
 			a.bugfixCommits++
@@ -89,13 +142,120 @@ func (g *GitCLI) CollectFileMetrics(ctx context.Context, root string) (map[strin
 	result := make(map[string]*model.GitFileMetrics, len(aggs))
 	for path, a := range aggs {
 		result[path] = &model.GitFileMetrics{
-			FilePath:      path,
-			LinesAdded:    a.added,
-			LinesDeleted:  a.deleted,
-			Commits:       a.commits,
-			BugfixCommits: a.bugfixCommits,
-			Authors:       len(a.authors),
+			FilePath:       path,
+			LinesAdded:     a.added,
+			LinesDeleted:   a.deleted,
+			Commits:        a.commits,
+			BugfixCommits:  a.bugfixCommits,
+			Authors:        len(a.authors),
+			DominantAuthor: dominantAuthor(a.addedByAuthor),
 		}
 	}
 	return result, nil
 }
+
+func (g *GitCLI) CollectCommitFiles(ctx context.Context, root string) ([][]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", root, "log", "--name-only", "--relative", "--no-renames",
+		"--format="+commitMarker+"%H")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+
+	var commits [][]string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, commitMarker):
+			commits = append(commits, nil)
+		case line == "" || len(commits) == 0:
+		default:
+			commits[len(commits)-1] = append(commits[len(commits)-1], line)
+		}
+	}
+	return commits, nil
+}
+
+func (g *GitCLI) DirtyFiles(ctx context.Context, root string) ([]string, error) {
+	top, err := exec.CommandContext(ctx, "git", "-C", root, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, fmt.Errorf("not a git work tree: %w", err)
+	}
+	toplevel := strings.TrimSpace(string(top))
+
+	out, err := exec.CommandContext(ctx, "git", "-C", root, "status", "--porcelain", "-z", "--untracked-files=all").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status: %w", err)
+	}
+
+	var paths []string
+	entries := strings.Split(string(out), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		status, path := entry[:2], entry[3:]
+		if status[0] == 'R' || status[0] == 'C' {
+			i++
+		}
+		if strings.ContainsRune(status, 'D') {
+			continue
+		}
+		paths = append(paths, filepath.Join(toplevel, filepath.FromSlash(path)))
+	}
+	return paths, nil
+}
+
+func (g *GitCLI) Clone(ctx context.Context, repoURL, rev string, depth int, dest string) error {
+	args := []string{"clone", "--quiet"}
+	if depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(depth))
+	}
+	if rev != "" {
+		args = append(args, "--branch", rev)
+	}
+	args = append(args, "--", repoURL, dest)
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git clone %s: %w: %s", redactURL(repoURL), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	u.User = url.User("redacted")
+	return u.String()
+}
+
+func dominantAuthor(addedByAuthor map[string]int) string {
+	best := ""
+	bestLines := -1
+	for author, lines := range addedByAuthor {
+		if lines > bestLines || (lines == bestLines && author < best) {
+			best = author
+			bestLines = lines
+		}
+	}
+	return best
+}
+
+func isBugfixCommit(subject, body string) bool {
+	lower := strings.ToLower(subject)
+	if strings.Contains(lower, "fix") ||
+		strings.Contains(lower, "bug") ||
+		strings.Contains(lower, "issue") {
+		return true
+	}
+	return fixReferenceRe.MatchString(body) ||
+		fixTrailerRe.MatchString(body) ||
+		issueLinkRe.MatchString(body)
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package output
+
+import (
+	"encoding/csv"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+)
+
+var stableCSVHeader = []string{
+	"path", "function", "language", "nloc", "ccn", "cognitive", "max_nesting", "parameters", "fan_in", "fan_out",
+}
+
+type stableCSVRow struct {
+	path      string
+	startLine int
+	fields    []string
+}
+
+type StableCSVRenderer struct{}
+
+func NewStableCSVRenderer() *StableCSVRenderer {
+	return &StableCSVRenderer{}
+}
+
+var _ ports.OutputRenderer = (*StableCSVRenderer)(nil)
+
+func (r *StableCSVRenderer) Format() string {
+	return "csv-stable"
+}
+
+func (r *StableCSVRenderer) Render(report *model.ProjectReport) (string, error) {
+	var rows []stableCSVRow
+	for _, f := range report.Files {
+		path := relativeReportPath(report.RootPath, f.Path)
+		for _, fn := range f.Functions {
+			rows = append(rows, stableCSVRow{
+				path:      path,
+				startLine: fn.StartLine,
+				fields: []string{
+					path,
+					fn.Name,
+					string(fn.Language),
+					strconv.Itoa(fn.NLOC),
+					strconv.Itoa(fn.CCN),
+					strconv.Itoa(fn.CognitiveComplexity),
+					strconv.Itoa(fn.MaxNesting),
+					strconv.Itoa(fn.Parameters),
+					strconv.Itoa(fn.FanIn),
+					strconv.Itoa(fn.FanOut),
+				},
+			})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.path != b.path {
+			return a.path < b.path
+		}
+		if a.fields[1] != b.fields[1] {
+			return a.fields[1] < b.fields[1]
+		}
+		return a.startLine < b.startLine
+	})
+
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if err := w.Write(stableCSVHeader); err != nil {
+		return "", err
+	}
+	for _, row := range rows {
+		if err := w.Write(row.fields); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+)
+
+type gitLabLines struct {
+	Begin int `json:"begin"`
+}
+
+type gitLabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitLabLines `json:"lines"`
+}
+
+type gitLabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitLabLocation `json:"location"`
+}
+
+type GitLabRenderer struct {
+	indent string
+}
+
+func NewGitLabRenderer() *GitLabRenderer {
+	return &GitLabRenderer{indent: "  "}
+}
+
+var _ ports.OutputRenderer = (*GitLabRenderer)(nil)
+
+func (r *GitLabRenderer) Format() string {
+	return "gitlab"
+}
+
+func (r *GitLabRenderer) Render(report *model.ProjectReport) (string, error) {
+	issues := []gitLabIssue{}
+	seen := make(map[string]int)
+	for _, f := range report.Files {
+		for _, smell := range f.Smells {
+			path := relativeReportPath(report.RootPath, smell.FilePath)
+			if path == "" {
+				path = relativeReportPath(report.RootPath, f.Path)
+			}
+			line := smell.Line
+			if line < 1 {
+				line = 1
+			}
+
+			key := strings.Join([]string{string(smell.Kind), path, smell.Function}, "\x00")
+			if smell.Function == "" {
+				key += fmt.Sprintf("\x00%d", line)
+			}
+			seen[key]++
+			if n := seen[key]; n > 1 {
+				key += fmt.Sprintf("\x00#%d", n)
+			}
+			sum := sha256.Sum256([]byte(key))
+
+			description := smell.Description
+			if smell.Function != "" {
+				description = fmt.Sprintf("%s: %s", smell.Function, smell.Description)
+			}
+			issues = append(issues, gitLabIssue{
+				Description: description,
+				CheckName:   string(smell.Kind),
+				Fingerprint: hex.EncodeToString(sum[:]),
+				Severity:    gitLabSeverity(model.SmellSeverity(smell.Kind)),
+				Location:    gitLabLocation{Path: path, Lines: gitLabLines{Begin: line}},
+			})
+		}
+	}
+
+	data, err := json.MarshalIndent(issues, "", r.indent)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func gitLabSeverity(sev model.IssueSeverity) string {
+	switch sev {
+	case model.SeverityError:
+		return "major"
+	case model.SeverityInfo:
+		return "info"
+	default:
+		return "minor"
+	}
+}
+
+func relativeReportPath(root, path string) string {
+	if path == "" {
+		return ""
+	}
+	if root != "" {
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
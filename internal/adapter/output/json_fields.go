@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+type fieldNode map[string]fieldNode
+
+func ValidateJSONFields(fields []string) error {
+	_, err := buildFieldTree(fields)
+	return err
+}
+
+func buildFieldTree(fields []string) (fieldNode, error) {
+	root := fieldNode{}
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if err := validateFieldPath(field); err != nil {
+			return nil, err
+		}
+
+		node := root
+		parts := strings.Split(field, ".")
+		for i, part := range parts {
+			child, seen := node[part]
+			if seen && child == nil {
+				break
+			}
+			if i == len(parts)-1 {
+				node[part] = nil
+				break
+			}
+			if child == nil {
+				child = fieldNode{}
+				node[part] = child
+			}
+			node = child
+		}
+	}
+	if len(root) == 0 {
+		return nil, fmt.Errorf("no JSON fields selected")
+	}
+	return root, nil
+}
+
+func validateFieldPath(field string) error {
+	t := reflect.TypeOf(model.ProjectReport{})
+	for _, part := range strings.Split(field, ".") {
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Map {
+			return nil
+		}
+		if t.Kind() != reflect.Struct {
+			return fmt.Errorf("invalid field %q: %s has no sub-fields", field, part)
+		}
+		next, ok := jsonField(t, part)
+		if !ok {
+			return fmt.Errorf("invalid field %q: unknown key %q", field, part)
+		}
+		t = next
+	}
+	return nil
+}
+
+func jsonField(t reflect.Type, name string) (reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if tag == name {
+			return f.Type, true
+		}
+	}
+	return nil, false
+}
+
+func projectReport(report *model.ProjectReport, fields []string) (any, error) {
+	tree, err := buildFieldTree(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return projectValue(generic, tree), nil
+}
+
+func projectValue(v any, node fieldNode) any {
+	if node == nil {
+		return v
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(node))
+		for key, child := range node {
+			if inner, ok := val[key]; ok {
+				out[key] = projectValue(inner, child)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = projectValue(item, node)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -10,10 +10,26 @@ import (
 	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
 )
 
-type JSONRenderer struct{}
+type JSONRendererOptions struct {
+	Indent string
+	Fields []string
+}
+
+type JSONRenderer struct {
+	indent string
+	fields []string
+}
 
 func NewJSONRenderer() *JSONRenderer {
-	return &JSONRenderer{}
+	return &JSONRenderer{indent: "  "}
+}
+
+func NewJSONRendererWithIndent(indent string) *JSONRenderer {
+	return &JSONRenderer{indent: indent}
+}
+
+func NewJSONRendererWithOptions(opts JSONRendererOptions) *JSONRenderer {
+	return &JSONRenderer{indent: opts.Indent, fields: opts.Fields}
 }
 
 var _ ports.OutputRenderer = (*JSONRenderer)(nil)
@@ -23,7 +39,22 @@ func (r *JSONRenderer) Format() string {
 }
 
 func (r *JSONRenderer) Render(report *model.ProjectReport) (string, error) {
-	data, err := json.MarshalIndent(report, "", "  ")
+	var payload any = report
+	if len(r.fields) > 0 {
+		projected, err := projectReport(report, r.fields)
+		if err != nil {
+			return "", err
+		}
+		payload = projected
+	}
+
+	var data []byte
+	var err error
+	if r.indent == "" {
+		data, err = json.Marshal(payload)
+	} else {
+		data, err = json.MarshalIndent(payload, "", r.indent)
+	}
 	if err != nil {
 		return "", err
 	}
@@ -4,6 +4,8 @@
 package output
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
@@ -35,10 +37,28 @@ func (r *RendererRegistry) Get(format string) (ports.OutputRenderer, bool) {
 	return out, ok
 }
 
+func (r *RendererRegistry) Register(renderer ports.OutputRenderer) error {
+	if renderer == nil {
+		return fmt.Errorf("register renderer: nil renderer")
+	}
+	f := strings.ToLower(renderer.Format())
+	if f == "" {
+		return fmt.Errorf("register renderer: empty format")
+	}
+	if _, exists := r.byFormat[f]; exists {
+		return fmt.Errorf("register renderer: format %q already registered", f)
+	}
+	r.byFormat[f] = renderer
+	return nil
+}
+
 func (r *RendererRegistry) List() []ports.OutputRenderer {
 	out := make([]ports.OutputRenderer, 0, len(r.byFormat))
 	for _, v := range r.byFormat {
 		out = append(out, v)
 	}
+	sort.Slice(out, func(i, j int) bool {
+		return strings.ToLower(out[i].Format()) < strings.ToLower(out[j].Format())
+	})
 	return out
 }
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package output
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+)
+
+type ScatterPoint struct {
+	Path       string `json:"path"`
+	Complexity int    `json:"complexity"`
+	Churn      int    `json:"churn"`
+	Smells     int    `json:"smells"`
+}
+
+type ScatterData struct {
+	MedianComplexity float64        `json:"medianComplexity"`
+	MedianChurn      float64        `json:"medianChurn"`
+	Points           []ScatterPoint `json:"points"`
+}
+
+type ScatterRenderer struct {
+	indent string
+}
+
+func NewScatterRenderer() *ScatterRenderer {
+	return &ScatterRenderer{indent: "  "}
+}
+
+var _ ports.OutputRenderer = (*ScatterRenderer)(nil)
+
+func (r *ScatterRenderer) Format() string {
+	return "scatter"
+}
+
+func (r *ScatterRenderer) Render(report *model.ProjectReport) (string, error) {
+	data, err := json.MarshalIndent(BuildScatterData(report), "", r.indent)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func BuildScatterData(report *model.ProjectReport) ScatterData {
+	out := ScatterData{Points: []ScatterPoint{}}
+	var complexity, churn []int
+	for _, f := range report.Files {
+		if f.Git == nil {
+			continue
+		}
+		p := ScatterPoint{
+			Path:       f.Path,
+			Complexity: f.Summary.CCNTotal,
+			Churn:      f.Git.LinesAdded + f.Git.LinesDeleted,
+			Smells:     len(f.Smells),
+		}
+		out.Points = append(out.Points, p)
+		complexity = append(complexity, p.Complexity)
+		churn = append(churn, p.Churn)
+	}
+	out.MedianComplexity = medianInt(complexity)
+	out.MedianChurn = medianInt(churn)
+	return out
+}
+
+func medianInt(values []int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return float64(sorted[mid])
+	}
+	return float64(sorted[mid-1]+sorted[mid]) / 2.0
+}
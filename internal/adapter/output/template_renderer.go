@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package output
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+)
+
+type TemplateRenderer struct {
+	tmpl *template.Template
+}
+
+func NewTemplateRenderer(name, src string) (*TemplateRenderer, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs()).Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("parse template %s: %w", name, err)
+	}
+	return &TemplateRenderer{tmpl: tmpl}, nil
+}
+
+var _ ports.OutputRenderer = (*TemplateRenderer)(nil)
+
+func (r *TemplateRenderer) Format() string {
+	return "template"
+}
+
+func (r *TemplateRenderer) Render(report *model.ProjectReport) (string, error) {
+	var b strings.Builder
+	if err := r.tmpl.Execute(&b, report); err != nil {
+		return "", fmt.Errorf("execute template %s: %w", r.tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
+
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"pct": func(ratio float64) string {
+			return fmt.Sprintf("%.1f%%", ratio*100)
+		},
+		"title":      title,
+		"accent":     accent,
+		"label":      label,
+		"value":      value,
+		"colorCCN":   colorCCNInt,
+		"colorCCNf":  colorCCNFloat,
+		"colorRisk":  colorRiskPct,
+		"colorScore": colorHotspot,
+		"trimPath":   trimPath,
+		"truncate":   truncate,
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"join":       strings.Join,
+		"repeat":     strings.Repeat,
+	}
+}
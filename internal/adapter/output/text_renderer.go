@@ -5,7 +5,10 @@ package output
 
 import (
 	"fmt"
+	"math"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,10 +33,38 @@ const (
 	colFunc = "\033[38;5;150m"
 )
 
-type TextRenderer struct{}
+type TextRendererOptions struct {
+	Tree             bool
+	CompareToAverage bool
+	GroupByPackage   bool
+
+	ListDeclarationOnly bool
+
+	CommentWarnBelow   float64
+	CommentDangerBelow float64
+}
+
+const (
+	DefaultCommentWarnBelow   = 0.10
+	DefaultCommentDangerBelow = 0.05
+)
+
+type TextRenderer struct {
+	opts TextRendererOptions
+}
 
 func NewTextRenderer() *TextRenderer {
-	return &TextRenderer{}
+	return NewTextRendererWithOptions(TextRendererOptions{})
+}
+
+func NewTextRendererWithOptions(opts TextRendererOptions) *TextRenderer {
+	if opts.CommentWarnBelow <= 0 {
+		opts.CommentWarnBelow = DefaultCommentWarnBelow
+	}
+	if opts.CommentDangerBelow <= 0 {
+		opts.CommentDangerBelow = DefaultCommentDangerBelow
+	}
+	return &TextRenderer{opts: opts}
 }
 
 var _ ports.OutputRenderer = (*TextRenderer)(nil)
@@ -48,10 +79,22 @@ func (r *TextRenderer) Render(report *model.ProjectReport) (string, error) {
 	fmt.Fprintf(&b, "%s\n", accent("CodeAudit Report"))
 	fmt.Fprintf(&b, "%s %s\n", label("Root:"), value(report.RootPath))
 	fmt.Fprintf(&b, "%s %s\n", label("Generated at:"), value(report.GeneratedAt.Format(time.RFC3339)))
+	if ci := report.CI; ci != nil {
+		fmt.Fprintf(&b, "%s %s\n", label("CI:"), value(ciSummary(ci)))
+	}
+	if report.Filter != nil {
+		fmt.Fprintf(&b, "%s %s\n", label("Filtered:"), value(fmt.Sprintf("%s (%d of %d files, aggregates recomputed)",
+			report.Filter.Glob, report.Filter.Matched, report.Filter.TotalFiles)))
+	}
 
 	fmt.Fprintf(&b, "\n%s\n", title("== Project Summary =="))
-	fmt.Fprintf(&b, "%s %s\n", label("Files:"), value(fmt.Sprintf("%d", report.Project.TotalFiles)))
+	fmt.Fprintf(&b, "%s %s\n", label("Files:"), value(filesSummary(report.Project)))
 	fmt.Fprintf(&b, "%s %s\n", label("Functions:"), value(fmt.Sprintf("%d", report.Project.TotalFunctions)))
+	if h := report.Health; h != nil {
+		fmt.Fprintf(&b, "%s %s %s\n", label("Health grade:"), value(fmt.Sprintf("%s (%.0f/100)", h.Grade, h.Score)),
+			label(fmt.Sprintf("(complexity %.0f, size %.0f; cyclomatic/cognitive weights %.2f/%.2f)",
+				h.Complexity, h.Size, h.CyclomaticWeight, h.CognitiveWeight)))
+	}
 	fmt.Fprintf(&b, "%s %s\n", label("Avg CCN / function:"), colorCCNFloat(report.Project.AvgCCNPerFunction))
 	fmt.Fprintf(&b, "%s %s\n", label("Max CCN / function:"), colorCCNInt(report.Project.MaxCCNPerFunction))
 	fmt.Fprintf(&b, "%s %s\n", label("Functions CCN>10:"), colorRiskPct(report.Project.FunctionsCCNGt10Pct*100))
@@ -69,7 +112,24 @@ func (r *TextRenderer) Render(report *model.ProjectReport) (string, error) {
 		)),
 	)
 	fmt.Fprintf(&b, "%s %s\n", label("Avg params / function:"), value(fmt.Sprintf("%.2f", report.Project.AvgParamsPerFunction)))
-	fmt.Fprintf(&b, "%s %s\n", label("Comment density (avg):"), value(fmt.Sprintf("%.1f%%", report.Project.CommentDensityAvg*100)))
+	fmt.Fprintf(
+		&b,
+		"%s %s %s\n",
+		label("Comment density:"),
+		r.colorCommentField(fmt.Sprintf("%.1f%%", report.Project.CommentDensityWeighted*100), report.Project.CommentDensityWeighted),
+		label(fmt.Sprintf("(per-file avg %.1f%%)", report.Project.CommentDensityAvg*100)),
+	)
+	if report.Project.PublicAPISymbols > 0 {
+		fmt.Fprintf(
+			&b,
+			"%s %s\n",
+			label("Public API:"),
+			value(fmt.Sprintf("%d symbols, %.0f%% documented",
+				report.Project.PublicAPISymbols,
+				report.Project.PublicAPIDocPct*100,
+			)),
+		)
+	}
 	fmt.Fprintf(
 		&b,
 		"%s %s\n",
@@ -81,6 +141,54 @@ func (r *TextRenderer) Render(report *model.ProjectReport) (string, error) {
 		)),
 	)
 
+	if d := report.Delta; d != nil {
+		source := d.BaselineGeneratedAt.Format(time.RFC3339)
+		if d.BaselinePath != "" {
+			source = d.BaselinePath + ", " + source
+		}
+		fmt.Fprintf(&b, "\n%s\n", title(fmt.Sprintf("== Delta vs baseline (%s) ==", source)))
+		for _, row := range []struct {
+			name   string
+			format string
+			delta  model.MetricDelta
+		}{
+			{"Files:", "%.0f", d.Files},
+			{"Functions:", "%.0f", d.Functions},
+			{"Avg CCN / function:", "%.2f", d.AvgCCNPerFunction},
+			{"Max CCN / function:", "%.0f", d.MaxCCNPerFunction},
+			{"Smells:", "%.0f", d.Smells},
+		} {
+			fmt.Fprintf(&b, "%s %s\n", label(fmt.Sprintf("%-20s", row.name)), value(fmt.Sprintf(
+				row.format+" -> "+row.format+" (%+"+row.format[1:]+")",
+				row.delta.Baseline, row.delta.Current, row.delta.Change)))
+		}
+	}
+
+	if len(report.Project.SmellCountsByKind) > 0 {
+		type smellCount struct {
+			Kind  model.CodeSmellKind
+			Count int
+		}
+		var counts []smellCount
+		for kind, n := range report.Project.SmellCountsByKind {
+			counts = append(counts, smellCount{Kind: kind, Count: n})
+		}
+		sort.Slice(counts, func(i, j int) bool {
+			if counts[i].Count == counts[j].Count {
+				return counts[i].Kind < counts[j].Kind
+			}
+			return counts[i].Count > counts[j].Count
+		})
+
+		fmt.Fprintf(&b, "\n%s\n", title("== Smells by kind =="))
+		for _, c := range counts {
+			fmt.Fprintf(&b, "%s %s\n", label(fmt.Sprintf("%-24s", string(c.Kind)+":")), value(fmt.Sprintf("%d", c.Count)))
+			if fix := model.SmellRemediation(c.Kind); fix != "" {
+				fmt.Fprintf(&b, "    fix: %s\n", fix)
+			}
+		}
+	}
+
 	if len(report.Hotspots) > 0 {
 		fmt.Fprintf(&b, "\n%s\n", title("== Top Hotspots (complexity × churn) =="))
 		for i, h := range report.Hotspots {
@@ -120,25 +228,176 @@ func (r *TextRenderer) Render(report *model.ProjectReport) (string, error) {
 			ccnRaw := fmt.Sprintf("%4d", f.Summary.CCNTotal)
 			ccnField := colorCCNField(ccnRaw, f.Summary.CCNTotal)
 
+			longest := ""
+			if f.Summary.LongestFunctionName != "" {
+				longest = fmt.Sprintf("  longest=%s (%d)", truncate(f.Summary.LongestFunctionName, 30), f.Summary.LongestFunctionNLOC)
+			}
+
+			cmtField := r.colorCommentField(fmt.Sprintf("%5.1f%%", f.Comments.CommentDensity*100), f.Comments.CommentDensity)
+
 			fmt.Fprintf(
 				&b,
-				"%s %-40s CCN=%s  NLOC=%5d  funcs=%3d\n",
+				"%s %-40s CCN=%s  NLOC=%5d  lines=%5s  funcs=%3d  cmt=%s%s\n",
 				label(idx),
 				trimPath(f.Path, 40),
 				ccnField,
 				f.Summary.NLOC,
+				fileLines(f),
 				f.Summary.FunctionsCount,
+				cmtField,
+				longest,
+			)
+		}
+	}
+
+	if len(report.Directories) > 0 {
+		dirs := append([]model.DirectoryMetrics(nil), report.Directories...)
+		sort.SliceStable(dirs, func(i, j int) bool {
+			return dirs[i].CCNTotal > dirs[j].CCNTotal
+		})
+
+		dirLimit := maxFiles
+		if len(dirs) < dirLimit {
+			dirLimit = len(dirs)
+		}
+
+		fmt.Fprintf(&b, "\n%s\n", title(fmt.Sprintf("== Directories by total complexity (top %d) ==", dirLimit)))
+		for i := 0; i < dirLimit; i++ {
+			d := dirs[i]
+
+			idx := fmt.Sprintf("%2d.", i+1)
+			ccnRaw := fmt.Sprintf("%4d", d.CCNTotal)
+			ccnField := colorCCNField(ccnRaw, d.CCNTotal)
+
+			fmt.Fprintf(
+				&b,
+				"%s %-40s CCN=%s  files=%3d  funcs=%3d  avg fn size=%6.1f  smells=%3d\n",
+				label(idx),
+				trimPath(d.Path, 40),
+				ccnField,
+				d.Files,
+				d.Functions,
+				d.AvgFunctionNLOC,
+				d.Smells,
+			)
+		}
+	}
+
+	if r.opts.GroupByPackage {
+		renderPackageGroups(&b, report.RootPath, report.Files)
+	}
+
+	if len(report.Packages) > 1 {
+		pkgLimit := maxFiles
+		if len(report.Packages) < pkgLimit {
+			pkgLimit = len(report.Packages)
+		}
+
+		fmt.Fprintf(&b, "\n%s\n", title(fmt.Sprintf("== Most unstable packages (top %d) ==", pkgLimit)))
+		for i := 0; i < pkgLimit; i++ {
+			p := report.Packages[i]
+
+			distance := "   -"
+			if p.Distance != nil {
+				distance = fmt.Sprintf("%.2f", *p.Distance)
+			}
+			instability, bar := "   -", instabilityBar(0)
+			if p.Instability != nil {
+				instability, bar = fmt.Sprintf("%.2f", *p.Instability), instabilityBar(*p.Instability)
+			}
+			fmt.Fprintf(
+				&b,
+				"%s %-40s I=%s %s  Ca=%3d  Ce=%3d  D=%s\n",
+				label(fmt.Sprintf("%2d.", i+1)),
+				trimPath(p.Path, 40),
+				instability,
+				bar,
+				p.Afferent,
+				p.Efferent,
+				distance,
 			)
 		}
 	}
 
+	if len(report.AuthorComplexity) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", title("== Complexity by dominant author =="))
+		for i, a := range report.AuthorComplexity {
+			ccnRaw := fmt.Sprintf("%4d", a.CCNTotal)
+			fmt.Fprintf(
+				&b,
+				"%s %-30s CCN=%s  files=%3d  funcs=%3d\n",
+				label(fmt.Sprintf("%2d.", i+1)),
+				truncate(a.Author, 30),
+				colorCCNField(ccnRaw, a.CCNTotal),
+				a.Files,
+				a.Functions,
+			)
+		}
+	}
+
+	if len(report.CoChanges) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", title("== Files that change together =="))
+		for i, c := range report.CoChanges {
+			fmt.Fprintf(
+				&b,
+				"%s %-35s %s %-35s commits=%3d  degree=%3.0f%%\n",
+				label(fmt.Sprintf("%2d.", i+1)),
+				trimPath(c.FileA, 35),
+				colMuted+"<->"+ansiReset,
+				trimPath(c.FileB, 35),
+				c.Commits,
+				c.Degree*100,
+			)
+		}
+	}
+
+	if r.opts.Tree {
+		renderFunctionTree(&b, report.Files)
+	} else {
+		renderFunctionTable(&b, report.Files, r.opts.CompareToAverage)
+	}
+
+	if r.opts.ListDeclarationOnly && report.Project.DeclarationOnlyFiles > 0 {
+		fmt.Fprintf(&b, "\n%s\n", title("== Declaration-only files (no functions) =="))
+		for _, f := range report.Files {
+			if len(f.Functions) == 0 {
+				fmt.Fprintf(&b, "  %s\n", value(f.Path))
+			}
+		}
+	}
+
+	if len(report.Warnings) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", title("== Warnings =="))
+		for _, w := range report.Warnings {
+			fmt.Fprintf(&b, "%s %s\n", warnBullet("-"), warnText(w))
+		}
+	}
+
+	if len(report.SkippedFiles) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", title("== Skipped files =="))
+		for _, sf := range report.SkippedFiles {
+			fmt.Fprintf(&b, "%s %s: %s\n", warnBullet("-"), value(sf.Path), warnText(sf.Reason))
+		}
+	}
+
+	if st := report.Stats; st != nil {
+		fmt.Fprintf(&b, "\n%s\n", title("== Run stats =="))
+		fmt.Fprintf(&b, "%s %s\n", label("Wall time:"), value(fmt.Sprintf("%d ms", st.WallTimeMillis)))
+		fmt.Fprintf(&b, "%s %s\n", label("Throughput:"), value(fmt.Sprintf("%d files, %.1f files/s", st.FilesAnalyzed, st.FilesPerSecond)))
+		fmt.Fprintf(&b, "%s %s\n", label("Peak heap:"), value(fmt.Sprintf("%.1f MiB", float64(st.PeakHeapBytes)/(1<<20))))
+	}
+
+	return b.String(), nil
+}
+
+func renderFunctionTable(b *strings.Builder, files []model.FileMetrics, compare bool) {
 	type functionRow struct {
 		File string
 		Fn   model.FunctionMetrics
 	}
 
 	var rows []functionRow
-	for _, f := range report.Files {
+	for _, f := range files {
 		for _, fn := range f.Functions {
 			rows = append(rows, functionRow{
 				File: f.Path,
@@ -156,7 +415,7 @@ func (r *TextRenderer) Render(report *model.ProjectReport) (string, error) {
 			return ci > cj
 		})
 
-		fmt.Fprintf(&b, "\n%s\n", title("== Function metrics (per function) =="))
+		fmt.Fprintf(b, "\n%s\n", title("== Function metrics (per function) =="))
 
 		header := fmt.Sprintf(
 			"%-40s %-30s %6s %6s %6s %6s %6s %6s %7s %7s %7s %6s %6s %8s",
@@ -166,8 +425,11 @@ func (r *TextRenderer) Render(report *model.ProjectReport) (string, error) {
 			"LStart", "LEnd", "Cmt%%",
 			"Fin", "Fout", "Hotspot",
 		)
-		fmt.Fprintln(&b, colMuted+header+ansiReset)
-		fmt.Fprintln(&b, colMuted+strings.Repeat("-", len(header))+ansiReset)
+		if compare {
+			header += fmt.Sprintf(" %-9s", "vsAvg C/S")
+		}
+		fmt.Fprintln(b, colMuted+header+ansiReset)
+		fmt.Fprintln(b, colMuted+strings.Repeat("-", len(header))+ansiReset)
 
 		for _, row := range rows {
 			fn := row.Fn
@@ -195,9 +457,14 @@ func (r *TextRenderer) Render(report *model.ProjectReport) (string, error) {
 			cogField := colorCOGField(cogRaw, fn.CognitiveComplexity)
 			hotField := colorHotspotField(hotRaw, fn.HotspotScore)
 
+			vsAvg := ""
+			if compare {
+				vsAvg = " " + deviationMarker(fn.CCNVsMean) + " " + deviationMarker(fn.NLOCVsMean)
+			}
+
 			fmt.Fprintf(
-				&b,
-				"%s %s %s %s %s %s %s %s %s %s %s %s %s %s\n",
+				b,
+				"%s %s %s %s %s %s %s %s %s %s %s %s %s %s%s\n",
 				fileCol,
 				funcCol,
 				ccnField,
@@ -212,18 +479,117 @@ func (r *TextRenderer) Render(report *model.ProjectReport) (string, error) {
 				finRaw,
 				foutRaw,
 				hotField,
+				vsAvg,
 			)
 		}
 	}
+}
 
-	if len(report.Warnings) > 0 {
-		fmt.Fprintf(&b, "\n%s\n", title("== Warnings =="))
-		for _, w := range report.Warnings {
-			fmt.Fprintf(&b, "%s %s\n", warnBullet("-"), warnText(w))
+func renderFunctionTree(b *strings.Builder, files []model.FileMetrics) {
+	sorted := append([]model.FileMetrics(nil), files...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Path < sorted[j].Path
+	})
+
+	fmt.Fprintf(b, "\n%s\n", title("== Functions by file =="))
+	for _, f := range sorted {
+		fmt.Fprintf(
+			b,
+			"%s %s\n",
+			colorFileField(f.Path),
+			label(fileTreeHeader(f)),
+		)
+
+		fns := append([]model.FunctionMetrics(nil), f.Functions...)
+		sort.SliceStable(fns, func(i, j int) bool {
+			return fns[i].StartLine < fns[j].StartLine
+		})
+		for i, fn := range fns {
+			branch := "├── "
+			if i == len(fns)-1 {
+				branch = "└── "
+			}
+			fmt.Fprintf(
+				b,
+				"%s%s CCN=%s NLOC=%d\n",
+				label(branch),
+				colorFuncField(fmt.Sprintf("%-30s", truncate(fn.Name, 30))),
+				colorCCNInt(fn.CCN),
+				fn.NLOC,
+			)
 		}
 	}
+}
 
-	return b.String(), nil
+func renderPackageGroups(b *strings.Builder, root string, files []model.FileMetrics) {
+	type packageGroup struct {
+		Name      string
+		Dir       string
+		Files     []model.FileMetrics
+		Functions int
+		CCNTotal  int
+	}
+
+	groups := map[string]*packageGroup{}
+	for _, f := range files {
+		if f.Language != model.LanguageGo || f.Package == "" {
+			continue
+		}
+		dir := filepath.Dir(f.Path)
+		if rel, err := filepath.Rel(root, dir); err == nil {
+			dir = rel
+		}
+		dir = filepath.ToSlash(dir)
+		key := dir + "\x00" + f.Package
+		g, ok := groups[key]
+		if !ok {
+			g = &packageGroup{Name: f.Package, Dir: dir}
+			groups[key] = g
+		}
+		g.Files = append(g.Files, f)
+		g.Functions += f.Summary.FunctionsCount
+		g.CCNTotal += f.Summary.CCNTotal
+	}
+	if len(groups) == 0 {
+		return
+	}
+
+	sorted := make([]*packageGroup, 0, len(groups))
+	for _, g := range groups {
+		sorted = append(sorted, g)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].CCNTotal != sorted[j].CCNTotal {
+			return sorted[i].CCNTotal > sorted[j].CCNTotal
+		}
+		if sorted[i].Dir != sorted[j].Dir {
+			return sorted[i].Dir < sorted[j].Dir
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	fmt.Fprintf(b, "\n%s\n", title("== Files by Go package =="))
+	for _, g := range sorted {
+		fmt.Fprintf(
+			b,
+			"%s %s %s\n",
+			accent(g.Name),
+			label(g.Dir),
+			label(fmt.Sprintf("(files=%d, funcs=%d, CCN=%d)", len(g.Files), g.Functions, g.CCNTotal)),
+		)
+		sort.SliceStable(g.Files, func(i, j int) bool {
+			return g.Files[i].Path < g.Files[j].Path
+		})
+		for _, f := range g.Files {
+			fmt.Fprintf(
+				b,
+				"    %s CCN=%s  funcs=%3d\n",
+				colorFileField(fmt.Sprintf("%-40s", trimPath(filepath.Base(f.Path), 40))),
+				colorCCNField(fmt.Sprintf("%4d", f.Summary.CCNTotal), f.Summary.CCNTotal),
+				f.Summary.FunctionsCount,
+			)
+		}
+	}
 }
 
 func title(s string) string {
@@ -335,6 +701,29 @@ func colorHotspotField(raw string, score float64) string {
 	}
 }
 
+func (r *TextRenderer) colorCommentField(raw string, density float64) string {
+	switch {
+	case density >= r.opts.CommentWarnBelow:
+		return colGood + raw + ansiReset
+	case density >= r.opts.CommentDangerBelow:
+		return colWarn + raw + ansiReset
+	default:
+		return colDanger + raw + ansiReset
+	}
+}
+
+func deviationMarker(ratio float64) string {
+	const eps = 1e-9
+	switch {
+	case ratio > 1+eps:
+		return colWarn + "↑" + ansiReset
+	case ratio < 1-eps:
+		return colGood + "↓" + ansiReset
+	default:
+		return colMuted + "=" + ansiReset
+	}
+}
+
 func trimPath(path string, max int) string {
 	if len(path) <= max {
 		return path
@@ -354,3 +743,46 @@ func truncate(s string, max int) string {
 	}
 	return s[:max-1] + "…"
 }
+
+func instabilityBar(instability float64) string {
+	const width = 10
+	filled := int(math.Round(instability * width))
+	return "[" + strings.Repeat("#", filled) + strings.Repeat(".", width-filled) + "]"
+}
+
+func fileLines(f model.FileMetrics) string {
+	if f.FileLinesTotal == 0 {
+		return "-"
+	}
+	return strconv.Itoa(f.FileLinesTotal)
+}
+
+func fileTreeHeader(f model.FileMetrics) string {
+	header := fmt.Sprintf("(funcs=%d, CCN=%d, NLOC=%d", f.Summary.FunctionsCount, f.Summary.CCNTotal, f.Summary.NLOC)
+	if f.FileLinesTotal > 0 {
+		header += fmt.Sprintf(", lines=%d", f.FileLinesTotal)
+	}
+	return header + ")"
+}
+
+func filesSummary(p model.ProjectMetrics) string {
+	if p.DeclarationOnlyFiles == 0 {
+		return strconv.Itoa(p.TotalFiles)
+	}
+	return fmt.Sprintf("%d (%d declaration-only)", p.TotalFiles, p.DeclarationOnlyFiles)
+}
+
+func ciSummary(ci *model.CIMetadata) string {
+	var parts []string
+	for _, f := range []struct{ name, value string }{
+		{"branch", ci.Branch},
+		{"commit", ci.Commit},
+		{"build", ci.BuildID},
+		{"pr", ci.PullRequest},
+	} {
+		if f.value != "" {
+			parts = append(parts, f.name+"="+f.value)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -4,6 +4,8 @@
 package parser
 
 import (
+	"fmt"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
@@ -12,14 +14,121 @@ import (
 	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
 )
 
+const (
+	CppModeHeuristic = "heuristic"
+	CppModeStrict    = "strict"
+)
+
+const DefaultMaxConstructorParams = 4
+
+type CParserOptions struct {
+	MaxConstructorParams int
+
+	CognitiveLineCap       int
+	SkipGeneratedCognitive bool
+
+	PreprocessorComplexity bool
+}
+
 type CParser struct {
-	funcHeaderRe *regexp.Regexp
+	scanner *lineScanner
 }
 
 func NewCParser() *CParser {
-	return &CParser{
+	return NewCParserWithOptions(CParserOptions{})
+}
+
+func NewCParserWithOptions(opts CParserOptions) *CParser {
+	return &CParser{scanner: newLineScanner(opts)}
+}
+
+type lineScanner struct {
+	funcHeaderRe *regexp.Regexp
+	opts         CParserOptions
+}
+
+func newLineScanner(opts CParserOptions) *lineScanner {
+	if opts.MaxConstructorParams <= 0 {
+		opts.MaxConstructorParams = DefaultMaxConstructorParams
+	}
+	return &lineScanner{
 		funcHeaderRe: regexp.MustCompile(`\b([a-zA-Z_]\w*)\s*\([^()]*\)\s*$`),
+		opts:         opts,
+	}
+}
+
+func ValidateCppMode(mode string) error {
+	switch mode {
+	case "", CppModeHeuristic, CppModeStrict:
+		return nil
+	default:
+		return fmt.Errorf("unknown C++ mode %q (want %s or %s)", mode, CppModeHeuristic, CppModeStrict)
+	}
+}
+
+func isCppSource(path string) bool {
+	for _, ext := range []string{".cpp", ".hpp", ".cc", ".hh", ".cxx", ".hxx"} {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+var preprocessorDirectiveRe = regexp.MustCompile(`^\s*#\s*([a-z]+)\s*(\w*)`)
+
+func preprocessorBranches(lines []string) int {
+	type directive struct{ name, arg string }
+	var directives []directive
+	for _, line := range lines {
+		if m := preprocessorDirectiveRe.FindStringSubmatch(line); m != nil {
+			directives = append(directives, directive{m[1], m[2]})
+		}
+	}
+
+	branches := 0
+	for i, d := range directives {
+		switch d.name {
+		case "ifndef":
+			guard := i == 0 && len(directives) > 1 &&
+				directives[1].name == "define" && directives[1].arg == d.arg
+			if guard {
+				continue
+			}
+			branches++
+		case "if", "ifdef", "elif", "elifdef", "elifndef", "else":
+			branches++
+		}
 	}
+	return branches
+}
+
+func isCppHeader(path, text string) bool {
+	if !strings.HasSuffix(path, ".h") {
+		return false
+	}
+	tokens := tokenizeCpp(text)
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].kind != cppIdent {
+			continue
+		}
+		next := tokens[i+1]
+		switch tokens[i].text {
+		case "class":
+			if next.kind == cppIdent {
+				return true
+			}
+		case "namespace":
+			if next.kind == cppIdent || next.text == "{" {
+				return true
+			}
+		case "template":
+			if next.text == "<" {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 var _ ports.CodeParser = (*CParser)(nil)
@@ -28,8 +137,12 @@ func (p *CParser) Name() string {
 	return "c/c++"
 }
 
+func (p *CParser) Extensions() []string {
+	return []string{".c", ".h", ".cpp", ".hpp", ".cc", ".hh", ".cxx", ".hxx"}
+}
+
 func (p *CParser) SupportsFile(path string) bool {
-	for _, ext := range []string{".c", ".h", ".cpp", ".hpp", ".cc", ".hh"} {
+	for _, ext := range p.Extensions() {
 		if strings.HasSuffix(path, ext) {
 			return true
 		}
@@ -38,6 +151,10 @@ func (p *CParser) SupportsFile(path string) bool {
 }
 
 func (p *CParser) ParseFile(path string, src []byte) (*model.FileMetrics, error) {
+	return p.scanner.parse(path, src, isCppSource(path) || isCppHeader(path, string(src)))
+}
+
+func (p *lineScanner) parse(path string, src []byte, cpp bool) (*model.FileMetrics, error) {
 	text := string(src)
 	lines := strings.Split(text, "\n")
 
@@ -57,6 +174,24 @@ func (p *CParser) ParseFile(path string, src []byte) (*model.FileMetrics, error)
 			CommentDensity: density,
 		},
 	}
+	setFileLineCounts(fm, lines)
+
+	if p.opts.PreprocessorComplexity {
+		fm.PreprocessorComplexity = preprocessorBranches(lines)
+	}
+
+	if cpp {
+		fm.Language = model.LanguageCpp
+	}
+
+	headerRe := p.funcHeaderRe
+	if cpp {
+		fm.Package = cppNamespace(tokenizeCpp(text))
+	}
+	braces := func(from, to int) (int, int) {
+		chunk := strings.Join(lines[from-1:to], "\n")
+		return strings.Count(chunk, "{"), strings.Count(chunk, "}")
+	}
 
 	var functions []model.FunctionMetrics
 	var allNloc, allCcn, maxCcn int
@@ -65,21 +200,37 @@ func (p *CParser) ParseFile(path string, src []byte) (*model.FileMetrics, error)
 	inFunc := false
 	funcStart := 0
 	funcName := ""
+	funcStatic := false
+	funcParams := 0
+	funcClass := ""
+	currentClass := ""
+	var staticFns []model.FunctionMetrics
+	publicDocs := make(map[string]bool)
 	braceDepth := 0
 
 	var headerBuf strings.Builder
 	headerStart := -1
+	inMacro := false
 
 	for i, line := range lines {
 		trimmed := strings.TrimSpace(line)
 
+		if inMacro {
+			inMacro = strings.HasSuffix(trimmed, "\\")
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") && strings.HasSuffix(trimmed, "\\") {
+			inMacro = true
+		}
+
 		if !inFunc {
 
 			if trimmed == "" ||
 				strings.HasPrefix(trimmed, "//") ||
 				strings.HasPrefix(trimmed, "/*") ||
 				strings.HasPrefix(trimmed, "*") ||
-				strings.HasPrefix(trimmed, "#") {
+				strings.HasPrefix(trimmed, "#") ||
+				accessSpecifierRe.MatchString(trimmed) {
 				headerBuf.Reset()
 				headerStart = -1
 				continue
@@ -93,21 +244,46 @@ func (p *CParser) ParseFile(path string, src []byte) (*model.FileMetrics, error)
 			}
 			headerBuf.WriteString(trimmed)
 
+			if currentClass != "" && strings.HasPrefix(trimmed, "};") {
+				currentClass = ""
+				headerBuf.Reset()
+				headerStart = -1
+				continue
+			}
+
+			if strings.HasSuffix(trimmed, ";") && !strings.Contains(trimmed, "{") {
+				if currentClass == "" {
+					if name, ok := p.publicPrototype(strings.TrimSuffix(headerBuf.String(), ";")); ok {
+						publicDocs[name] = publicDocs[name] || hasCDocComment(lines, headerStart)
+					}
+				}
+				headerBuf.Reset()
+				headerStart = -1
+				continue
+			}
+
 			if strings.Contains(trimmed, "{") {
 				candidate := headerBuf.String()
 				if idx := strings.Index(candidate, "{"); idx >= 0 {
 					candidate = strings.TrimSpace(candidate[:idx])
 				}
+				if m := classHeaderRe.FindStringSubmatch(candidate); len(m) == 2 {
+					currentClass = m[1]
+				}
 
-				if m := p.funcHeaderRe.FindStringSubmatch(candidate); len(m) == 2 {
-					name := m[1]
+				if m := headerRe.FindStringSubmatch(candidate); len(m) == 2 {
+					name := strings.Join(strings.Fields(m[1]), "")
 					if !isControlKeyword(name) {
 						inFunc = true
 						funcName = name
 						funcStart = headerStart
+						nameIdx := strings.Index(candidate, m[1])
+						funcStatic = staticKeyword.MatchString(candidate[:nameIdx])
+						funcParams = countHeaderParams(candidate[nameIdx+len(m[1]):])
+						funcClass = constructorClass(candidate, name, currentClass)
 
-						headerText := strings.Join(lines[funcStart-1:i+1], "\n")
-						braceDepth = strings.Count(headerText, "{") - strings.Count(headerText, "}")
+						opens, closes := braces(funcStart, i+1)
+						braceDepth = opens - closes
 					}
 				}
 
@@ -118,15 +294,28 @@ func (p *CParser) ParseFile(path string, src []byte) (*model.FileMetrics, error)
 			continue
 		}
 
-		braceDepth += strings.Count(line, "{")
-		braceDepth -= strings.Count(line, "}")
+		opens, closes := braces(i+1, i+1)
+		braceDepth += opens - closes
 
 		if braceDepth <= 0 {
 			start := funcStart
 			end := i + 1
 
+			if reason := invalidFunctionRange(start, end, functions); reason != "" {
+				fm.Warnings = append(fm.Warnings, fmt.Sprintf("dropped function %s: %s", funcName, reason))
+				inFunc = false
+				funcName = ""
+				funcStart = 0
+				funcStatic = false
+				funcClass = ""
+				braceDepth = 0
+				continue
+			}
+
+			capped, capRecord := newCognitiveCap(p.opts.CognitiveLineCap)
 			nloc, ccn, cognitive, maxNesting, locals, commentLinesFn :=
-				computeTextMetricsForRange(lines, start, end)
+				computeTextMetricsForRange(lines, start, end, capRecord)
+			cognitive = capped.apply(cognitive)
 
 			commentDensityFn := 0.0
 			if nloc+commentLinesFn > 0 {
@@ -134,15 +323,17 @@ func (p *CParser) ParseFile(path string, src []byte) (*model.FileMetrics, error)
 			}
 
 			callees := extractCFunctionCalls(lines, start, end)
+			operators := countOperatorsForRange(lines, start, end)
 
 			fn := model.FunctionMetrics{
 				Name:                funcName,
 				Signature:           funcName,
 				FilePath:            path,
-				Language:            model.LanguageC,
+				Language:            fm.Language,
 				StartLine:           start,
 				EndLine:             end,
 				NLOC:                nloc,
+				Parameters:          funcParams,
 				CCN:                 ccn,
 				CognitiveComplexity: cognitive,
 				MaxNesting:          maxNesting,
@@ -150,9 +341,25 @@ func (p *CParser) ParseFile(path string, src []byte) (*model.FileMetrics, error)
 				FanOut:              len(callees),
 				CommentDensity:      commentDensityFn,
 				Callees:             callees,
+				Operators:           &operators,
 			}
 
 			functions = append(functions, fn)
+			if funcStatic {
+				staticFns = append(staticFns, fn)
+			} else {
+				publicDocs[funcName] = publicDocs[funcName] || hasCDocComment(lines, start)
+			}
+			if funcClass != "" && funcParams > p.opts.MaxConstructorParams {
+				fm.Smells = append(fm.Smells, model.CodeSmell{
+					Kind:        model.SmellConstructorManyParams,
+					Description: fmt.Sprintf("constructor of %s takes %d parameters (>%d)", funcClass, funcParams, p.opts.MaxConstructorParams),
+					FilePath:    path,
+					Function:    funcName,
+					Class:       funcClass,
+					Line:        start,
+				})
+			}
 			allNloc += nloc
 			allCcn += ccn
 			if ccn > maxCcn {
@@ -168,11 +375,20 @@ func (p *CParser) ParseFile(path string, src []byte) (*model.FileMetrics, error)
 			inFunc = false
 			funcName = ""
 			funcStart = 0
+			funcStatic = false
+			funcClass = ""
 			braceDepth = 0
 		}
 	}
 
 	fm.Functions = functions
+	if p.opts.SkipGeneratedCognitive && isGeneratedSource(lines) {
+		zeroCognitive(fm.Functions)
+	}
+	if !cpp && !isHeaderPath(path) {
+		fm.Smells = append(fm.Smells, unusedStaticFunctions(path, lines, functions, staticFns)...)
+	}
+	fm.Smells = append(fm.Smells, duplicateIncludes(path, lines)...)
 	fnCount := len(functions)
 	avgCcn := 0.0
 	if fnCount > 0 {
@@ -188,10 +404,229 @@ func (p *CParser) ParseFile(path string, src []byte) (*model.FileMetrics, error)
 		FunctionsCCNGt10:  functionsCcnGt10,
 		FunctionsCCNGt20:  functionsCcnGt20,
 	}
+	fm.Summary.LongestFunctionName, fm.Summary.LongestFunctionNLOC = longestFunction(functions)
+
+	for _, documented := range publicDocs {
+		fm.Comments.PublicSymbols++
+		if documented {
+			fm.Comments.PublicDocumented++
+		}
+	}
+	if fm.Comments.PublicSymbols > 0 {
+		fm.Comments.PublicAPIDocPct = float64(fm.Comments.PublicDocumented) / float64(fm.Comments.PublicSymbols)
+	}
 
 	return fm, nil
 }
 
+func (p *lineScanner) publicPrototype(decl string) (string, bool) {
+	decl = strings.TrimSpace(decl)
+	if strings.HasPrefix(decl, "typedef") || strings.HasPrefix(decl, "return") {
+		return "", false
+	}
+	m := p.funcHeaderRe.FindStringSubmatchIndex(decl)
+	if m == nil {
+		return "", false
+	}
+	name := decl[m[2]:m[3]]
+	retType := strings.TrimSpace(decl[:m[2]])
+	if retType == "" || isControlKeyword(name) || staticKeyword.MatchString(retType) || strings.ContainsAny(retType, "=(") {
+		return "", false
+	}
+	return name, true
+}
+
+func hasCDocComment(lines []string, start int) bool {
+	i := start - 2
+	if i < 0 || i >= len(lines) {
+		return false
+	}
+	prev := strings.TrimSpace(lines[i])
+	if strings.HasPrefix(prev, "///") {
+		return true
+	}
+	if !strings.HasSuffix(prev, "*/") {
+		return false
+	}
+	for ; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if idx := strings.Index(line, "/*"); idx >= 0 {
+			return strings.HasPrefix(line[idx:], "/**") || strings.HasPrefix(line[idx:], "/*!")
+		}
+	}
+	return false
+}
+
+var staticKeyword = regexp.MustCompile(`\bstatic\b`)
+
+var accessSpecifierRe = regexp.MustCompile(`^(?:public|private|protected)\s*:$`)
+
+var classHeaderRe = regexp.MustCompile(`^(?:template\s*<.*>\s*)?(?:class|struct)\s+([A-Za-z_]\w*)\b[^(]*$`)
+
+var qualifiedCtorRe = regexp.MustCompile(`\b([A-Za-z_]\w*)\s*::\s*([A-Za-z_]\w*)\s*\(`)
+
+func constructorClass(candidate, name, currentClass string) string {
+	for _, m := range qualifiedCtorRe.FindAllStringSubmatch(candidate, -1) {
+		if m[1] == m[2] && m[2] == name {
+			return m[1]
+		}
+	}
+	if currentClass != "" && name == currentClass && !strings.Contains(candidate, "~") {
+		return currentClass
+	}
+	return ""
+}
+
+func countHeaderParams(rest string) int {
+	open := strings.IndexByte(rest, '(')
+	if open < 0 {
+		return 0
+	}
+	depth := 0
+	params := 1
+	for i := open + 1; i < len(rest); i++ {
+		switch rest[i] {
+		case '(', '<', '[':
+			depth++
+		case ')', '>', ']':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			if inner := strings.TrimSpace(rest[open+1 : i]); inner == "" || inner == "void" {
+				return 0
+			}
+			return params
+		case ',':
+			if depth == 0 {
+				params++
+			}
+		}
+	}
+	return params
+}
+
+func isHeaderPath(path string) bool {
+	switch filepath.Ext(path) {
+	case ".h", ".hpp", ".hh", ".hxx":
+		return true
+	}
+	return false
+}
+
+func unusedStaticFunctions(path string, lines []string, functions, staticFns []model.FunctionMetrics) []model.CodeSmell {
+	if len(staticFns) == 0 {
+		return nil
+	}
+	refs := identifierLines(lines)
+
+	var smells []model.CodeSmell
+	for _, fn := range staticFns {
+		called := false
+		for _, caller := range functions {
+			if caller.StartLine == fn.StartLine {
+				continue
+			}
+			for _, callee := range caller.Callees {
+				if callee == fn.Name {
+					called = true
+					break
+				}
+			}
+			if called {
+				break
+			}
+		}
+		if called || referencedOutside(refs, fn) {
+			continue
+		}
+		smells = append(smells, model.CodeSmell{
+			Kind:        model.SmellDeadCode,
+			Description: fmt.Sprintf("static function %s is never referenced in this file", fn.Name),
+			FilePath:    path,
+			Function:    fn.Name,
+			Line:        fn.StartLine,
+		})
+	}
+	return smells
+}
+
+var includeDirectiveRe = regexp.MustCompile(`^\s*#\s*include\s*([<"][^>"]+[>"])`)
+
+func duplicateIncludes(path string, lines []string) []model.CodeSmell {
+	var smells []model.CodeSmell
+	firstSeen := map[string]int{}
+	for i, line := range lines {
+		m := includeDirectiveRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		header := m[1]
+		if first, ok := firstSeen[header]; ok {
+			smells = append(smells, model.CodeSmell{
+				Kind:        model.SmellDuplicateInclude,
+				Description: fmt.Sprintf("%s is already included on line %d", header, first),
+				FilePath:    path,
+				Line:        i + 1,
+			})
+			continue
+		}
+		firstSeen[header] = i + 1
+	}
+	return smells
+}
+
+func identifierLines(lines []string) map[string][]int {
+	refs := make(map[string][]int)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if staticKeyword.MatchString(trimmed) && strings.HasSuffix(trimmed, ");") {
+			continue
+		}
+		for j := 0; j < len(line); {
+			if !isIdentStart(line[j]) {
+				j++
+				continue
+			}
+			start := j
+			for j < len(line) && isIdentPart(line[j]) {
+				j++
+			}
+			if start > 0 && isIdentPart(line[start-1]) {
+				continue
+			}
+			word := line[start:j]
+			if n := len(refs[word]); n == 0 || refs[word][n-1] != i+1 {
+				refs[word] = append(refs[word], i+1)
+			}
+		}
+	}
+	return refs
+}
+
+func referencedOutside(refs map[string][]int, fn model.FunctionMetrics) bool {
+	name := fn.Name
+	if k := strings.LastIndex(name, "::"); k >= 0 {
+		name = name[k+2:]
+	}
+	for _, line := range refs[name] {
+		if line < fn.StartLine || line > fn.EndLine {
+			return true
+		}
+	}
+	return false
+}
+
+func invalidFunctionRange(start, end int, emitted []model.FunctionMetrics) string {
+	if start >= end {
+		return fmt.Sprintf("empty line range %d-%d", start, end)
+	}
+	if n := len(emitted); n > 0 && start <= emitted[n-1].EndLine {
+		return fmt.Sprintf("lines %d-%d overlap %s", start, end, emitted[n-1].Name)
+	}
+	return ""
+}
+
 var cCallRegexp = regexp.MustCompile(`\b([a-zA-Z_]\w*)\s*\(`)
 
 func extractCFunctionCalls(lines []string, start, end int) []string {
@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+)
+
+type Config struct {
+	Disabled   []string
+	Extensions map[string][]string
+	Priority   []string
+}
+
+func Configure(parsers []ports.CodeParser, cfg Config) ([]ports.CodeParser, error) {
+	known := make(map[string]struct{}, len(parsers))
+	for _, p := range parsers {
+		known[strings.ToLower(p.Name())] = struct{}{}
+	}
+
+	disabled := make(map[string]struct{}, len(cfg.Disabled))
+	for _, name := range cfg.Disabled {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("unknown parser %q", name)
+		}
+		disabled[name] = struct{}{}
+	}
+
+	overrides := make(map[string][]string, len(cfg.Extensions))
+	for name, exts := range cfg.Extensions {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("unknown parser %q", name)
+		}
+		overrides[name] = exts
+	}
+
+	rank := make(map[string]int, len(cfg.Priority))
+	for _, name := range cfg.Priority {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("unknown parser %q", name)
+		}
+		if _, dup := rank[name]; !dup {
+			rank[name] = len(rank)
+		}
+	}
+
+	var out []ports.CodeParser
+	for _, p := range parsers {
+		name := strings.ToLower(p.Name())
+		if _, ok := disabled[name]; ok {
+			continue
+		}
+		if exts, ok := overrides[name]; ok {
+			out = append(out, withExtensions(p, exts))
+			continue
+		}
+		out = append(out, p)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return priorityRank(rank, out[i]) < priorityRank(rank, out[j])
+	})
+	return out, nil
+}
+
+func Extensions(parsers []ports.CodeParser) []string {
+	seen := make(map[string]struct{})
+	var out []string
+	for _, p := range parsers {
+		for _, ext := range p.Extensions() {
+			if _, ok := seen[ext]; !ok {
+				seen[ext] = struct{}{}
+				out = append(out, ext)
+			}
+		}
+	}
+	return out
+}
+
+func WithMappedExtensions(include []string, cfg Config) []string {
+	names := make([]string, 0, len(cfg.Extensions))
+	for name := range cfg.Extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := append([]string(nil), include...)
+	seen := make(map[string]struct{}, len(out))
+	for _, ext := range out {
+		seen[strings.ToLower(ext)] = struct{}{}
+	}
+	for _, name := range names {
+		for _, ext := range cfg.Extensions[name] {
+			if _, ok := seen[strings.ToLower(ext)]; !ok {
+				seen[strings.ToLower(ext)] = struct{}{}
+				out = append(out, ext)
+			}
+		}
+	}
+	return out
+}
+
+func priorityRank(rank map[string]int, p ports.CodeParser) int {
+	if r, ok := rank[strings.ToLower(p.Name())]; ok {
+		return r
+	}
+	return len(rank)
+}
+
+type extensionOverride struct {
+	ports.CodeParser
+	exts []string
+}
+
+type explainingOverride struct {
+	*extensionOverride
+	ports.FunctionExplainer
+}
+
+func withExtensions(p ports.CodeParser, exts []string) ports.CodeParser {
+	override := &extensionOverride{CodeParser: p, exts: exts}
+	if explainer, ok := p.(ports.FunctionExplainer); ok {
+		return &explainingOverride{extensionOverride: override, FunctionExplainer: explainer}
+	}
+	return override
+}
+
+func (p *extensionOverride) Extensions() []string {
+	return p.exts
+}
+
+func (p *extensionOverride) MatchesContent(path string, src []byte) bool {
+	m, ok := p.CodeParser.(ports.ContentMatcher)
+	return ok && m.MatchesContent(path, src)
+}
+
+func (p *extensionOverride) SupportsFile(path string) bool {
+	lower := strings.ToLower(path)
+	for _, ext := range p.exts {
+		if strings.HasSuffix(lower, strings.ToLower(ext)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,846 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+)
+
+type CppParserOptions struct {
+	CppMode              string
+	MaxConstructorParams int
+
+	CognitiveLineCap       int
+	SkipGeneratedCognitive bool
+	PreprocessorComplexity bool
+}
+
+type CppParser struct {
+	opts CppParserOptions
+	line *lineScanner
+}
+
+func NewCppParser() *CppParser {
+	return NewCppParserWithOptions(CppParserOptions{})
+}
+
+func NewCppParserWithOptions(opts CppParserOptions) *CppParser {
+	if opts.MaxConstructorParams <= 0 {
+		opts.MaxConstructorParams = DefaultMaxConstructorParams
+	}
+	p := &CppParser{opts: opts}
+	if opts.CppMode == CppModeHeuristic {
+		p.line = newLineScanner(CParserOptions{
+			MaxConstructorParams: opts.MaxConstructorParams,
+
+			CognitiveLineCap:       opts.CognitiveLineCap,
+			SkipGeneratedCognitive: opts.SkipGeneratedCognitive,
+
+			PreprocessorComplexity: opts.PreprocessorComplexity,
+		})
+	}
+	return p
+}
+
+var (
+	_ ports.CodeParser     = (*CppParser)(nil)
+	_ ports.ContentMatcher = (*CppParser)(nil)
+)
+
+func (p *CppParser) Name() string {
+	return "c++"
+}
+
+func (p *CppParser) Extensions() []string {
+	return []string{".cpp", ".cc", ".cxx", ".hpp", ".hh", ".hxx"}
+}
+
+func (p *CppParser) MatchesContent(path string, src []byte) bool {
+	return isCppHeader(path, string(src))
+}
+
+func (p *CppParser) SupportsFile(path string) bool {
+	for _, ext := range p.Extensions() {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+type cppScopeKind int
+
+const (
+	cppScopeNamespace cppScopeKind = iota
+	cppScopeClass
+)
+
+type cppScope struct {
+	kind     cppScopeKind
+	name     string
+	public   bool
+	internal bool
+}
+
+type cppMember struct {
+	public     bool
+	documented bool
+}
+
+type cppFunction struct {
+	name      string
+	class     string
+	start     int
+	bodyOpen  int
+	bodyClose int
+	params    int
+	ctor      bool
+	internal  bool
+	public    bool
+	lambda    bool
+}
+
+type cppLambda struct {
+	intro, bodyOpen, bodyClose int
+	params                     int
+}
+
+func (p *CppParser) ParseFile(path string, src []byte) (*model.FileMetrics, error) {
+	if p.line != nil {
+		return p.line.parse(path, src, true)
+	}
+	text := string(src)
+	lines := strings.Split(text, "\n")
+
+	totalLines := len(lines)
+	commentLines := estimateCommentLines(lines)
+	density := 0.0
+	if totalLines > 0 {
+		density = float64(commentLines) / float64(totalLines)
+	}
+
+	fm := &model.FileMetrics{
+		Path:     path,
+		Language: model.LanguageCpp,
+		Comments: model.CommentMetrics{
+			TotalLines:     totalLines,
+			CommentLines:   commentLines,
+			CommentDensity: density,
+		},
+	}
+	setFileLineCounts(fm, lines)
+	if p.opts.PreprocessorComplexity {
+		fm.PreprocessorComplexity = preprocessorBranches(lines)
+	}
+
+	tokens := tokenizeCpp(text)
+	fm.Package = cppNamespace(tokens)
+	match := cppMatchBrackets(tokens)
+	decls, members, skip := p.declarations(tokens, match, lines)
+
+	known := make(map[string]bool, len(decls)+len(members))
+	for _, d := range decls {
+		known[d.name] = true
+	}
+	for name := range members {
+		known[name] = true
+	}
+
+	var functions, internalFns []model.FunctionMetrics
+	var allNloc, allCcn, maxCcn int
+	var functionsCcnGt10, functionsCcnGt20 int
+	publicDocs := make(map[string]bool)
+	for name, m := range members {
+		if m.public {
+			publicDocs[name] = m.documented
+		}
+	}
+
+	for _, d := range decls {
+		start := tokens[d.start].line
+		end := tokens[d.bodyClose].line
+		nloc := cppSpanLines(tokens, d.start, d.bodyClose, skip)
+		ccn, cognitive, maxNesting := p.spanMetrics(tokens, d.bodyOpen, d.bodyClose, skip)
+		_, _, _, _, _, commentLinesFn := computeTextMetricsForRange(lines, start, end, nil)
+
+		commentDensityFn := 0.0
+		if nloc+commentLinesFn > 0 {
+			commentDensityFn = float64(commentLinesFn) / float64(nloc+commentLinesFn)
+		}
+
+		callees := cppCallees(tokens, match, d.bodyOpen, d.bodyClose, skip, d.class, known)
+		operators := countOperatorsForRange(lines, start, end)
+
+		documented := false
+		if !d.lambda {
+			documented = hasCDocComment(lines, start) || members[d.name].documented
+		}
+
+		fn := model.FunctionMetrics{
+			Name:                d.name,
+			Signature:           d.name,
+			FilePath:            path,
+			Language:            model.LanguageCpp,
+			StartLine:           start,
+			EndLine:             end,
+			NLOC:                nloc,
+			Parameters:          d.params,
+			CCN:                 ccn,
+			CognitiveComplexity: cognitive,
+			MaxNesting:          maxNesting,
+			FanOut:              len(callees),
+			CommentDensity:      commentDensityFn,
+			Callees:             callees,
+			Operators:           &operators,
+			IsPublic:            d.public,
+			IsDocumented:        documented,
+		}
+		functions = append(functions, fn)
+
+		if d.internal {
+			internalFns = append(internalFns, fn)
+		}
+		if d.public {
+			publicDocs[d.name] = publicDocs[d.name] || documented
+		}
+		if d.ctor && d.params > p.opts.MaxConstructorParams {
+			fm.Smells = append(fm.Smells, model.CodeSmell{
+				Kind:        model.SmellConstructorManyParams,
+				Description: fmt.Sprintf("constructor of %s takes %d parameters (>%d)", d.class, d.params, p.opts.MaxConstructorParams),
+				FilePath:    path,
+				Function:    d.name,
+				Class:       d.class,
+				Line:        start,
+			})
+		}
+
+		allNloc += nloc
+		allCcn += ccn
+		if ccn > maxCcn {
+			maxCcn = ccn
+		}
+		if ccn > 10 {
+			functionsCcnGt10++
+		}
+		if ccn > 20 {
+			functionsCcnGt20++
+		}
+	}
+
+	fm.Functions = functions
+	if p.opts.SkipGeneratedCognitive && isGeneratedSource(lines) {
+		zeroCognitive(fm.Functions)
+	}
+	if !isHeaderPath(path) {
+		fm.Smells = append(fm.Smells, unusedStaticFunctions(path, lines, functions, internalFns)...)
+	}
+	fm.Smells = append(fm.Smells, duplicateIncludes(path, lines)...)
+
+	fnCount := len(functions)
+	avgCcn := 0.0
+	if fnCount > 0 {
+		avgCcn = float64(allCcn) / float64(fnCount)
+	}
+	fm.Summary = model.FileSummaryMetrics{
+		NLOC:              allNloc,
+		CCNTotal:          allCcn,
+		CCNAvgPerFunction: avgCcn,
+		CCNMaxFunction:    maxCcn,
+		FunctionsCount:    fnCount,
+		FunctionsCCNGt10:  functionsCcnGt10,
+		FunctionsCCNGt20:  functionsCcnGt20,
+	}
+	fm.Summary.LongestFunctionName, fm.Summary.LongestFunctionNLOC = longestFunction(functions)
+
+	for _, documented := range publicDocs {
+		fm.Comments.PublicSymbols++
+		if documented {
+			fm.Comments.PublicDocumented++
+		}
+	}
+	if fm.Comments.PublicSymbols > 0 {
+		fm.Comments.PublicAPIDocPct = float64(fm.Comments.PublicDocumented) / float64(fm.Comments.PublicSymbols)
+	}
+
+	return fm, nil
+}
+
+func (p *CppParser) spanMetrics(tokens []cppToken, from, to int, skip map[int]int) (ccn, cognitive, maxNesting int) {
+	if p.opts.CognitiveLineCap <= 0 {
+		return cppSpanMetrics(tokens, from, to, skip)
+	}
+	perLine := make(map[int]int)
+	ccn, _, maxNesting = cppSpanMetricsRecorded(tokens, from, to, skip, func(t cppToken, _, cognitive int) {
+		perLine[t.line] += cognitive
+	})
+	for _, c := range perLine {
+		if c > p.opts.CognitiveLineCap {
+			c = p.opts.CognitiveLineCap
+		}
+		cognitive += c
+	}
+	return ccn, cognitive, maxNesting
+}
+
+func (p *CppParser) declarations(tokens []cppToken, match []int, lines []string) ([]cppFunction, map[string]cppMember, map[int]int) {
+	lambdas := cppFindLambdas(tokens, match)
+
+	skip := make(map[int]int, len(lambdas))
+	lambdaBodies := make(map[int]cppLambda, len(lambdas))
+	for _, l := range lambdas {
+		skip[l.intro] = l.bodyClose
+		lambdaBodies[l.bodyOpen] = l
+	}
+
+	decls, members := p.scanDeclarations(tokens, match, lambdaBodies, lines)
+	for _, l := range lambdas {
+		decls = append(decls, cppFunction{
+			start:     l.intro,
+			bodyOpen:  l.bodyOpen,
+			bodyClose: l.bodyClose,
+			params:    l.params,
+			lambda:    true,
+		})
+	}
+	sort.SliceStable(decls, func(i, j int) bool { return decls[i].start < decls[j].start })
+	nameLambdas(decls, tokens)
+	return decls, members, skip
+}
+
+func (p *CppParser) scanDeclarations(tokens []cppToken, match []int, lambdaBodies map[int]cppLambda, lines []string) ([]cppFunction, map[string]cppMember) {
+	var decls []cppFunction
+	members := make(map[string]cppMember)
+	var scopes []cppScope
+
+	classChain := func() string {
+		var names []string
+		for _, s := range scopes {
+			if s.kind == cppScopeClass {
+				names = append(names, s.name)
+			}
+		}
+		return strings.Join(names, "::")
+	}
+	internalScope := func() bool {
+		for _, s := range scopes {
+			if s.internal {
+				return true
+			}
+		}
+		return false
+	}
+
+	stmtStart := 0
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		var top *cppScope
+		if n := len(scopes); n > 0 {
+			top = &scopes[n-1]
+		}
+
+		if top != nil && top.kind == cppScopeClass && i == stmtStart && i+1 < len(tokens) &&
+			tokens[i+1].text == ":" && (t.text == "public" || t.text == "private" || t.text == "protected") {
+			top.public = t.text == "public"
+			i++
+			stmtStart = i + 1
+			continue
+		}
+
+		switch t.text {
+		case ";":
+			if top != nil && top.kind == cppScopeClass {
+				if name, _, ok := cppDeclaratorName(tokens, match, stmtStart, i); ok {
+					members[qualifyCpp(classChain(), name)] = cppMember{
+						public:     top.public,
+						documented: hasCDocComment(lines, tokens[stmtStart].line),
+					}
+				}
+			} else if !internalScope() && !cppHasToken(tokens, stmtStart, i, "static", "typedef", "using", "return") {
+				if name, paren, ok := cppDeclaratorName(tokens, match, stmtStart, i); ok && !cppHasToken(tokens, stmtStart, paren, "=") {
+					members[name] = cppMember{public: true, documented: hasCDocComment(lines, tokens[stmtStart].line)}
+				}
+			}
+			stmtStart = i + 1
+			continue
+		case "}":
+			if len(scopes) > 0 {
+				scopes = scopes[:len(scopes)-1]
+			}
+			stmtStart = i + 1
+			continue
+		case "{":
+		default:
+			continue
+		}
+
+		close := match[i]
+		if close < 0 {
+			continue
+		}
+		if _, ok := lambdaBodies[i]; ok {
+			if !cppEnclosed(match, stmtStart, i) {
+				stmtStart = close + 1
+			}
+			i = close
+			continue
+		}
+
+		switch {
+		case cppHasToken(tokens, stmtStart, i, "namespace"):
+			var parts []string
+			for j := stmtStart; j < i; j++ {
+				if tokens[j].kind == cppIdent && tokens[j].text != "namespace" && tokens[j].text != "inline" {
+					parts = append(parts, tokens[j].text)
+				}
+			}
+			scopes = append(scopes, cppScope{kind: cppScopeNamespace, name: strings.Join(parts, "::"), internal: len(parts) == 0})
+			stmtStart = i + 1
+			continue
+		case stmtStart < i && tokens[stmtStart].text == "extern" && tokens[stmtStart+1].kind == cppLiteral:
+			scopes = append(scopes, cppScope{kind: cppScopeNamespace})
+			stmtStart = i + 1
+			continue
+		case cppHasToken(tokens, stmtStart, i, "enum"):
+			i = close
+			stmtStart = close + 1
+			continue
+		}
+
+		name, paren, isFunc := cppDeclaratorName(tokens, match, stmtStart, i)
+		if !isFunc {
+			if key, className := cppClassHead(tokens, stmtStart, i); key != "" && !cppHasToken(tokens, stmtStart, i, "=") {
+				scopes = append(scopes, cppScope{kind: cppScopeClass, name: className, public: key != "class"})
+				stmtStart = i + 1
+				continue
+			}
+			i = close
+			stmtStart = close + 1
+			continue
+		}
+		if cppHasToken(tokens, stmtStart, paren, "=") {
+			i = close
+			stmtStart = close + 1
+			continue
+		}
+		if cppInInitializerList(tokens, match, paren, i) {
+			if prev := tokens[i-1]; prev.kind == cppIdent || (prev.text == ">" && prev.templateArg) {
+				i = close
+				continue
+			}
+		}
+
+		class := classChain()
+		parts := strings.Split(name, "::")
+		last := parts[len(parts)-1]
+		if len(parts) > 1 {
+			class = qualifyCpp(class, strings.Join(parts[:len(parts)-1], "::"))
+		}
+		owner := class
+		if k := strings.LastIndex(owner, "::"); k >= 0 {
+			owner = owner[k+2:]
+		}
+
+		fullName := qualifyCpp(classChain(), name)
+		public := true
+		internal := false
+		switch {
+		case top != nil && top.kind == cppScopeClass:
+			public = top.public
+		case len(parts) > 1:
+			if m, ok := members[fullName]; ok {
+				public = m.public
+			}
+		default:
+			internal = internalScope() || cppHasToken(tokens, stmtStart, paren, "static")
+			public = !internal
+		}
+
+		decls = append(decls, cppFunction{
+			name:      fullName,
+			class:     class,
+			start:     stmtStart,
+			bodyOpen:  i,
+			bodyClose: close,
+			params:    cppCountParams(tokens, match, paren),
+			ctor:      owner != "" && last == owner,
+			internal:  internal,
+			public:    public,
+		})
+		i = close
+		stmtStart = close + 1
+	}
+	return decls, members
+}
+
+func cppEnclosed(match []int, from, at int) bool {
+	for j := from; j < at; j++ {
+		if match[j] > at {
+			return true
+		}
+	}
+	return false
+}
+
+func qualifyCpp(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "::" + name
+}
+
+func cppMatchBrackets(tokens []cppToken) []int {
+	match := make([]int, len(tokens))
+	for i := range match {
+		match[i] = -1
+	}
+	stacks := map[string][]int{}
+	closers := map[string]string{")": "(", "]": "[", "}": "{"}
+	for i, t := range tokens {
+		if t.kind != cppPunct || t.operatorName {
+			continue
+		}
+		switch t.text {
+		case "(", "[", "{":
+			stacks[t.text] = append(stacks[t.text], i)
+		case ")", "]", "}":
+			open := closers[t.text]
+			if n := len(stacks[open]); n > 0 {
+				j := stacks[open][n-1]
+				stacks[open] = stacks[open][:n-1]
+				match[i], match[j] = j, i
+			}
+		}
+	}
+	return match
+}
+
+var cppLambdaPrefixKeywords = map[string]bool{
+	"return": true, "co_return": true, "co_yield": true, "throw": true, "case": true,
+}
+
+var cppLambdaSpecifiers = map[string]bool{
+	"mutable": true, "constexpr": true, "consteval": true, "noexcept": true, "static": true,
+}
+
+func cppFindLambdas(tokens []cppToken, match []int) []cppLambda {
+	var lambdas []cppLambda
+	for i, t := range tokens {
+		if t.text != "[" || t.kind != cppPunct || t.operatorName || match[i] < 0 {
+			continue
+		}
+		if i+1 < len(tokens) && tokens[i+1].text == "[" {
+			continue
+		}
+		if i > 0 {
+			prev := tokens[i-1]
+			if prev.text == "[" || prev.text == ")" || prev.text == "]" || prev.kind == cppNumber || prev.kind == cppLiteral ||
+				(prev.kind == cppIdent && !cppLambdaPrefixKeywords[prev.text]) || (prev.text == ">" && prev.templateArg) {
+				continue
+			}
+		}
+
+		k := match[i] + 1
+		for k < len(tokens) && tokens[k].templateArg {
+			k++
+		}
+		params := 0
+		if k < len(tokens) && tokens[k].text == "(" && match[k] > 0 {
+			params = cppCountParams(tokens, match, k)
+			k = match[k] + 1
+		}
+		for k < len(tokens) && tokens[k].text != "{" {
+			switch {
+			case cppLambdaSpecifiers[tokens[k].text]:
+				k++
+				if k < len(tokens) && tokens[k].text == "(" && match[k] > 0 {
+					k = match[k] + 1
+				}
+			case tokens[k].text == "->":
+				k++
+				for k < len(tokens) && tokens[k].text != "{" && tokens[k].text != ";" {
+					k++
+				}
+			default:
+				k = len(tokens)
+			}
+		}
+		if k >= len(tokens) || match[k] < 0 {
+			continue
+		}
+		lambdas = append(lambdas, cppLambda{intro: i, bodyOpen: k, bodyClose: match[k], params: params})
+	}
+	return lambdas
+}
+
+func nameLambdas(decls []cppFunction, tokens []cppToken) {
+	for i := range decls {
+		if !decls[i].lambda {
+			continue
+		}
+		name := fmt.Sprintf("lambda@%d", tokens[decls[i].start].line)
+		for j := i - 1; j >= 0; j-- {
+			if decls[j].bodyOpen < decls[i].start && decls[i].bodyClose <= decls[j].bodyClose {
+				name = decls[j].name + "::" + name
+				decls[i].class = decls[j].class
+				break
+			}
+		}
+		decls[i].name = name
+	}
+}
+
+var cppNonCallKeywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "return": true, "catch": true,
+	"sizeof": true, "alignof": true, "alignas": true, "decltype": true, "typeid": true, "noexcept": true,
+	"static_assert": true, "static_cast": true, "dynamic_cast": true, "const_cast": true,
+	"reinterpret_cast": true, "requires": true, "__attribute__": true, "__declspec": true,
+	"throw": true, "operator": true,
+}
+
+var cppCallPrefixKeywords = map[string]bool{
+	"return": true, "else": true, "new": true, "delete": true, "throw": true, "case": true, "do": true,
+	"co_await": true, "co_return": true, "co_yield": true, "not": true, "and": true, "or": true,
+}
+
+func cppQualifiedNameBefore(tokens []cppToken, paren int) (name string, first int, ok bool) {
+	j := paren - 1
+	for j >= 0 && tokens[j].templateArg {
+		j--
+	}
+	if j < 0 || tokens[j].kind != cppIdent {
+		return "", 0, false
+	}
+	parts := []string{tokens[j].text}
+	first = j
+	for first >= 2 && tokens[first-1].text == "::" {
+		k := first - 2
+		for k >= 0 && tokens[k].templateArg {
+			k--
+		}
+		if k < 0 || tokens[k].kind != cppIdent {
+			first--
+			break
+		}
+		parts = append([]string{tokens[k].text}, parts...)
+		first = k
+	}
+	return strings.Join(parts, "::"), first, true
+}
+
+func cppCallees(tokens []cppToken, match []int, from, to int, skip map[int]int, class string, known map[string]bool) []string {
+	seen := make(map[string]struct{})
+	for i := from + 1; i < to; i++ {
+		if end, ok := skip[i]; ok {
+			i = end
+			continue
+		}
+		t := tokens[i]
+		if t.text != "(" || t.kind != cppPunct || t.operatorName || t.templateArg {
+			continue
+		}
+		name, first, ok := cppQualifiedNameBefore(tokens, i)
+		if !ok || cppNonCallKeywords[name] {
+			continue
+		}
+
+		member := false
+		if first > 0 {
+			prev := tokens[first-1]
+			switch {
+			case prev.text == "." || prev.text == "->":
+				member = true
+				if first < 2 || tokens[first-2].text != "this" {
+					name = name[strings.LastIndex(name, ":")+1:]
+					seen[name] = struct{}{}
+					continue
+				}
+			case prev.kind == cppIdent && !cppCallPrefixKeywords[prev.text]:
+				continue
+			case prev.text == ">" && prev.templateArg:
+				continue
+			}
+		}
+		if !strings.Contains(name, "::") || member {
+			if qualified := qualifyCpp(class, name); class != "" && known[qualified] {
+				name = qualified
+			}
+		}
+		seen[name] = struct{}{}
+	}
+
+	out := make([]string, 0, len(seen))
+	for name := range seen {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+var cppDeclaratorSkip = map[string]bool{
+	"decltype": true, "alignas": true, "__attribute__": true, "__declspec": true, "noexcept": true,
+	"throw": true, "requires": true, "static_assert": true, "sizeof": true,
+}
+
+func cppDeclaratorName(tokens []cppToken, match []int, from, to int) (name string, paren int, ok bool) {
+	for k := from; k < to; k++ {
+		t := tokens[k]
+		if t.templateArg || t.operatorName || t.kind != cppPunct {
+			continue
+		}
+		switch t.text {
+		case "[", "{":
+			if match[k] > k {
+				k = match[k]
+			}
+			continue
+		case "(":
+		default:
+			continue
+		}
+		if match[k] < 0 {
+			return "", 0, false
+		}
+		if k == from {
+			k = match[k]
+			continue
+		}
+
+		prev := tokens[k-1]
+		switch {
+		case prev.operatorName:
+			j := k - 1
+			for j >= from && tokens[j].operatorName {
+				j--
+			}
+			var op strings.Builder
+			for m := j + 1; m < k; m++ {
+				op.WriteString(tokens[m].text)
+			}
+			qualified, _, _ := cppQualifiedNameBefore(tokens, j+1)
+			return qualified + op.String(), k, true
+		case prev.kind == cppIdent && cppDeclaratorSkip[prev.text]:
+			k = match[k]
+			continue
+		}
+
+		qualified, first, found := cppQualifiedNameBefore(tokens, k)
+		if !found || cppNonCallKeywords[qualified] {
+			k = match[k]
+			continue
+		}
+		if first > from && tokens[first-1].text == "~" {
+			parts := strings.Split(qualified, "::")
+			parts[len(parts)-1] = "~" + parts[len(parts)-1]
+			qualified = strings.Join(parts, "::")
+		} else if first > from && tokens[first-1].text == "operator" {
+			qualified = "operator " + qualified
+		}
+		return qualified, k, true
+	}
+	return "", 0, false
+}
+
+func cppClassHead(tokens []cppToken, from, to int) (key, name string) {
+	for k := from; k < to; k++ {
+		t := tokens[k]
+		if t.templateArg || t.kind != cppIdent {
+			continue
+		}
+		switch t.text {
+		case "class", "struct", "union":
+		default:
+			continue
+		}
+		key = t.text
+		for j := k + 1; j < to; j++ {
+			n := tokens[j]
+			if n.kind != cppIdent || n.templateArg {
+				if n.text == ":" || n.text == "{" {
+					break
+				}
+				continue
+			}
+			switch n.text {
+			case "alignas", "__attribute__", "__declspec", "final":
+				continue
+			}
+			name = n.text
+			if j+1 < to && tokens[j+1].text == "::" {
+				continue
+			}
+			break
+		}
+		return key, name
+	}
+	return "", ""
+}
+
+func cppInInitializerList(tokens []cppToken, match []int, paren, body int) bool {
+	for k := match[paren] + 1; k < body; k++ {
+		if tokens[k].text == ":" && !tokens[k].templateArg {
+			return true
+		}
+		if tokens[k].text == "(" && match[k] > k {
+			k = match[k]
+		}
+	}
+	return false
+}
+
+func cppCountParams(tokens []cppToken, match []int, open int) int {
+	close := match[open]
+	if close <= open+1 {
+		return 0
+	}
+	if close == open+2 && tokens[open+1].text == "void" {
+		return 0
+	}
+	params := 1
+	for k := open + 1; k < close; k++ {
+		t := tokens[k]
+		if t.templateArg {
+			continue
+		}
+		switch t.text {
+		case "(", "[", "{":
+			if match[k] > k {
+				k = match[k]
+			}
+		case ",":
+			params++
+		}
+	}
+	return params
+}
+
+func cppHasToken(tokens []cppToken, from, to int, texts ...string) bool {
+	for k := from; k < to && k < len(tokens); k++ {
+		if tokens[k].templateArg || tokens[k].kind == cppLiteral {
+			continue
+		}
+		for _, text := range texts {
+			if tokens[k].text == text {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func cppSpanLines(tokens []cppToken, from, to int, skip map[int]int) int {
+	lines := make(map[int]struct{})
+	for i := from; i <= to && i < len(tokens); i++ {
+		if end, ok := skip[i]; ok && i > from {
+			i = end
+			continue
+		}
+		lines[tokens[i].line] = struct{}{}
+	}
+	return len(lines)
+}
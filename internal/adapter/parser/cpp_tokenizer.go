@@ -0,0 +1,436 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"strings"
+)
+
+type cppTokenKind int
+
+const (
+	cppIdent cppTokenKind = iota
+	cppNumber
+	cppLiteral
+	cppPunct
+)
+
+type cppToken struct {
+	kind cppTokenKind
+	text string
+	line int
+
+	templateArg  bool
+	operatorName bool
+}
+
+var cppPunctuators = []string{
+	"<<=", ">>=", "...", "->*", "<=>",
+	"::", "->", "&&", "||", "<<", "==", "!=", "<=", ">=", "++", "--",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", ".*",
+}
+
+var cppStringPrefixes = []string{"u8R", "uR", "UR", "LR", "R", "u8", "u", "U", "L"}
+
+func tokenizeCpp(src string) []cppToken {
+	var tokens []cppToken
+	line := 1
+	atLineStart := true
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == '\n':
+			line++
+			atLineStart = true
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+			continue
+		case c == '#' && atLineStart:
+			for i < len(src) && src[i] != '\n' {
+				if src[i] == '\\' && i+1 < len(src) && src[i+1] == '\n' {
+					line++
+					i++
+				}
+				i++
+			}
+			continue
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+			continue
+		}
+		atLineStart = false
+
+		if n, raw := cppStringStart(src[i:]); n > 0 {
+			start := i
+			if raw {
+				i = cppSkipRawString(src, i+n)
+			} else {
+				i = cppSkipQuoted(src, i+n, '"')
+			}
+			tokens = append(tokens, cppToken{kind: cppLiteral, text: src[start:i], line: line})
+			line += strings.Count(src[start:i], "\n")
+			continue
+		}
+
+		if c == '\'' {
+			start := i
+			i = cppSkipQuoted(src, i+1, '\'')
+			tokens = append(tokens, cppToken{kind: cppLiteral, text: src[start:i], line: line})
+			continue
+		}
+
+		if isIdentStart(c) {
+			start := i
+			for i < len(src) && isIdentPart(src[i]) {
+				i++
+			}
+			tokens = append(tokens, cppToken{kind: cppIdent, text: src[start:i], line: line})
+			continue
+		}
+
+		if isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])) {
+			start := i
+			for i < len(src) && (isIdentPart(src[i]) || src[i] == '.' ||
+				(src[i] == '\'' && i+1 < len(src) && isIdentPart(src[i+1])) ||
+				((src[i] == '+' || src[i] == '-') && (src[i-1] == 'e' || src[i-1] == 'E' || src[i-1] == 'p' || src[i-1] == 'P'))) {
+				i++
+			}
+			tokens = append(tokens, cppToken{kind: cppNumber, text: src[start:i], line: line})
+			continue
+		}
+
+		text := src[i : i+1]
+		for _, p := range cppPunctuators {
+			if strings.HasPrefix(src[i:], p) {
+				text = p
+				break
+			}
+		}
+		tokens = append(tokens, cppToken{kind: cppPunct, text: text, line: line})
+		i += len(text)
+	}
+
+	markOperatorNames(tokens)
+	markTemplateArguments(tokens)
+	return tokens
+}
+
+func cppStringStart(s string) (int, bool) {
+	for _, p := range cppStringPrefixes {
+		if strings.HasPrefix(s, p+`"`) {
+			return len(p) + 1, strings.HasSuffix(p, "R")
+		}
+	}
+	if strings.HasPrefix(s, `"`) {
+		return 1, false
+	}
+	return 0, false
+}
+
+func cppSkipQuoted(src string, i int, quote byte) int {
+	for i < len(src) {
+		switch src[i] {
+		case '\\':
+			i += 2
+			continue
+		case quote:
+			return i + 1
+		case '\n':
+			return i
+		}
+		i++
+	}
+	return len(src)
+}
+
+func cppSkipRawString(src string, i int) int {
+	open := strings.IndexByte(src[i:], '(')
+	if open < 0 {
+		return len(src)
+	}
+	closing := ")" + src[i:i+open] + `"`
+	end := strings.Index(src[i+open+1:], closing)
+	if end < 0 {
+		return len(src)
+	}
+	return i + open + 1 + end + len(closing)
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func markOperatorNames(tokens []cppToken) {
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].text != "operator" || i+1 >= len(tokens) {
+			continue
+		}
+		next := &tokens[i+1]
+		if next.kind != cppPunct {
+			continue
+		}
+		next.operatorName = true
+		if (next.text == "(" || next.text == "[") && i+2 < len(tokens) {
+			tokens[i+2].operatorName = true
+		}
+	}
+}
+
+func markTemplateArguments(tokens []cppToken) {
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i].text != "<" || tokens[i].operatorName || tokens[i].templateArg {
+			continue
+		}
+		prev := tokens[i-1]
+		if prev.kind != cppIdent || prev.text == "operator" {
+			continue
+		}
+		if end := matchTemplateClose(tokens, i); end > 0 {
+			for j := i; j <= end; j++ {
+				tokens[j].templateArg = true
+			}
+		}
+	}
+}
+
+func matchTemplateClose(tokens []cppToken, open int) int {
+	angle, paren := 0, 0
+	for j := open; j < len(tokens); j++ {
+		t := tokens[j]
+		if t.kind != cppPunct {
+			continue
+		}
+		switch t.text {
+		case "<":
+			if paren == 0 {
+				angle++
+			}
+		case ">":
+			if paren == 0 {
+				angle--
+				if angle == 0 {
+					return j
+				}
+			}
+		case "(", "[":
+			paren++
+		case ")", "]":
+			paren--
+			if paren < 0 {
+				return -1
+			}
+		case ";", "{", "}":
+			return -1
+		case "&&", "||", "?":
+			if paren == 0 {
+				return -1
+			}
+		}
+	}
+	return -1
+}
+
+var cppTypeKeywords = map[string]bool{
+	"auto": true, "const": true, "volatile": true, "bool": true, "char": true,
+	"short": true, "int": true, "long": true, "float": true, "double": true,
+	"unsigned": true, "signed": true, "void": true,
+}
+
+func isRvalueReference(tokens []cppToken, i int) bool {
+	if i == 0 || i+1 >= len(tokens) {
+		return false
+	}
+	prev, next := tokens[i-1], tokens[i+1]
+	if cppTypeKeywords[prev.text] || prev.text == ">" {
+		return true
+	}
+	switch next.text {
+	case ")", ",", ">", "...":
+		return true
+	}
+	if prev.kind != cppIdent || next.kind != cppIdent || i < 2 || i+2 >= len(tokens) {
+		return false
+	}
+	switch tokens[i-2].text {
+	case ";", "{", "}":
+	default:
+		return false
+	}
+	switch tokens[i+2].text {
+	case "=", ";", ":", "(", "{":
+		return true
+	}
+	return false
+}
+
+var cppBlockOpeners = map[string]bool{
+	")": true, "else": true, "do": true, "try": true, "]": true,
+	"{": true, ";": true, "}": true, ":": true,
+	"mutable": true, "noexcept": true, "const": true, "override": true, "final": true,
+}
+
+func cppSpanMetrics(tokens []cppToken, from, to int, skip map[int]int) (ccn, cognitive, maxNesting int) {
+	return cppSpanMetricsRecorded(tokens, from, to, skip, nil)
+}
+
+func cppSpanMetricsRecorded(tokens []cppToken, from, to int, skip map[int]int, record func(t cppToken, ccn, cognitive int)) (ccn, cognitive, maxNesting int) {
+	ccn = 1
+
+	var blocks []bool
+	depth := 0
+	inBody := false
+	var lastLogical string
+
+	for i := from; i <= to && i < len(tokens); i++ {
+		if end, ok := skip[i]; ok && i > from {
+			i = end
+			continue
+		}
+		t := tokens[i]
+
+		if t.kind == cppPunct && t.text == "{" {
+			block := !inBody
+			if inBody && i > 0 {
+				block = cppBlockOpeners[tokens[i-1].text]
+				if n := len(blocks); n > 0 && !blocks[n-1] {
+					block = false
+				}
+			}
+			inBody = true
+			blocks = append(blocks, block)
+			if block {
+				depth++
+				if depth > maxNesting {
+					maxNesting = depth
+				}
+			}
+			continue
+		}
+		if t.kind == cppPunct && t.text == "}" {
+			if n := len(blocks); n > 0 {
+				if blocks[n-1] {
+					depth--
+				}
+				blocks = blocks[:n-1]
+			}
+			continue
+		}
+		if !inBody || t.templateArg || t.operatorName {
+			continue
+		}
+
+		nesting := depth - 1
+		if nesting < 0 {
+			nesting = 0
+		}
+		ccnBefore, cognitiveBefore := ccn, cognitive
+
+		switch t.text {
+		case "if", "for", "while", "catch":
+			ccn++
+			cognitive += 1 + nesting
+			if t.text == "if" && i > 0 && tokens[i-1].text == "else" {
+				cognitive -= 1 + nesting
+			}
+		case "switch":
+			cognitive += 1 + nesting
+		case "case":
+			ccn++
+		case "else":
+			cognitive++
+		case "?":
+			ccn++
+			cognitive += 1 + nesting
+		case "&&", "and", "||", "or":
+			if t.text == "&&" && isRvalueReference(tokens, i) {
+				continue
+			}
+			ccn++
+			op := t.text
+			if op == "and" {
+				op = "&&"
+			} else if op == "or" {
+				op = "||"
+			}
+			if op != lastLogical {
+				cognitive++
+			}
+			lastLogical = op
+			if record != nil {
+				record(t, ccn-ccnBefore, cognitive-cognitiveBefore)
+			}
+			continue
+		}
+		if record != nil && (ccn != ccnBefore || cognitive != cognitiveBefore) {
+			record(t, ccn-ccnBefore, cognitive-cognitiveBefore)
+		}
+		if t.kind == cppIdent || t.kind == cppNumber || t.kind == cppLiteral || t.text == ")" || t.text == "(" || t.text == "!" {
+			continue
+		}
+		lastLogical = ""
+	}
+
+	return ccn, cognitive, maxNesting
+}
+
+func cppNamespace(tokens []cppToken) string {
+	var parts []string
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].text != "namespace" {
+			continue
+		}
+		j := i + 1
+		if j < len(tokens) && tokens[j].text == "inline" {
+			j++
+		}
+		var name []string
+		for j < len(tokens) && tokens[j].kind == cppIdent {
+			name = append(name, tokens[j].text)
+			j++
+			if j < len(tokens) && tokens[j].text == "::" {
+				j++
+				continue
+			}
+			break
+		}
+		if len(name) == 0 || j >= len(tokens) || tokens[j].text != "{" {
+			if len(parts) > 0 {
+				break
+			}
+			continue
+		}
+		parts = append(parts, name...)
+		next := j + 1
+		if next < len(tokens) && tokens[next].text == "inline" {
+			next++
+		}
+		if next >= len(tokens) || tokens[next].text != "namespace" {
+			break
+		}
+		i = next - 1
+	}
+	return strings.Join(parts, "::")
+}
@@ -0,0 +1,818 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+)
+
+type CSharpParserOptions struct {
+	SkipGeneratedCognitive bool
+}
+
+type CSharpParser struct {
+	opts CSharpParserOptions
+}
+
+func NewCSharpParser() *CSharpParser {
+	return NewCSharpParserWithOptions(CSharpParserOptions{})
+}
+
+func NewCSharpParserWithOptions(opts CSharpParserOptions) *CSharpParser {
+	return &CSharpParser{opts: opts}
+}
+
+var _ ports.CodeParser = (*CSharpParser)(nil)
+
+func (p *CSharpParser) Name() string {
+	return "csharp"
+}
+
+func (p *CSharpParser) Extensions() []string {
+	return []string{".cs"}
+}
+
+func (p *CSharpParser) SupportsFile(path string) bool {
+	return strings.HasSuffix(path, ".cs")
+}
+
+type csharpDeclKind int
+
+const (
+	csharpMember csharpDeclKind = iota
+	csharpLocal
+	csharpLambda
+)
+
+type csharpDecl struct {
+	kind       csharpDeclKind
+	name       string
+	class      string
+	start      int
+	bodyOpen   int
+	bodyClose  int
+	params     int
+	public     bool
+	documented bool
+}
+
+type csharpScope struct {
+	kind  cppScopeKind
+	name  string
+	iface bool
+}
+
+func (p *CSharpParser) ParseFile(path string, src []byte) (*model.FileMetrics, error) {
+	text := string(src)
+	lines := strings.Split(text, "\n")
+
+	totalLines := len(lines)
+	commentLines := estimateCommentLines(lines)
+	density := 0.0
+	if totalLines > 0 {
+		density = float64(commentLines) / float64(totalLines)
+	}
+
+	fm := &model.FileMetrics{
+		Path:     path,
+		Language: model.LanguageCSharp,
+		Comments: model.CommentMetrics{
+			TotalLines:     totalLines,
+			CommentLines:   commentLines,
+			CommentDensity: density,
+		},
+	}
+	setFileLineCounts(fm, lines)
+
+	tokens := tokenizeCSharp(text)
+	match := cppMatchBrackets(tokens)
+	enclosing := csharpEnclosing(tokens, match)
+
+	decls, members, namespace := csharpDeclarations(tokens, match, lines)
+	fm.Package = namespace
+	for _, d := range decls {
+		if d.bodyOpen < len(tokens) && tokens[d.bodyOpen].text == "{" {
+			decls = append(decls, csharpLocalFunctions(tokens, match, d.bodyOpen, d.bodyClose)...)
+		}
+	}
+	decls = append(decls, csharpLambdas(tokens, match, enclosing)...)
+	sort.SliceStable(decls, func(i, j int) bool { return decls[i].start < decls[j].start })
+	nameNestedCSharp(decls, tokens)
+
+	skip := make(map[int]int)
+	known := make(map[string]bool, len(decls)+len(members))
+	for _, d := range decls {
+		if d.kind != csharpMember {
+			skip[d.start] = d.bodyClose
+		}
+		known[d.name] = true
+	}
+	for name := range members {
+		known[name] = true
+	}
+
+	publicDocs := make(map[string]bool)
+	for name, documented := range members {
+		publicDocs[name] = documented
+	}
+
+	var functions []model.FunctionMetrics
+	var allNloc, allCcn, maxCcn int
+	var functionsCcnGt10, functionsCcnGt20 int
+
+	for _, d := range decls {
+		start := tokens[d.start].line
+		end := tokens[d.bodyClose].line
+		nloc := cppSpanLines(tokens, d.start, d.bodyClose, skip)
+		ccn, cognitive, maxNesting := csharpSpanMetrics(tokens, d.bodyOpen, d.bodyClose, skip)
+		_, _, _, _, _, commentLinesFn := computeTextMetricsForRange(lines, start, end, nil)
+
+		commentDensityFn := 0.0
+		if nloc+commentLinesFn > 0 {
+			commentDensityFn = float64(commentLinesFn) / float64(nloc+commentLinesFn)
+		}
+
+		callees := csharpCallees(tokens, d.bodyOpen, d.bodyClose, skip, []string{d.name, d.class}, known)
+		operators := countOperatorsForRange(lines, start, end)
+
+		functions = append(functions, model.FunctionMetrics{
+			Name:                d.name,
+			Signature:           d.name,
+			FilePath:            path,
+			Language:            model.LanguageCSharp,
+			StartLine:           start,
+			EndLine:             end,
+			NLOC:                nloc,
+			Parameters:          d.params,
+			CCN:                 ccn,
+			CognitiveComplexity: cognitive,
+			MaxNesting:          maxNesting,
+			FanOut:              len(callees),
+			CommentDensity:      commentDensityFn,
+			Callees:             callees,
+			Operators:           &operators,
+			IsPublic:            d.public,
+			IsDocumented:        d.documented,
+		})
+		if d.public {
+			publicDocs[d.name] = publicDocs[d.name] || d.documented
+		}
+
+		allNloc += nloc
+		allCcn += ccn
+		if ccn > maxCcn {
+			maxCcn = ccn
+		}
+		if ccn > 10 {
+			functionsCcnGt10++
+		}
+		if ccn > 20 {
+			functionsCcnGt20++
+		}
+	}
+
+	fm.Functions = functions
+	if p.opts.SkipGeneratedCognitive && isGeneratedSource(lines) {
+		zeroCognitive(fm.Functions)
+	}
+
+	fnCount := len(functions)
+	avgCcn := 0.0
+	if fnCount > 0 {
+		avgCcn = float64(allCcn) / float64(fnCount)
+	}
+	fm.Summary = model.FileSummaryMetrics{
+		NLOC:              allNloc,
+		CCNTotal:          allCcn,
+		CCNAvgPerFunction: avgCcn,
+		CCNMaxFunction:    maxCcn,
+		FunctionsCount:    fnCount,
+		FunctionsCCNGt10:  functionsCcnGt10,
+		FunctionsCCNGt20:  functionsCcnGt20,
+	}
+	fm.Summary.LongestFunctionName, fm.Summary.LongestFunctionNLOC = longestFunction(functions)
+
+	for _, documented := range publicDocs {
+		fm.Comments.PublicSymbols++
+		if documented {
+			fm.Comments.PublicDocumented++
+		}
+	}
+	if fm.Comments.PublicSymbols > 0 {
+		fm.Comments.PublicAPIDocPct = float64(fm.Comments.PublicDocumented) / float64(fm.Comments.PublicSymbols)
+	}
+
+	return fm, nil
+}
+
+var csharpTypeKeywords = map[string]bool{
+	"class": true, "struct": true, "interface": true, "record": true, "enum": true,
+}
+
+func csharpDeclarations(tokens []cppToken, match []int, lines []string) ([]csharpDecl, map[string]bool, string) {
+	var decls []csharpDecl
+	members := make(map[string]bool)
+	var scopes []csharpScope
+	namespace := ""
+
+	classChain := func() string {
+		var names []string
+		for _, s := range scopes {
+			if s.kind == cppScopeClass {
+				names = append(names, s.name)
+			}
+		}
+		return strings.Join(names, ".")
+	}
+	isPublic := func(from, to int) bool {
+		if n := len(scopes); n > 0 && scopes[n-1].iface {
+			return !cppHasToken(tokens, from, to, "private")
+		}
+		return cppHasToken(tokens, from, to, "public")
+	}
+
+	stmtStart := 0
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		inType := len(scopes) > 0 && scopes[len(scopes)-1].kind == cppScopeClass
+
+		if i == stmtStart && t.text == "[" && match[i] > i {
+			i = match[i]
+			stmtStart = i + 1
+			continue
+		}
+
+		switch t.text {
+		case ";":
+			switch {
+			case tokens[stmtStart].text == "namespace" && namespace == "":
+				namespace = csharpDottedName(tokens, stmtStart+1, i)
+			case inType && isPublic(stmtStart, i):
+				if name := csharpMemberName(tokens, match, stmtStart, i); name != "" {
+					members[qualifyCSharp(classChain(), name)] = hasXMLDocComment(lines, tokens[stmtStart].line)
+				}
+			}
+			stmtStart = i + 1
+			continue
+		case "}":
+			if len(scopes) > 0 {
+				scopes = scopes[:len(scopes)-1]
+			}
+			stmtStart = i + 1
+			continue
+		case "=>":
+			if !inType || csharpHasAssignment(tokens, match, stmtStart, i) {
+				continue
+			}
+			name, paren, ok := csharpDeclaratorName(tokens, match, stmtStart, i)
+			params := 0
+			if ok {
+				params = cppCountParams(tokens, match, paren)
+			} else {
+				name = csharpMemberName(tokens, match, stmtStart, i)
+			}
+			end := csharpExpressionEnd(tokens, match, i+1)
+			public := isPublic(stmtStart, i)
+			decls = append(decls, csharpDecl{
+				name:       qualifyCSharp(classChain(), name),
+				class:      classChain(),
+				start:      stmtStart,
+				bodyOpen:   i,
+				bodyClose:  end,
+				params:     params,
+				public:     public,
+				documented: hasXMLDocComment(lines, tokens[stmtStart].line),
+			})
+			i = end
+			continue
+		case "{":
+		default:
+			continue
+		}
+
+		close := match[i]
+		if close < 0 {
+			continue
+		}
+
+		if tokens[stmtStart].text == "namespace" {
+			name := csharpDottedName(tokens, stmtStart+1, i)
+			if namespace == "" {
+				namespace = name
+			}
+			scopes = append(scopes, csharpScope{kind: cppScopeNamespace, name: name})
+			stmtStart = i + 1
+			continue
+		}
+		if key, name := csharpTypeHead(tokens, match, stmtStart, i); key != "" {
+			if key == "enum" {
+				i = close
+				stmtStart = close + 1
+				continue
+			}
+			scopes = append(scopes, csharpScope{kind: cppScopeClass, name: name, iface: key == "interface"})
+			stmtStart = i + 1
+			continue
+		}
+		if !inType || csharpHasAssignment(tokens, match, stmtStart, i) {
+			i = close
+			stmtStart = close + 1
+			continue
+		}
+
+		public := isPublic(stmtStart, i)
+		documented := hasXMLDocComment(lines, tokens[stmtStart].line)
+		name, paren, ok := csharpDeclaratorName(tokens, match, stmtStart, i)
+		params := 0
+		if ok {
+			params = cppCountParams(tokens, match, paren)
+		} else {
+			name = csharpMemberName(tokens, match, stmtStart, i)
+			if !cppHasToken(tokens, i+1, close, "{", "=>") {
+				if public && name != "" {
+					members[qualifyCSharp(classChain(), name)] = documented
+				}
+				i = close
+				stmtStart = close + 1
+				continue
+			}
+		}
+
+		decls = append(decls, csharpDecl{
+			name:       qualifyCSharp(classChain(), name),
+			class:      classChain(),
+			start:      stmtStart,
+			bodyOpen:   i,
+			bodyClose:  close,
+			params:     params,
+			public:     public,
+			documented: documented,
+		})
+		i = close
+		stmtStart = close + 1
+	}
+	return decls, members, namespace
+}
+
+func qualifyCSharp(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func csharpDottedName(tokens []cppToken, from, to int) string {
+	var parts []string
+	for k := from; k < to; k++ {
+		if tokens[k].kind == cppIdent {
+			parts = append(parts, tokens[k].text)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+func csharpTypeHead(tokens []cppToken, match []int, from, to int) (key, name string) {
+	for k := from; k < to; k++ {
+		t := tokens[k]
+		if t.text == "(" || t.text == "=" || t.text == "=>" {
+			return "", ""
+		}
+		if t.templateArg || !csharpTypeKeywords[t.text] {
+			continue
+		}
+		for j := k + 1; j < to; j++ {
+			if tokens[j].kind == cppIdent && !csharpTypeKeywords[tokens[j].text] {
+				return t.text, tokens[j].text
+			}
+		}
+		return t.text, ""
+	}
+	return "", ""
+}
+
+func csharpHasAssignment(tokens []cppToken, match []int, from, to int) bool {
+	for k := from; k < to; k++ {
+		switch t := tokens[k]; {
+		case (t.text == "(" || t.text == "[") && match[k] > k:
+			k = match[k]
+		case t.text == "=" && !t.templateArg && !t.operatorName:
+			return true
+		}
+	}
+	return false
+}
+
+var csharpModifiers = map[string]bool{
+	"public": true, "private": true, "protected": true, "internal": true, "static": true,
+	"virtual": true, "override": true, "abstract": true, "sealed": true, "async": true,
+	"extern": true, "unsafe": true, "new": true, "readonly": true, "partial": true,
+}
+
+func csharpDeclaratorName(tokens []cppToken, match []int, from, to int) (string, int, bool) {
+	name, paren, ok := cppDeclaratorName(tokens, match, from, to)
+	for ok && csharpModifiers[name] && match[paren]+1 < to {
+		name, paren, ok = cppDeclaratorName(tokens, match, match[paren]+1, to)
+	}
+	return name, paren, ok && !csharpModifiers[name]
+}
+
+func csharpMemberName(tokens []cppToken, match []int, from, to int) string {
+	if name, _, ok := csharpDeclaratorName(tokens, match, from, to); ok {
+		return name
+	}
+	end := to
+	for k := from; k < to; k++ {
+		if tokens[k].text == "=" && !tokens[k].templateArg {
+			end = k
+			break
+		}
+	}
+	for k := end - 1; k >= from; k-- {
+		t := tokens[k]
+		if t.text == "]" && match[k] >= from {
+			k = match[k]
+			continue
+		}
+		if t.kind == cppIdent && !t.templateArg {
+			return t.text
+		}
+	}
+	return ""
+}
+
+func hasXMLDocComment(lines []string, start int) bool {
+	for i := start - 2; i >= 0 && i < len(lines); i-- {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "[") {
+			continue
+		}
+		return strings.HasPrefix(line, "///")
+	}
+	return false
+}
+
+func csharpEnclosing(tokens []cppToken, match []int) []int {
+	enclosing := make([]int, len(tokens))
+	var stack []int
+	for i, t := range tokens {
+		enclosing[i] = -1
+		if n := len(stack); n > 0 {
+			enclosing[i] = stack[n-1]
+		}
+		if t.kind != cppPunct || match[i] < 0 {
+			continue
+		}
+		switch t.text {
+		case "(", "[", "{":
+			stack = append(stack, i)
+		case ")", "]", "}":
+			if n := len(stack); n > 0 && stack[n-1] == match[i] {
+				stack = stack[:n-1]
+			}
+		}
+	}
+	return enclosing
+}
+
+func csharpExpressionEnd(tokens []cppToken, match []int, from int) int {
+	for j := from; j < len(tokens); j++ {
+		t := tokens[j]
+		if t.kind != cppPunct || t.templateArg || t.operatorName {
+			continue
+		}
+		switch t.text {
+		case "(", "[", "{":
+			if match[j] > j {
+				j = match[j]
+			}
+		case ",", ";", ")", "]", "}":
+			return j - 1
+		}
+	}
+	return len(tokens) - 1
+}
+
+func csharpBody(tokens []cppToken, match []int, arrow int) (open, close int) {
+	if next := arrow + 1; next < len(tokens) && tokens[next].text == "{" && match[next] > next {
+		return next, match[next]
+	}
+	return arrow, csharpExpressionEnd(tokens, match, arrow+1)
+}
+
+var csharpNonNameKeywords = map[string]bool{
+	"if": true, "for": true, "foreach": true, "while": true, "switch": true, "catch": true,
+	"using": true, "lock": true, "fixed": true, "return": true, "await": true, "new": true,
+	"throw": true, "else": true, "yield": true, "case": true, "nameof": true, "typeof": true,
+	"sizeof": true, "default": true, "checked": true, "unchecked": true, "when": true,
+	"base": true, "this": true, "is": true, "as": true, "in": true, "out": true, "ref": true,
+}
+
+func csharpLocalFunctions(tokens []cppToken, match []int, from, to int) []csharpDecl {
+	var decls []csharpDecl
+	stmtStart := from + 1
+	for i := from + 1; i < to; i++ {
+		t := tokens[i]
+		if t.kind != cppPunct || t.templateArg {
+			continue
+		}
+		switch t.text {
+		case ";", "}":
+			stmtStart = i + 1
+			continue
+		case "{", "=>":
+		default:
+			continue
+		}
+
+		paren := -1
+		for k := stmtStart; k < i; k++ {
+			if tokens[k].text == "(" && !tokens[k].templateArg {
+				paren = k
+				break
+			}
+		}
+		if paren > stmtStart && match[paren] > 0 && match[paren] < i && csharpIsLocalSignature(tokens, stmtStart, paren, match[paren], i) {
+			name, _, _ := cppQualifiedNameBefore(tokens, paren)
+			open, close := i, match[i]
+			if t.text == "=>" {
+				open, close = csharpBody(tokens, match, i)
+			}
+			decls = append(decls, csharpDecl{
+				kind:      csharpLocal,
+				name:      name,
+				start:     stmtStart,
+				bodyOpen:  open,
+				bodyClose: close,
+				params:    cppCountParams(tokens, match, paren),
+			})
+		}
+		if t.text == "{" {
+			stmtStart = i + 1
+		}
+	}
+	return decls
+}
+
+func csharpIsLocalSignature(tokens []cppToken, stmtStart, paren, closeParen, body int) bool {
+	if next := closeParen + 1; next < body && tokens[next].text != "where" {
+		return false
+	}
+	name, first, ok := cppQualifiedNameBefore(tokens, paren)
+	if !ok || strings.Contains(name, "::") || csharpNonNameKeywords[name] || first <= stmtStart {
+		return false
+	}
+	prev := tokens[first-1]
+	switch {
+	case prev.kind == cppIdent:
+		return !csharpNonNameKeywords[prev.text]
+	case prev.text == ">" && prev.templateArg, prev.text == "]", prev.text == "?":
+		return true
+	}
+	return false
+}
+
+var csharpLambdaPrefix = map[string]bool{
+	"async": true, "static": true, "return": true, "await": true, "throw": true,
+}
+
+func csharpLambdas(tokens []cppToken, match, enclosing []int) []csharpDecl {
+	var decls []csharpDecl
+	for k := 1; k < len(tokens); k++ {
+		if tokens[k].text != "=>" {
+			continue
+		}
+		if e := enclosing[k]; e > 0 && tokens[e].text == "{" && tokens[e-1].text == "switch" {
+			continue
+		}
+
+		prev := tokens[k-1]
+		intro, params := -1, 0
+		switch {
+		case prev.text == ")" && match[k-1] >= 0:
+			open := match[k-1]
+			if open > 0 {
+				b := tokens[open-1]
+				if (b.kind == cppIdent && !csharpLambdaPrefix[b.text]) || b.templateArg || b.operatorName {
+					continue
+				}
+			}
+			intro, params = open, cppCountParams(tokens, match, open)
+		case prev.kind == cppIdent:
+			if k >= 2 {
+				b := tokens[k-2]
+				if (b.kind == cppIdent && !csharpLambdaPrefix[b.text]) || b.templateArg ||
+					b.text == "." || b.text == "?" || b.text == "]" {
+					continue
+				}
+				if csharpAccessors[prev.text] && (b.text == "{" || b.text == ";" || b.text == "}") {
+					continue
+				}
+			}
+			intro, params = k-1, 1
+		default:
+			continue
+		}
+		for intro > 0 && (tokens[intro-1].text == "async" || tokens[intro-1].text == "static") {
+			intro--
+		}
+
+		open, close := csharpBody(tokens, match, k)
+		decls = append(decls, csharpDecl{
+			kind:      csharpLambda,
+			start:     intro,
+			bodyOpen:  open,
+			bodyClose: close,
+			params:    params,
+		})
+	}
+	return decls
+}
+
+func nameNestedCSharp(decls []csharpDecl, tokens []cppToken) {
+	for i := range decls {
+		if decls[i].kind == csharpMember {
+			continue
+		}
+		name := decls[i].name
+		if decls[i].kind == csharpLambda {
+			name = fmt.Sprintf("lambda@%d", tokens[decls[i].start].line)
+		}
+		for j := i - 1; j >= 0; j-- {
+			if decls[j].start < decls[i].start && decls[i].bodyClose <= decls[j].bodyClose {
+				name = decls[j].name + "." + name
+				decls[i].class = decls[j].class
+				break
+			}
+		}
+		decls[i].name = name
+	}
+}
+
+var csharpBlockOpeners = map[string]bool{
+	")": true, "else": true, "do": true, "try": true, "finally": true, "=>": true,
+	"{": true, ";": true, "}": true, ":": true, "checked": true, "unchecked": true, "unsafe": true,
+}
+
+var csharpAccessors = map[string]bool{
+	"get": true, "set": true, "init": true, "add": true, "remove": true,
+}
+
+const (
+	csharpPlainBrace = iota
+	csharpNestingBlock
+	csharpFlatBlock
+)
+
+func csharpSpanMetrics(tokens []cppToken, from, to int, skip map[int]int) (ccn, cognitive, maxNesting int) {
+	ccn = 1
+	depth := 1
+	maxNesting = 1
+
+	var blocks []int
+	var lastLogical string
+
+	for i := from + 1; i <= to && i < len(tokens); i++ {
+		if end, ok := skip[i]; ok {
+			i = end
+			continue
+		}
+		t := tokens[i]
+
+		if t.kind == cppPunct && t.text == "{" {
+			kind := csharpPlainBrace
+			switch {
+			case len(blocks) > 0 && blocks[len(blocks)-1] == csharpPlainBrace:
+			case len(blocks) == 0 && csharpAccessors[tokens[i-1].text]:
+				kind = csharpFlatBlock
+			case csharpBlockOpeners[tokens[i-1].text]:
+				kind = csharpNestingBlock
+			}
+			blocks = append(blocks, kind)
+			if kind == csharpNestingBlock {
+				depth++
+				if depth > maxNesting {
+					maxNesting = depth
+				}
+			}
+			continue
+		}
+		if t.kind == cppPunct && t.text == "}" {
+			if n := len(blocks); n > 0 {
+				if blocks[n-1] == csharpNestingBlock {
+					depth--
+				}
+				blocks = blocks[:n-1]
+			}
+			continue
+		}
+		if t.templateArg || t.operatorName {
+			continue
+		}
+
+		nesting := depth - 1
+		switch t.text {
+		case "if", "for", "foreach", "while", "catch":
+			ccn++
+			cognitive += 1 + nesting
+			if t.text == "if" && tokens[i-1].text == "else" {
+				cognitive -= 1 + nesting
+			}
+		case "switch":
+			ccn++
+			cognitive += 1 + nesting
+		case "case":
+			ccn++
+		case "else":
+			cognitive++
+		case "??", "??=", "?.":
+			ccn++
+		case "&&", "||":
+			ccn++
+			if t.text != lastLogical {
+				cognitive++
+			}
+			lastLogical = t.text
+			continue
+		}
+		if t.kind == cppIdent || t.kind == cppNumber || t.kind == cppLiteral || t.text == ")" || t.text == "(" || t.text == "!" {
+			continue
+		}
+		lastLogical = ""
+	}
+
+	return ccn, cognitive, maxNesting
+}
+
+func csharpCallees(tokens []cppToken, from, to int, skip map[int]int, scopes []string, known map[string]bool) []string {
+	seen := make(map[string]struct{})
+	for i := from + 1; i < to; i++ {
+		if end, ok := skip[i]; ok {
+			i = end
+			continue
+		}
+		t := tokens[i]
+		if t.text != "(" || t.kind != cppPunct || t.templateArg || t.operatorName {
+			continue
+		}
+
+		j := i - 1
+		for j >= 0 && tokens[j].templateArg {
+			j--
+		}
+		if j < 0 || tokens[j].kind != cppIdent || csharpNonNameKeywords[tokens[j].text] {
+			continue
+		}
+		parts := []string{tokens[j].text}
+		first := j
+		for first >= 2 && tokens[first-1].text == "." && tokens[first-2].kind == cppIdent {
+			first -= 2
+			parts = append([]string{tokens[first].text}, parts...)
+		}
+
+		if first > 0 {
+			prev := tokens[first-1]
+			switch {
+			case prev.text == "." || prev.text == "?." || prev.text == ")" || prev.text == "]":
+				parts = parts[len(parts)-1:]
+			case prev.kind == cppIdent && !csharpLambdaPrefix[prev.text] && !cppCallPrefixKeywords[prev.text]:
+				continue
+			case prev.text == ">" && prev.templateArg:
+				continue
+			}
+		}
+
+		switch parts[0] {
+		case "this", "base":
+			parts = parts[1:]
+			if len(parts) == 0 {
+				continue
+			}
+		}
+		if first := parts[0]; len(parts) > 1 && (first[0] < 'A' || first[0] > 'Z') {
+			parts = parts[len(parts)-1:]
+		}
+		name := strings.Join(parts, ".")
+		if len(parts) == 1 {
+			for _, scope := range scopes {
+				if qualified := qualifyCSharp(scope, name); scope != "" && known[qualified] {
+					name = qualified
+					break
+				}
+			}
+		}
+		seen[name] = struct{}{}
+	}
+
+	out := make([]string, 0, len(seen))
+	for name := range seen {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"strings"
+)
+
+var csharpPunctuators = []string{
+	"??=", "<<=", ">>=",
+	"??", "?.", "=>", "::", "->", "&&", "||", "<<", "==", "!=", "<=", ">=", "++", "--",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=",
+}
+
+func tokenizeCSharp(src string) []cppToken {
+	var tokens []cppToken
+	line := 1
+	atLineStart := true
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == '\n':
+			line++
+			atLineStart = true
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+			continue
+		case c == '#' && atLineStart:
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+			continue
+		}
+		atLineStart = false
+
+		if end, ok := csharpStringEnd(src, i); ok {
+			tokens = append(tokens, cppToken{kind: cppLiteral, text: src[i:end], line: line})
+			line += strings.Count(src[i:end], "\n")
+			i = end
+			continue
+		}
+
+		if c == '\'' {
+			start := i
+			i = cppSkipQuoted(src, i+1, '\'')
+			tokens = append(tokens, cppToken{kind: cppLiteral, text: src[start:i], line: line})
+			continue
+		}
+
+		if isIdentStart(c) || (c == '@' && i+1 < len(src) && isIdentStart(src[i+1])) {
+			if c == '@' {
+				i++
+			}
+			start := i
+			for i < len(src) && isIdentPart(src[i]) {
+				i++
+			}
+			tokens = append(tokens, cppToken{kind: cppIdent, text: src[start:i], line: line})
+			continue
+		}
+
+		if isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])) {
+			start := i
+			for i < len(src) && (isIdentPart(src[i]) || src[i] == '.' ||
+				((src[i] == '+' || src[i] == '-') && (src[i-1] == 'e' || src[i-1] == 'E'))) {
+				i++
+			}
+			tokens = append(tokens, cppToken{kind: cppNumber, text: src[start:i], line: line})
+			continue
+		}
+
+		text := src[i : i+1]
+		for _, p := range csharpPunctuators {
+			if strings.HasPrefix(src[i:], p) {
+				text = p
+				break
+			}
+		}
+		tokens = append(tokens, cppToken{kind: cppPunct, text: text, line: line})
+		i += len(text)
+	}
+
+	markOperatorNames(tokens)
+	markTemplateArguments(tokens)
+	return tokens
+}
+
+func csharpStringEnd(src string, i int) (int, bool) {
+	j := i
+	interpolated, verbatim := false, false
+	for j < len(src) && (src[j] == '$' || src[j] == '@') {
+		if src[j] == '$' {
+			interpolated = true
+		} else {
+			verbatim = true
+		}
+		j++
+	}
+	if j >= len(src) || src[j] != '"' {
+		return i, false
+	}
+
+	quotes := 0
+	for j+quotes < len(src) && src[j+quotes] == '"' {
+		quotes++
+	}
+	if quotes >= 3 {
+		closing := strings.Repeat(`"`, quotes)
+		end := strings.Index(src[j+quotes:], closing)
+		if end < 0 {
+			return len(src), true
+		}
+		return j + quotes + end + quotes, true
+	}
+
+	depth := 0
+	for j++; j < len(src); j++ {
+		c := src[j]
+		switch {
+		case depth > 0:
+			switch c {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			case '"', '$', '@':
+				if end, ok := csharpStringEnd(src, j); ok {
+					j = end - 1
+				}
+			case '\'':
+				j = cppSkipQuoted(src, j+1, '\'') - 1
+			}
+		case interpolated && c == '{':
+			if j+1 < len(src) && src[j+1] == '{' {
+				j++
+				continue
+			}
+			depth++
+		case !verbatim && c == '\\':
+			j++
+		case !verbatim && c == '\n':
+			return j, true
+		case c == '"':
+			if verbatim && j+1 < len(src) && src[j+1] == '"' {
+				j++
+				continue
+			}
+			return j + 1, true
+		}
+	}
+	return len(src), true
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+)
+
+var (
+	_ ports.FunctionExplainer = (*GoParser)(nil)
+	_ ports.FunctionExplainer = (*CParser)(nil)
+	_ ports.FunctionExplainer = (*CppParser)(nil)
+)
+
+func (p *GoParser) ExplainFunction(path string, src []byte, function string) (*model.FunctionExplanation, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(src), "\n")
+
+	for _, decl := range file.Decls {
+		fdecl, ok := decl.(*ast.FuncDecl)
+		if !ok || fdecl.Body == nil || fdecl.Name.Name != function {
+			continue
+		}
+		start := fset.Position(fdecl.Pos()).Line
+		end := fset.Position(fdecl.End()).Line
+		if end > len(lines) {
+			end = len(lines)
+		}
+		excludes := funcLitExcludes(fset, collectFuncLits(fdecl.Body), start, end)
+
+		exp := &model.FunctionExplanation{FilePath: path, Function: function, StartLine: start, EndLine: end, BaseCCN: 1}
+		_, exp.CCN, exp.Cognitive, _, _, _ = computeTextMetricsForRangeWithExcludes(lines, start, end, excludes,
+			func(c model.LineContribution) { exp.Lines = append(exp.Lines, c) })
+		return exp, nil
+	}
+	return nil, fmt.Errorf("function %s not found in %s", function, path)
+}
+
+func (p *CParser) ExplainFunction(path string, src []byte, function string) (*model.FunctionExplanation, error) {
+	return p.scanner.explain(path, src, function, isCppSource(path) || isCppHeader(path, string(src)))
+}
+
+func (p *lineScanner) explain(path string, src []byte, function string, cpp bool) (*model.FunctionExplanation, error) {
+	fm, err := p.parse(path, src, cpp)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(src), "\n")
+
+	for _, fn := range fm.Functions {
+		if fn.Name != function {
+			continue
+		}
+		exp := &model.FunctionExplanation{FilePath: path, Function: function, StartLine: fn.StartLine, EndLine: fn.EndLine, BaseCCN: 1}
+		_, exp.CCN, exp.Cognitive, _, _, _ = computeTextMetricsForRange(lines, fn.StartLine, fn.EndLine,
+			func(c model.LineContribution) { exp.Lines = append(exp.Lines, c) })
+		return exp, nil
+	}
+	return nil, fmt.Errorf("function %s not found in %s", function, path)
+}
+
+func (p *CppParser) ExplainFunction(path string, src []byte, function string) (*model.FunctionExplanation, error) {
+	if p.line != nil {
+		return p.line.explain(path, src, function, true)
+	}
+	lines := strings.Split(string(src), "\n")
+	tokens := tokenizeCpp(string(src))
+	decls, _, skip := p.declarations(tokens, cppMatchBrackets(tokens), lines)
+
+	for _, d := range decls {
+		if d.name != function {
+			continue
+		}
+		exp := &model.FunctionExplanation{
+			FilePath:  path,
+			Function:  function,
+			StartLine: tokens[d.start].line,
+			EndLine:   tokens[d.bodyClose].line,
+			BaseCCN:   1,
+		}
+		byLine := make(map[int]int)
+		exp.CCN, exp.Cognitive, _ = cppSpanMetricsRecorded(tokens, d.bodyOpen, d.bodyClose, skip, func(t cppToken, ccn, cognitive int) {
+			idx, ok := byLine[t.line]
+			if !ok {
+				code := ""
+				if t.line-1 < len(lines) {
+					code = strings.TrimSpace(lines[t.line-1])
+				}
+				exp.Lines = append(exp.Lines, model.LineContribution{Line: t.line, Code: code})
+				idx = len(exp.Lines) - 1
+				byLine[t.line] = idx
+			}
+			l := &exp.Lines[idx]
+			l.CCN += ccn
+			l.Cognitive += cognitive
+			l.Constructs = append(l.Constructs, t.text)
+		})
+		return exp, nil
+	}
+	return nil, fmt.Errorf("function %s not found in %s", function, path)
+}
@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+)
+
+func goClosureNames(fdecl *ast.FuncDecl) map[*ast.FuncLit]string {
+	names := make(map[*ast.FuncLit]string)
+	var walk func(body *ast.BlockStmt, prefix string)
+	walk = func(body *ast.BlockStmt, prefix string) {
+		n := 0
+		ast.Inspect(body, func(node ast.Node) bool {
+			lit, ok := node.(*ast.FuncLit)
+			if !ok {
+				return true
+			}
+			n++
+			name := fmt.Sprintf("%s%d", prefix, n)
+			names[lit] = name
+			walk(lit.Body, name+".")
+			return false
+		})
+	}
+	if fdecl.Body != nil {
+		walk(fdecl.Body, goQualifiedFuncName(fdecl)+".func")
+	}
+	return names
+}
+
+func goQualifiedFuncName(fdecl *ast.FuncDecl) string {
+	if fdecl.Recv == nil || len(fdecl.Recv.List) == 0 {
+		return fdecl.Name.Name
+	}
+	typ := fdecl.Recv.List[0].Type
+	pointer := false
+	if star, ok := typ.(*ast.StarExpr); ok {
+		pointer = true
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+	recv := "?"
+	if ident, ok := typ.(*ast.Ident); ok {
+		recv = ident.Name
+	}
+	if pointer {
+		return fmt.Sprintf("(*%s).%s", recv, fdecl.Name.Name)
+	}
+	return recv + "." + fdecl.Name.Name
+}
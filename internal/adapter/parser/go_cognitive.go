@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+const (
+	CognitiveModelLine  = "line"
+	CognitiveModelSonar = "sonar"
+)
+
+func ValidateCognitiveModel(name string) error {
+	switch name {
+	case "", CognitiveModelLine, CognitiveModelSonar:
+		return nil
+	default:
+		return fmt.Errorf("unknown cognitive model %q (want %s or %s)", name, CognitiveModelLine, CognitiveModelSonar)
+	}
+}
+
+type sonarCognitive struct {
+	score int
+}
+
+func sonarCognitiveComplexity(body *ast.BlockStmt) int {
+	if body == nil {
+		return 0
+	}
+	c := &sonarCognitive{}
+	c.block(body, 0)
+	return c.score
+}
+
+func (c *sonarCognitive) block(b *ast.BlockStmt, nesting int) {
+	if b == nil {
+		return
+	}
+	for _, stmt := range b.List {
+		c.stmt(stmt, nesting)
+	}
+}
+
+func (c *sonarCognitive) stmt(s ast.Stmt, nesting int) {
+	switch s := s.(type) {
+	case *ast.IfStmt:
+		c.score += 1 + nesting
+		c.ifChain(s, nesting)
+	case *ast.ForStmt:
+		c.score += 1 + nesting
+		c.stmt(s.Init, nesting)
+		c.expr(s.Cond, nesting)
+		c.stmt(s.Post, nesting)
+		c.block(s.Body, nesting+1)
+	case *ast.RangeStmt:
+		c.score += 1 + nesting
+		c.expr(s.X, nesting)
+		c.block(s.Body, nesting+1)
+	case *ast.SwitchStmt:
+		c.score += 1 + nesting
+		c.stmt(s.Init, nesting)
+		c.expr(s.Tag, nesting)
+		c.clauses(s.Body, nesting+1)
+	case *ast.TypeSwitchStmt:
+		c.score += 1 + nesting
+		c.stmt(s.Init, nesting)
+		c.clauses(s.Body, nesting+1)
+	case *ast.SelectStmt:
+		c.score += 1 + nesting
+		c.clauses(s.Body, nesting+1)
+	case *ast.BranchStmt:
+		if s.Tok == token.GOTO || s.Label != nil {
+			c.score++
+		}
+	case *ast.LabeledStmt:
+		c.stmt(s.Stmt, nesting)
+	case *ast.BlockStmt:
+		c.block(s, nesting)
+	case *ast.ExprStmt:
+		c.expr(s.X, nesting)
+	case *ast.AssignStmt:
+		for _, e := range s.Rhs {
+			c.expr(e, nesting)
+		}
+	case *ast.ReturnStmt:
+		for _, e := range s.Results {
+			c.expr(e, nesting)
+		}
+	case *ast.DeferStmt:
+		c.expr(s.Call, nesting)
+	case *ast.GoStmt:
+		c.expr(s.Call, nesting)
+	case *ast.DeclStmt:
+		ast.Inspect(s, func(n ast.Node) bool {
+			if e, ok := n.(ast.Expr); ok {
+				c.expr(e, nesting)
+				return false
+			}
+			return true
+		})
+	}
+}
+
+func (c *sonarCognitive) ifChain(s *ast.IfStmt, nesting int) {
+	c.stmt(s.Init, nesting)
+	c.expr(s.Cond, nesting)
+	c.block(s.Body, nesting+1)
+	switch e := s.Else.(type) {
+	case *ast.IfStmt:
+		c.score++
+		c.ifChain(e, nesting)
+	case *ast.BlockStmt:
+		c.score++
+		c.block(e, nesting+1)
+	}
+}
+
+func (c *sonarCognitive) clauses(b *ast.BlockStmt, nesting int) {
+	for _, s := range b.List {
+		switch cl := s.(type) {
+		case *ast.CaseClause:
+			for _, e := range cl.List {
+				c.expr(e, nesting)
+			}
+			for _, st := range cl.Body {
+				c.stmt(st, nesting)
+			}
+		case *ast.CommClause:
+			c.stmt(cl.Comm, nesting)
+			for _, st := range cl.Body {
+				c.stmt(st, nesting)
+			}
+		}
+	}
+}
+
+func (c *sonarCognitive) expr(e ast.Expr, nesting int) {
+	if e == nil {
+		return
+	}
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				c.score += logicalSequences(n, token.ILLEGAL)
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func logicalSequences(e ast.Expr, parent token.Token) int {
+	switch n := e.(type) {
+	case *ast.ParenExpr:
+		return logicalSequences(n.X, token.ILLEGAL)
+	case *ast.BinaryExpr:
+		if n.Op != token.LAND && n.Op != token.LOR {
+			return 0
+		}
+		score := 0
+		if n.Op != parent {
+			score = 1
+		}
+		return score + logicalSequences(n.X, n.Op) + logicalSequences(n.Y, n.Op)
+	default:
+		return 0
+	}
+}
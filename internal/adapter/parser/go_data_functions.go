@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+const (
+	minDataFunctionLines = 10
+	dataLiteralShare     = 0.8
+)
+
+func isGoDataFunction(fset *token.FileSet, fdecl *ast.FuncDecl) bool {
+	if fdecl.Body == nil {
+		return false
+	}
+	bodyLines := fset.Position(fdecl.Body.Rbrace).Line - fset.Position(fdecl.Body.Lbrace).Line - 1
+	if bodyLines < minDataFunctionLines {
+		return false
+	}
+
+	largest := 0
+	ast.Inspect(fdecl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CompositeLit:
+			if span := fset.Position(node.End()).Line - fset.Position(node.Pos()).Line + 1; span > largest {
+				largest = span
+			}
+			return false
+		}
+		return true
+	})
+	return float64(largest) >= dataLiteralShare*float64(bodyLines)
+}
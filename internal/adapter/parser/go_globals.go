@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+type globalAccess struct {
+	Reads     int
+	Writes    int
+	Written   []string
+	FirstLine int
+
+	Unresolved []model.IdentifierRef
+}
+
+func findGlobalAccess(fset *token.FileSet, fdecl *ast.FuncDecl, typed goFileTypes) globalAccess {
+	var acc globalAccess
+	if fdecl.Body == nil {
+		return acc
+	}
+	isGlobal := typed.packageVar
+
+	written := make(map[*ast.Ident]struct{})
+	seen := make(map[string]struct{})
+	markWrite := func(expr ast.Expr) {
+		id := rootIdent(expr)
+		if typed.unresolved(id) {
+			written[id] = struct{}{}
+			acc.Unresolved = append(acc.Unresolved, model.IdentifierRef{Name: id.Name, Line: fset.Position(id.Pos()).Line, Write: true})
+			return
+		}
+		if !isGlobal(id) {
+			return
+		}
+		written[id] = struct{}{}
+		acc.Writes++
+		if _, ok := seen[id.Name]; !ok {
+			seen[id.Name] = struct{}{}
+			acc.Written = append(acc.Written, id.Name)
+		}
+		if line := fset.Position(id.Pos()).Line; acc.FirstLine == 0 || line < acc.FirstLine {
+			acc.FirstLine = line
+		}
+	}
+
+	ast.Inspect(fdecl.Body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.AssignStmt:
+			if s.Tok != token.DEFINE {
+				for _, lhs := range s.Lhs {
+					markWrite(lhs)
+				}
+			}
+		case *ast.IncDecStmt:
+			markWrite(s.X)
+		}
+		return true
+	})
+
+	members := make(map[*ast.Ident]struct{})
+	ast.Inspect(fdecl.Body, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.SelectorExpr:
+			members[e.Sel] = struct{}{}
+		case *ast.KeyValueExpr:
+			if id, ok := e.Key.(*ast.Ident); ok {
+				members[id] = struct{}{}
+			}
+		}
+		return true
+	})
+
+	ast.Inspect(fdecl.Body, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		if _, w := written[id]; w {
+			return true
+		}
+		if isGlobal(id) {
+			acc.Reads++
+		} else if _, member := members[id]; !member && typed.unresolved(id) {
+			acc.Unresolved = append(acc.Unresolved, model.IdentifierRef{Name: id.Name, Line: fset.Position(id.Pos()).Line})
+		}
+		return true
+	})
+	return acc
+}
+
+func rootIdent(expr ast.Expr) *ast.Ident {
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return e
+		case *ast.SelectorExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+const (
+	minIdentifiersForNamingSmell = 4
+	crypticIdentifierShare       = 0.5
+)
+
+var loopIdentifiers = map[string]bool{"i": true, "j": true, "k": true}
+
+type identifierStats struct {
+	Declared int
+	AvgLen   float64
+	Short    int
+	ShortIDs []string
+}
+
+func (s identifierStats) cryptic() bool {
+	return s.Declared >= minIdentifiersForNamingSmell &&
+		float64(s.Short) > float64(s.Declared)*crypticIdentifierShare
+}
+
+func functionIdentifierStats(fdecl *ast.FuncDecl, typed goFileTypes) identifierStats {
+	var stats identifierStats
+
+	receivers := make(map[types.Object]bool)
+	if fdecl.Recv != nil {
+		for _, field := range fdecl.Recv.List {
+			for _, name := range field.Names {
+				receivers[typed.info.Defs[name]] = true
+			}
+		}
+	}
+
+	seen := make(map[types.Object]bool)
+	total := 0
+	ast.Inspect(fdecl, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok || id == fdecl.Name || id.Name == "_" {
+			return true
+		}
+		obj := typed.info.Defs[id]
+		if obj == nil || seen[obj] || receivers[obj] {
+			return true
+		}
+		switch obj.(type) {
+		case *types.Var, *types.Const:
+		default:
+			return true
+		}
+		seen[obj] = true
+
+		stats.Declared++
+		total += len(id.Name)
+		if len(id.Name) == 1 && !loopIdentifiers[id.Name] {
+			stats.Short++
+			stats.ShortIDs = append(stats.ShortIDs, id.Name)
+		}
+		return true
+	})
+
+	if stats.Declared > 0 {
+		stats.AvgLen = float64(total) / float64(stats.Declared)
+	}
+	return stats
+}
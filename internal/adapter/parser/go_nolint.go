@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+const DefaultNolintPrefix = "nolint"
+
+type suppression struct {
+	all   bool
+	kinds map[model.CodeSmellKind]struct{}
+}
+
+func (s *suppression) merge(other suppression) {
+	s.all = s.all || other.all
+	for kind := range other.kinds {
+		if s.kinds == nil {
+			s.kinds = make(map[model.CodeSmellKind]struct{})
+		}
+		s.kinds[kind] = struct{}{}
+	}
+}
+
+func (s suppression) covers(kind model.CodeSmellKind) bool {
+	if s.all {
+		return true
+	}
+	_, ok := s.kinds[kind]
+	return ok
+}
+
+func (s suppression) list() []model.CodeSmellKind {
+	var out []model.CodeSmellKind
+	for _, kind := range model.AllCodeSmellKinds() {
+		if s.covers(kind) {
+			out = append(out, kind)
+		}
+	}
+	return out
+}
+
+func parseNolint(text, prefix string) (suppression, bool) {
+	body := strings.TrimPrefix(text, "//")
+	if body == text || !strings.HasPrefix(body, prefix) {
+		return suppression{}, false
+	}
+	rest := body[len(prefix):]
+	if rest == "" || rest[0] == ' ' || rest[0] == '\t' {
+		return suppression{all: true}, true
+	}
+	if rest[0] != ':' {
+		return suppression{}, false
+	}
+	list := rest[1:]
+	if i := strings.IndexAny(list, " \t"); i >= 0 {
+		list = list[:i]
+	}
+
+	var sup suppression
+	matched := false
+	for _, name := range strings.Split(list, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "":
+		case "codeaudit", "all":
+			sup.all = true
+			matched = true
+		default:
+			for _, kind := range model.AllCodeSmellKinds() {
+				if string(kind) == name {
+					sup.merge(suppression{kinds: map[model.CodeSmellKind]struct{}{kind: {}}})
+					matched = true
+				}
+			}
+		}
+	}
+	return sup, matched
+}
+
+type suppressedSpan struct {
+	start, end int
+	sup        suppression
+}
+
+func suppressedAt(spans []suppressedSpan, line int, kind model.CodeSmellKind) bool {
+	for _, s := range spans {
+		if line >= s.start && line <= s.end && s.sup.covers(kind) {
+			return true
+		}
+	}
+	return false
+}
+
+func nolintByLine(fset *token.FileSet, file *ast.File, prefix string) map[int]suppression {
+	byLine := make(map[int]suppression)
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			if s, ok := parseNolint(c.Text, prefix); ok {
+				line := fset.Position(c.Pos()).Line
+				merged := byLine[line]
+				merged.merge(s)
+				byLine[line] = merged
+			}
+		}
+	}
+	return byLine
+}
+
+func functionSuppression(fset *token.FileSet, fdecl *ast.FuncDecl, byLine map[int]suppression, prefix string) (suppression, bool) {
+	var sup suppression
+	found := false
+
+	if fdecl.Doc != nil {
+		for _, c := range fdecl.Doc.List {
+			if s, ok := parseNolint(c.Text, prefix); ok {
+				sup.merge(s)
+				found = true
+			}
+		}
+	}
+	if s, ok := byLine[fset.Position(fdecl.Pos()).Line]; ok {
+		sup.merge(s)
+		found = true
+	}
+	return sup, found
+}
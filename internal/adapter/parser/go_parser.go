@@ -15,10 +15,32 @@ import (
 	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
 )
 
-type GoParser struct{}
+type GoParserOptions struct {
+	ReportErrShadowing     bool
+	CognitiveModel         string
+	CognitiveLineCap       int
+	SkipGeneratedCognitive bool
+	MissingDefault         bool
+	NolintPrefix           string
+	MaxReturnValues        int
+	QualifiedClosureNames  bool
+}
+
+const DefaultMaxReturnValues = 3
+
+type GoParser struct {
+	opts GoParserOptions
+}
 
 func NewGoParser() *GoParser {
-	return &GoParser{}
+	return NewGoParserWithOptions(GoParserOptions{})
+}
+
+func NewGoParserWithOptions(opts GoParserOptions) *GoParser {
+	if opts.MaxReturnValues <= 0 {
+		opts.MaxReturnValues = DefaultMaxReturnValues
+	}
+	return &GoParser{opts: opts}
 }
 
 var _ ports.CodeParser = (*GoParser)(nil)
@@ -27,6 +49,10 @@ func (p *GoParser) Name() string {
 	return "go"
 }
 
+func (p *GoParser) Extensions() []string {
+	return []string{".go"}
+}
+
 func (p *GoParser) SupportsFile(path string) bool {
 	return strings.HasSuffix(path, ".go")
 }
@@ -54,27 +80,71 @@ func (p *GoParser) ParseFile(path string, src []byte) (*model.FileMetrics, error
 	fm := &model.FileMetrics{
 		Path:     path,
 		Language: model.LanguageGo,
+		Package:  file.Name.Name,
 		Comments: model.CommentMetrics{
 			TotalLines:     totalLines,
 			CommentLines:   commentLines,
 			CommentDensity: commentDensity,
 		},
 	}
+	setFileLineCounts(fm, lines)
+
+	cmap := ast.NewCommentMap(fset, file, file.Comments)
+
+	for _, imp := range file.Imports {
+		fm.Imports = append(fm.Imports, strings.Trim(imp.Path.Value, "`\""))
+	}
 
 	var functions []model.FunctionMetrics
+	var astSmells []model.CodeSmell
 	var allNloc int
 	var allCcn int
 	var maxCcn int
 	var functionsCcnGt10, functionsCcnGt20 int
 	var documentedPublic, publicCount int
+	typed := checkGoFile(fset, file)
+	var suppressed []suppressedSpan
+	nolintPrefix := p.opts.NolintPrefix
+	if nolintPrefix == "" {
+		nolintPrefix = DefaultNolintPrefix
+	}
+	nolintLines := nolintByLine(fset, file, nolintPrefix)
 
 	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+			pubTypes, docTypes := countExportedTypes(gen)
+			publicCount += pubTypes
+			documentedPublic += docTypes
+			for _, spec := range gen.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					fm.TypeDecls++
+					if _, ok := ts.Type.(*ast.InterfaceType); ok {
+						fm.InterfaceDecls++
+					}
+				}
+			}
+			continue
+		}
+
 		fdecl, ok := decl.(*ast.FuncDecl)
-		if !ok || fdecl.Body == nil {
+		if !ok {
+			continue
+		}
+		if fdecl.Body == nil {
+			if !ast.IsExported(fdecl.Name.Name) {
+				continue
+			}
+			fn := bodylessGoFunction(path, fset, fdecl)
+			fn.Role = goFunctionRole(path, file.Name.Name, fdecl)
+			fm.ExternalFunctions = append(fm.ExternalFunctions, fn)
+			publicCount++
+			if fn.IsDocumented {
+				documentedPublic++
+			}
 			continue
 		}
 
-		mainFn, nestedFns, pubCount, pubDocCount := analyzeGoFunction(path, lines, fset, fdecl)
+		mainFn, nestedFns, pubCount, pubDocCount := analyzeGoFunction(path, lines, fset, fdecl, cmap, p.opts)
 		if mainFn.Name == "" {
 			continue
 		}
@@ -82,6 +152,106 @@ func (p *GoParser) ParseFile(path string, src []byte) (*model.FileMetrics, error
 		publicCount += pubCount
 		documentedPublic += pubDocCount
 
+		if sup, ok := functionSuppression(fset, fdecl, nolintLines, nolintPrefix); ok {
+			suppressed = append(suppressed, suppressedSpan{start: mainFn.StartLine, end: mainFn.EndLine, sup: sup})
+			mainFn.SuppressedSmells = sup.list()
+			for k := range nestedFns {
+				nestedFns[k].SuppressedSmells = mainFn.SuppressedSmells
+			}
+		}
+
+		shadows := findShadowedVariables(fset, fdecl, !p.opts.ReportErrShadowing)
+		mainFn.ShadowedVariables = len(shadows)
+		for _, ev := range shadows {
+			astSmells = append(astSmells, model.CodeSmell{
+				Kind:        model.SmellShadowedVariable,
+				Description: fmt.Sprintf("variable %q shadows an outer declaration", ev.Name),
+				FilePath:    path,
+				Function:    mainFn.Name,
+				Line:        ev.Line,
+			})
+		}
+
+		access := findGlobalAccess(fset, fdecl, typed)
+		mainFn.GlobalReads = access.Reads
+		mainFn.GlobalWrites = access.Writes
+		mainFn.UnresolvedRefs = access.Unresolved
+		if access.Writes > 0 {
+			astSmells = append(astSmells, model.CodeSmell{
+				Kind:        model.SmellGlobalMutation,
+				Description: fmt.Sprintf("function writes package variable(s) %s", strings.Join(access.Written, ", ")),
+				FilePath:    path,
+				Function:    mainFn.Name,
+				Line:        access.FirstLine,
+			})
+		}
+
+		results, errorLast := countResults(fdecl)
+		mainFn.ReturnValueCount = results
+		values := results
+		if errorLast {
+			values--
+		}
+		if values > p.opts.MaxReturnValues {
+			desc := fmt.Sprintf("function returns %d values (>%d)", values, p.opts.MaxReturnValues)
+			if errorLast {
+				desc = fmt.Sprintf("function returns %d values plus an error (>%d)", values, p.opts.MaxReturnValues)
+			}
+			astSmells = append(astSmells, model.CodeSmell{
+				Kind:        model.SmellManyReturns,
+				Description: desc,
+				FilePath:    path,
+				Function:    mainFn.Name,
+				Line:        fset.Position(fdecl.Type.Results.Pos()).Line,
+			})
+		}
+
+		if fdecl.Name.IsExported() && errorResultNotLast(fdecl) {
+			astSmells = append(astSmells, model.CodeSmell{
+				Kind:        model.SmellErrorNotLast,
+				Description: "error is returned before other results instead of last",
+				FilePath:    path,
+				Function:    mainFn.Name,
+				Line:        fset.Position(fdecl.Type.Results.Pos()).Line,
+			})
+		}
+
+		names := functionIdentifierStats(fdecl, typed)
+		mainFn.AvgIdentifierLength = names.AvgLen
+		mainFn.ShortIdentifiers = names.Short
+		if names.cryptic() {
+			astSmells = append(astSmells, model.CodeSmell{
+				Kind: model.SmellCrypticNames,
+				Description: fmt.Sprintf("%d of %d declared names are a single character (%s)",
+					names.Short, names.Declared, strings.Join(names.ShortIDs, ", ")),
+				FilePath: path,
+				Function: mainFn.Name,
+				Line:     mainFn.StartLine,
+			})
+		}
+
+		if p.opts.MissingDefault {
+			for _, ev := range findSwitchesWithoutDefault(fset, fdecl) {
+				kind := "switch"
+				if ev.TypeSwitch {
+					kind = "type switch"
+				}
+				astSmells = append(astSmells, model.CodeSmell{
+					Kind:        model.SmellMissingDefault,
+					Description: kind + " has no default case",
+					FilePath:    path,
+					Function:    mainFn.Name,
+					Line:        ev.Line,
+				})
+			}
+		}
+
+		role := goFunctionRole(path, file.Name.Name, fdecl)
+		mainFn.Role = role
+		for k := range nestedFns {
+			nestedFns[k].Role = role
+		}
+
 		allFns := append([]model.FunctionMetrics{mainFn}, nestedFns...)
 		for _, fn := range allFns {
 			functions = append(functions, fn)
@@ -100,6 +270,10 @@ func (p *GoParser) ParseFile(path string, src []byte) (*model.FileMetrics, error
 	}
 
 	fm.Functions = functions
+	fm.PackageVars = packageVarNames(file)
+	if p.opts.SkipGeneratedCognitive && isGeneratedSource(lines) {
+		zeroCognitive(fm.Functions)
+	}
 	fnCount := len(functions)
 	avgCcn := 0.0
 	if fnCount > 0 {
@@ -119,7 +293,10 @@ func (p *GoParser) ParseFile(path string, src []byte) (*model.FileMetrics, error
 		FunctionsCCNGt10:  functionsCcnGt10,
 		FunctionsCCNGt20:  functionsCcnGt20,
 	}
+	fm.Summary.LongestFunctionName, fm.Summary.LongestFunctionNLOC = longestFunction(functions)
 	fm.Comments.PublicAPIDocPct = publicDocPct
+	fm.Comments.PublicSymbols = publicCount
+	fm.Comments.PublicDocumented = documentedPublic
 
 	var smells []model.CodeSmell
 	for _, fn := range functions {
@@ -151,12 +328,18 @@ func (p *GoParser) ParseFile(path string, src []byte) (*model.FileMetrics, error
 			})
 		}
 	}
-	fm.Smells = smells
+	smells = append(smells, astSmells...)
+	for _, smell := range smells {
+		if smell.Function != "" && suppressedAt(suppressed, smell.Line, smell.Kind) {
+			continue
+		}
+		fm.Smells = append(fm.Smells, smell)
+	}
 
 	return fm, nil
 }
 
-func analyzeGoFunction(path string, lines []string, fset *token.FileSet, fdecl *ast.FuncDecl) (model.FunctionMetrics, []model.FunctionMetrics, int, int) {
+func analyzeGoFunction(path string, lines []string, fset *token.FileSet, fdecl *ast.FuncDecl, cmap ast.CommentMap, opts GoParserOptions) (model.FunctionMetrics, []model.FunctionMetrics, int, int) {
 	start := fset.Position(fdecl.Pos()).Line
 	end := fset.Position(fdecl.End()).Line
 
@@ -168,25 +351,17 @@ func analyzeGoFunction(path string, lines []string, fset *token.FileSet, fdecl *
 	}
 
 	funcLits := collectFuncLits(fdecl.Body)
-
-	var excludes []lineRange
-	for _, lit := range funcLits {
-		s := fset.Position(lit.Pos()).Line
-		e := fset.Position(lit.End()).Line
-		if s < start {
-			s = start
-		}
-		if e > end {
-			e = end
-		}
-		if s <= e {
-			excludes = append(excludes, lineRange{Start: s, End: e})
-		}
+	excludes := funcLitExcludes(fset, funcLits, start, end)
+
+	capped, capRecord := newCognitiveCap(opts.CognitiveLineCap)
+	nloc, ccn, cognitive, maxNesting, locals, _ :=
+		computeTextMetricsForRangeWithExcludes(lines, start, end, excludes, capRecord)
+	cognitive = capped.apply(cognitive)
+	commentLinesFn := astCommentLines(fset, cmap.Filter(fdecl), excludes)
+	if opts.CognitiveModel == CognitiveModelSonar {
+		cognitive = sonarCognitiveComplexity(fdecl.Body)
 	}
 
-	nloc, ccn, cognitive, maxNesting, locals, commentLinesFn :=
-		computeTextMetricsForRangeWithExcludes(lines, start, end, excludes)
-
 	params := countParams(fdecl)
 	isPublic := ast.IsExported(fdecl.Name.Name)
 	isDoc := fdecl.Doc != nil && len(fdecl.Doc.List) > 0
@@ -244,6 +419,12 @@ func analyzeGoFunction(path string, lines []string, fset *token.FileSet, fdecl *
 		Callees:             callees,
 		IsPublic:            isPublic,
 		IsDocumented:        isDoc,
+		IsDataFunction:      isGoDataFunction(fset, fdecl),
+	}
+
+	var closureNames map[*ast.FuncLit]string
+	if opts.QualifiedClosureNames {
+		closureNames = goClosureNames(fdecl)
 	}
 
 	var nestedFns []model.FunctionMetrics
@@ -260,8 +441,14 @@ func analyzeGoFunction(path string, lines []string, fset *token.FileSet, fdecl *
 			continue
 		}
 
-		nlocLit, ccnLit, cogLit, maxNestLit, localsLit, commentLinesLit :=
-			computeTextMetricsForRangeWithExcludes(lines, s, e, nil)
+		cappedLit, capRecordLit := newCognitiveCap(opts.CognitiveLineCap)
+		nlocLit, ccnLit, cogLit, maxNestLit, localsLit, _ :=
+			computeTextMetricsForRangeWithExcludes(lines, s, e, nil, capRecordLit)
+		cogLit = cappedLit.apply(cogLit)
+		commentLinesLit := astCommentLines(fset, cmap.Filter(lit), nil)
+		if opts.CognitiveModel == CognitiveModelSonar {
+			cogLit = sonarCognitiveComplexity(lit.Body)
+		}
 
 		commentDensityLit := 0.0
 		if nlocLit+commentLinesLit > 0 {
@@ -292,6 +479,9 @@ func analyzeGoFunction(path string, lines []string, fset *token.FileSet, fdecl *
 		sort.Strings(calleesLit)
 
 		name := fmt.Sprintf("@%d-%d", s, e)
+		if qualified, ok := closureNames[lit]; ok {
+			name = qualified
+		}
 
 		nestedFns = append(nestedFns, model.FunctionMetrics{
 			Name:                name,
@@ -317,6 +507,63 @@ func analyzeGoFunction(path string, lines []string, fset *token.FileSet, fdecl *
 	return mainFn, nestedFns, publicCount, documentedPublic
 }
 
+func bodylessGoFunction(path string, fset *token.FileSet, fdecl *ast.FuncDecl) model.FunctionMetrics {
+	return model.FunctionMetrics{
+		Name:         fdecl.Name.Name,
+		Signature:    buildSignature(fdecl),
+		FilePath:     path,
+		Language:     model.LanguageGo,
+		StartLine:    fset.Position(fdecl.Pos()).Line,
+		EndLine:      fset.Position(fdecl.End()).Line,
+		Parameters:   countParams(fdecl),
+		IsPublic:     true,
+		IsDocumented: fdecl.Doc != nil && len(fdecl.Doc.List) > 0,
+		IsExternal:   true,
+	}
+}
+
+func countExportedTypes(gen *ast.GenDecl) (int, int) {
+	public, documented := 0, 0
+	for _, spec := range gen.Specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		if !ok || !ast.IsExported(ts.Name.Name) {
+			continue
+		}
+		public++
+		hasDoc := ts.Doc != nil && len(ts.Doc.List) > 0
+		if !hasDoc && !gen.Lparen.IsValid() {
+			hasDoc = gen.Doc != nil && len(gen.Doc.List) > 0
+		}
+		if hasDoc {
+			documented++
+		}
+	}
+	return public, documented
+}
+
+func astCommentLines(fset *token.FileSet, cmap ast.CommentMap, excludes []lineRange) int {
+	seen := make(map[int]struct{})
+	for _, group := range cmap.Comments() {
+		for _, c := range group.List {
+			from := fset.Position(c.Pos()).Line
+			to := fset.Position(c.End()).Line
+			for line := from; line <= to; line++ {
+				excluded := false
+				for _, r := range excludes {
+					if line >= r.Start && line <= r.End {
+						excluded = true
+						break
+					}
+				}
+				if !excluded {
+					seen[line] = struct{}{}
+				}
+			}
+		}
+	}
+	return len(seen)
+}
+
 func collectFuncLits(node ast.Node) []*ast.FuncLit {
 	var lits []*ast.FuncLit
 	ast.Inspect(node, func(n ast.Node) bool {
@@ -330,7 +577,25 @@ func collectFuncLits(node ast.Node) []*ast.FuncLit {
 	return lits
 }
 
-func computeTextMetricsForRangeWithExcludes(lines []string, start, end int, excludes []lineRange) (nloc, ccn, cognitive, maxNesting, locals, commentLines int) {
+func funcLitExcludes(fset *token.FileSet, funcLits []*ast.FuncLit, start, end int) []lineRange {
+	var excludes []lineRange
+	for _, lit := range funcLits {
+		s := fset.Position(lit.Pos()).Line
+		e := fset.Position(lit.End()).Line
+		if s < start {
+			s = start
+		}
+		if e > end {
+			e = end
+		}
+		if s <= e {
+			excludes = append(excludes, lineRange{Start: s, End: e})
+		}
+	}
+	return excludes
+}
+
+func computeTextMetricsForRangeWithExcludes(lines []string, start, end int, excludes []lineRange, record lineRecorder) (nloc, ccn, cognitive, maxNesting, locals, commentLines int) {
 	ccn = 1
 	depth := 0
 	inBlock := false
@@ -405,45 +670,59 @@ func computeTextMetricsForRangeWithExcludes(lines []string, start, end int, excl
 
 		ccnLine := 0
 		cogLine := 0
+		var constructs []string
 
 		if strings.Contains(trimmed, "else if ") {
 			ccnLine++
 			cogLine++
+			constructs = append(constructs, "else if")
 		} else if strings.Contains(trimmed, "if ") {
 			ccnLine++
 			cogLine++
+			constructs = append(constructs, "if")
 		}
 
 		if strings.Contains(trimmed, "for ") {
 			ccnLine++
 			cogLine++
+			constructs = append(constructs, "for")
 		}
 		if strings.Contains(trimmed, "switch ") {
 			ccnLine++
 			cogLine++
+			constructs = append(constructs, "switch")
 		}
 
 		caseCount := strings.Count(trimmed, "case ")
 		if caseCount > 0 {
 			ccnLine += caseCount
 			cogLine += caseCount
+			for n := 0; n < caseCount; n++ {
+				constructs = append(constructs, "case")
+			}
 		}
 		if strings.Contains(trimmed, "default:") {
 			ccnLine++
 			cogLine++
+			constructs = append(constructs, "default")
 		}
 		if strings.Contains(trimmed, "goto ") {
 			ccnLine++
 			cogLine++
+			constructs = append(constructs, "goto")
 		}
 
 		boolOps := strings.Count(trimmed, "&&") + strings.Count(trimmed, "||")
 		if boolOps > 0 {
 			cogLine += boolOps
+			for n := 0; n < boolOps; n++ {
+				constructs = append(constructs, "&&/||")
+			}
 		}
 
 		if strings.HasPrefix(trimmed, "return ") && depth > 0 {
 			cogLine++
+			constructs = append(constructs, "nested return")
 		}
 
 		if ccnLine > 0 {
@@ -452,6 +731,15 @@ func computeTextMetricsForRangeWithExcludes(lines []string, start, end int, excl
 		if cogLine > 0 {
 			cognitive += cogLine * (1 + depth)
 		}
+		if record != nil && cogLine > 0 {
+			record(model.LineContribution{
+				Line:       lineNo,
+				Code:       trimmed,
+				CCN:        ccnLine,
+				Cognitive:  cogLine * (1 + depth),
+				Constructs: constructs,
+			})
+		}
 
 		if strings.Contains(line, ":=") || strings.HasPrefix(trimmed, "var ") {
 			locals++
@@ -468,6 +756,38 @@ func countParams(fn *ast.FuncDecl) int {
 	return countParamsFromFieldList(fn.Type.Params)
 }
 
+func countResults(fn *ast.FuncDecl) (int, bool) {
+	if fn.Type == nil || fn.Type.Results == nil || len(fn.Type.Results.List) == 0 {
+		return 0, false
+	}
+	last := fn.Type.Results.List[len(fn.Type.Results.List)-1]
+	id, ok := last.Type.(*ast.Ident)
+	return countParamsFromFieldList(fn.Type.Results), ok && id.Name == "error"
+}
+
+func errorResultNotLast(fn *ast.FuncDecl) bool {
+	if fn.Type == nil || fn.Type.Results == nil {
+		return false
+	}
+	var isError []bool
+	for _, field := range fn.Type.Results.List {
+		id, ok := field.Type.(*ast.Ident)
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			isError = append(isError, ok && id.Name == "error")
+		}
+	}
+	for i := 0; i < len(isError)-1; i++ {
+		if isError[i] {
+			return true
+		}
+	}
+	return false
+}
+
 func countParamsFromFieldList(fl *ast.FieldList) int {
 	if fl == nil {
 		return 0
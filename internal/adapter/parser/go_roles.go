@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"go/ast"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+func goFunctionRole(path, pkg string, fdecl *ast.FuncDecl) model.FunctionRole {
+	if fdecl.Recv != nil {
+		return model.RoleRegular
+	}
+	name := fdecl.Name.Name
+	params := fdecl.Type.Params
+	noParams := params == nil || len(params.List) == 0
+	noResults := fdecl.Type.Results == nil || len(fdecl.Type.Results.List) == 0
+
+	switch {
+	case name == "init" && noParams && noResults:
+		return model.RoleInit
+	case name == "main" && pkg == "main" && noParams && noResults:
+		return model.RoleMain
+	}
+
+	if !strings.HasSuffix(path, "_test.go") {
+		return model.RoleRegular
+	}
+	switch {
+	case name == "TestMain" && testingParam(params, "M"):
+		return model.RoleTest
+	case hasTestPrefix(name, "Test") && testingParam(params, "T"):
+		return model.RoleTest
+	case hasTestPrefix(name, "Benchmark") && testingParam(params, "B"):
+		return model.RoleBenchmark
+	case hasTestPrefix(name, "Fuzz") && testingParam(params, "F"):
+		return model.RoleFuzz
+	case strings.HasPrefix(name, "Example") && noParams && noResults:
+		return model.RoleExample
+	}
+	return model.RoleRegular
+}
+
+func hasTestPrefix(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	if len(name) == len(prefix) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(name[len(prefix):])
+	return !unicode.IsLower(r)
+}
+
+func testingParam(params *ast.FieldList, typeName string) bool {
+	if params == nil || len(params.List) != 1 || len(params.List[0].Names) > 1 {
+		return false
+	}
+	star, ok := params.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == typeName
+}
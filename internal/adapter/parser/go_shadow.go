@@ -0,0 +1,237 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+type shadowEvent struct {
+	Name string
+	Line int
+}
+
+type shadowWalker struct {
+	fset      *token.FileSet
+	ignoreErr bool
+	scopes    []map[string]struct{}
+	events    []shadowEvent
+}
+
+func findShadowedVariables(fset *token.FileSet, fdecl *ast.FuncDecl, ignoreErr bool) []shadowEvent {
+	if fdecl.Body == nil {
+		return nil
+	}
+	w := &shadowWalker{fset: fset, ignoreErr: ignoreErr}
+	w.walkFunc(fdecl.Recv, fdecl.Type, fdecl.Body)
+	return w.events
+}
+
+func (w *shadowWalker) push() {
+	w.scopes = append(w.scopes, make(map[string]struct{}))
+}
+
+func (w *shadowWalker) pop() {
+	w.scopes = w.scopes[:len(w.scopes)-1]
+}
+
+func (w *shadowWalker) declare(ident *ast.Ident) {
+	if ident == nil || ident.Name == "_" {
+		return
+	}
+	current := w.scopes[len(w.scopes)-1]
+	if _, ok := current[ident.Name]; ok {
+		return
+	}
+	for i := len(w.scopes) - 2; i >= 0; i-- {
+		if _, ok := w.scopes[i][ident.Name]; !ok {
+			continue
+		}
+		if !(w.ignoreErr && ident.Name == "err") {
+			w.events = append(w.events, shadowEvent{
+				Name: ident.Name,
+				Line: w.fset.Position(ident.Pos()).Line,
+			})
+		}
+		break
+	}
+	current[ident.Name] = struct{}{}
+}
+
+func (w *shadowWalker) declareFields(fl *ast.FieldList) {
+	if fl == nil {
+		return
+	}
+	for _, f := range fl.List {
+		for _, name := range f.Names {
+			w.declare(name)
+		}
+	}
+}
+
+func (w *shadowWalker) walkFunc(recv *ast.FieldList, ftype *ast.FuncType, body *ast.BlockStmt) {
+	w.push()
+	w.declareFields(recv)
+	if ftype != nil {
+		w.declareFields(ftype.Params)
+		w.declareFields(ftype.Results)
+	}
+	w.walkStmts(body.List)
+	w.pop()
+}
+
+func (w *shadowWalker) walkStmts(stmts []ast.Stmt) {
+	for _, s := range stmts {
+		w.walkStmt(s)
+	}
+}
+
+func (w *shadowWalker) walkBlock(block *ast.BlockStmt) {
+	if block == nil {
+		return
+	}
+	w.push()
+	w.walkStmts(block.List)
+	w.pop()
+}
+
+func (w *shadowWalker) walkStmt(stmt ast.Stmt) {
+	switch s := stmt.(type) {
+	case nil:
+	case *ast.BlockStmt:
+		w.walkBlock(s)
+	case *ast.IfStmt:
+		w.push()
+		w.walkStmt(s.Init)
+		w.walkExpr(s.Cond)
+		w.walkBlock(s.Body)
+		w.walkStmt(s.Else)
+		w.pop()
+	case *ast.ForStmt:
+		w.push()
+		w.walkStmt(s.Init)
+		w.walkExpr(s.Cond)
+		w.walkStmt(s.Post)
+		w.walkBlock(s.Body)
+		w.pop()
+	case *ast.RangeStmt:
+		w.walkExpr(s.X)
+		w.push()
+		if s.Tok == token.DEFINE {
+			if ident, ok := s.Key.(*ast.Ident); ok {
+				w.declare(ident)
+			}
+			if ident, ok := s.Value.(*ast.Ident); ok {
+				w.declare(ident)
+			}
+		}
+		w.walkBlock(s.Body)
+		w.pop()
+	case *ast.SwitchStmt:
+		w.push()
+		w.walkStmt(s.Init)
+		w.walkExpr(s.Tag)
+		w.walkClauses(s.Body)
+		w.pop()
+	case *ast.TypeSwitchStmt:
+		w.push()
+		w.walkStmt(s.Init)
+		if assign, ok := s.Assign.(*ast.AssignStmt); ok {
+			w.walkExprs(assign.Rhs)
+			if assign.Tok == token.DEFINE {
+				for _, lhs := range assign.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						w.declare(ident)
+					}
+				}
+			}
+		}
+		w.walkClauses(s.Body)
+		w.pop()
+	case *ast.SelectStmt:
+		w.walkClauses(s.Body)
+	case *ast.AssignStmt:
+		w.walkExprs(s.Rhs)
+		if s.Tok == token.DEFINE {
+			for _, lhs := range s.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					w.declare(ident)
+				}
+			}
+		} else {
+			w.walkExprs(s.Lhs)
+		}
+	case *ast.DeclStmt:
+		gen, ok := s.Decl.(*ast.GenDecl)
+		if !ok {
+			return
+		}
+		for _, spec := range gen.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			w.walkExprs(vs.Values)
+			for _, name := range vs.Names {
+				w.declare(name)
+			}
+		}
+	case *ast.LabeledStmt:
+		w.walkStmt(s.Stmt)
+	case *ast.ExprStmt:
+		w.walkExpr(s.X)
+	case *ast.ReturnStmt:
+		w.walkExprs(s.Results)
+	case *ast.GoStmt:
+		w.walkExpr(s.Call)
+	case *ast.DeferStmt:
+		w.walkExpr(s.Call)
+	case *ast.SendStmt:
+		w.walkExpr(s.Chan)
+		w.walkExpr(s.Value)
+	case *ast.IncDecStmt:
+		w.walkExpr(s.X)
+	}
+}
+
+func (w *shadowWalker) walkClauses(body *ast.BlockStmt) {
+	if body == nil {
+		return
+	}
+	for _, clause := range body.List {
+		switch c := clause.(type) {
+		case *ast.CaseClause:
+			w.push()
+			w.walkExprs(c.List)
+			w.walkStmts(c.Body)
+			w.pop()
+		case *ast.CommClause:
+			w.push()
+			w.walkStmt(c.Comm)
+			w.walkStmts(c.Body)
+			w.pop()
+		}
+	}
+}
+
+func (w *shadowWalker) walkExprs(exprs []ast.Expr) {
+	for _, e := range exprs {
+		w.walkExpr(e)
+	}
+}
+
+func (w *shadowWalker) walkExpr(expr ast.Expr) {
+	if expr == nil {
+		return
+	}
+	ast.Inspect(expr, func(n ast.Node) bool {
+		lit, ok := n.(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+		w.walkFunc(nil, lit.Type, lit.Body)
+		return false
+	})
+}
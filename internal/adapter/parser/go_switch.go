@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+type switchEvent struct {
+	Line       int
+	TypeSwitch bool
+}
+
+func findSwitchesWithoutDefault(fset *token.FileSet, fdecl *ast.FuncDecl) []switchEvent {
+	if fdecl.Body == nil {
+		return nil
+	}
+	var events []switchEvent
+	ast.Inspect(fdecl.Body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.SwitchStmt:
+			if !hasDefaultClause(s.Body) {
+				events = append(events, switchEvent{Line: fset.Position(s.Pos()).Line})
+			}
+		case *ast.TypeSwitchStmt:
+			if !hasDefaultClause(s.Body) {
+				events = append(events, switchEvent{Line: fset.Position(s.Pos()).Line, TypeSwitch: true})
+			}
+		}
+		return true
+	})
+	return events
+}
+
+func hasDefaultClause(body *ast.BlockStmt) bool {
+	for _, stmt := range body.List {
+		if cc, ok := stmt.(*ast.CaseClause); ok && cc.List == nil {
+			return true
+		}
+	}
+	return false
+}
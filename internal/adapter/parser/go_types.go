@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+)
+
+type goFileTypes struct {
+	info *types.Info
+	pkg  *types.Package
+}
+
+type stubImporter struct{}
+
+func (stubImporter) Import(importPath string) (*types.Package, error) {
+	pkg := types.NewPackage(importPath, path.Base(importPath))
+	pkg.MarkComplete()
+	return pkg, nil
+}
+
+func checkGoFile(fset *token.FileSet, file *ast.File) goFileTypes {
+	info := &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{
+		Importer:    stubImporter{},
+		FakeImportC: true,
+		Error:       func(error) {},
+	}
+	pkg, _ := conf.Check(file.Name.Name, fset, []*ast.File{file}, info)
+	return goFileTypes{info: info, pkg: pkg}
+}
+
+func (t goFileTypes) packageVar(id *ast.Ident) bool {
+	if id == nil || id.Name == "_" || t.pkg == nil {
+		return false
+	}
+	obj := t.info.Uses[id]
+	if obj == nil {
+		obj = t.info.Defs[id]
+	}
+	v, ok := obj.(*types.Var)
+	return ok && !v.IsField() && v.Parent() == t.pkg.Scope()
+}
+
+func (t goFileTypes) unresolved(id *ast.Ident) bool {
+	if id == nil || id.Name == "_" || t.pkg == nil {
+		return false
+	}
+	if _, ok := t.info.Uses[id]; ok {
+		return false
+	}
+	if _, ok := t.info.Defs[id]; ok {
+		return false
+	}
+	return types.Universe.Lookup(id.Name) == nil
+}
+
+func packageVarNames(file *ast.File) []string {
+	var names []string
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				if name.Name != "_" {
+					names = append(names, name.Name)
+				}
+			}
+		}
+	}
+	return names
+}
@@ -6,12 +6,108 @@ package parser
 import (
 	"regexp"
 	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
 )
 
 var decisionKeywords = regexp.MustCompile(`\b(if|for|while|case|switch)\b`)
 
 var boolOps = regexp.MustCompile(`&&|\|\||\?`)
 
+var logicalOps = regexp.MustCompile(`&&|\|\|`)
+
+var gotoKeyword = regexp.MustCompile(`\bgoto\b`)
+
+var generatedCodeRe = regexp.MustCompile(`^\s*(//|/\*)\s*Code generated .* DO NOT EDIT\.`)
+
+func isGeneratedSource(lines []string) bool {
+	for _, line := range lines {
+		if generatedCodeRe.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+type cognitiveCap struct {
+	max   int
+	total int
+}
+
+func newCognitiveCap(max int) (*cognitiveCap, lineRecorder) {
+	if max <= 0 {
+		return nil, nil
+	}
+	c := &cognitiveCap{max: max}
+	return c, c.record
+}
+
+func (c *cognitiveCap) record(l model.LineContribution) {
+	if l.Cognitive > c.max {
+		c.total += c.max
+		return
+	}
+	c.total += l.Cognitive
+}
+
+func (c *cognitiveCap) apply(cognitive int) int {
+	if c == nil {
+		return cognitive
+	}
+	return c.total
+}
+
+func zeroCognitive(functions []model.FunctionMetrics) {
+	for i := range functions {
+		functions[i].CognitiveComplexity = 0
+	}
+}
+
+func setFileLineCounts(fm *model.FileMetrics, lines []string) {
+	total := len(lines)
+	if total > 0 && lines[total-1] == "" {
+		total--
+	}
+	fm.FileLinesTotal = total
+	fm.FileLinesComment = fm.Comments.CommentLines
+	fm.FileLinesCode = countCodeLines(lines)
+}
+
+func countCodeLines(lines []string) int {
+	inBlock := false
+	count := 0
+	for _, line := range lines {
+		rest := strings.TrimSpace(line)
+		code := false
+		for rest != "" {
+			if inBlock {
+				end := strings.Index(rest, "*/")
+				if end < 0 {
+					rest = ""
+					break
+				}
+				rest = strings.TrimSpace(rest[end+2:])
+				inBlock = false
+				continue
+			}
+			if strings.HasPrefix(rest, "//") {
+				break
+			}
+			if strings.HasPrefix(rest, "/*") {
+				rest = rest[2:]
+				inBlock = true
+				continue
+			}
+			code = true
+			break
+		}
+		if code {
+			count++
+		}
+	}
+	return count
+}
+
 func estimateCommentLines(lines []string) int {
 	inBlock := false
 	count := 0
@@ -46,7 +142,9 @@ func estimateCommentLines(lines []string) int {
 	return count
 }
 
-func computeTextMetricsForRange(lines []string, startLine, endLine int) (
+type lineRecorder func(model.LineContribution)
+
+func computeTextMetricsForRange(lines []string, startLine, endLine int, record lineRecorder) (
 	nloc int,
 	ccn int,
 	cognitive int,
@@ -115,8 +213,10 @@ func computeTextMetricsForRange(lines []string, startLine, endLine int) (
 
 		code := stripStringLiterals(trimmed)
 
-		decisions := len(decisionKeywords.FindAllStringSubmatch(code, -1))
-		bools := len(boolOps.FindAllStringSubmatch(code, -1))
+		decisionMatches := decisionKeywords.FindAllString(code, -1)
+		boolMatches := boolOps.FindAllString(code, -1)
+		decisions := len(decisionMatches)
+		bools := len(boolMatches)
 
 		ccn += decisions + bools
 
@@ -131,11 +231,97 @@ func computeTextMetricsForRange(lines []string, startLine, endLine int) (
 		}
 
 		cognitive += decisions + blockDepth
+
+		if record != nil && decisions+bools+blockDepth > 0 {
+			constructs := append(decisionMatches, boolMatches...)
+			if blockDepth > 0 {
+				constructs = append(constructs, "nesting")
+			}
+			record(model.LineContribution{
+				Line:       i + 1,
+				Code:       trimmed,
+				CCN:        decisions + bools,
+				Cognitive:  decisions + blockDepth,
+				Constructs: constructs,
+			})
+		}
 	}
 
 	return
 }
 
+func longestFunction(functions []model.FunctionMetrics) (string, int) {
+	var longest *model.FunctionMetrics
+	for i := range functions {
+		fn := &functions[i]
+		if fn.NLOC == 0 {
+			continue
+		}
+		if longest == nil || fn.NLOC > longest.NLOC ||
+			(fn.NLOC == longest.NLOC && fn.StartLine < longest.StartLine) {
+			longest = fn
+		}
+	}
+	if longest == nil {
+		return "", 0
+	}
+	return longest.Name, longest.NLOC
+}
+
+func countOperatorsForRange(lines []string, startLine, endLine int) model.OperatorCounts {
+	var counts model.OperatorCounts
+	if startLine < 1 {
+		startLine = 1
+	}
+	if endLine > len(lines) {
+		endLine = len(lines)
+	}
+
+	inBlockComment := false
+	for i := startLine - 1; i < endLine; i++ {
+		code := stripStringLiterals(lines[i])
+
+		var b strings.Builder
+		for len(code) > 0 {
+			if inBlockComment {
+				idx := strings.Index(code, "*/")
+				if idx < 0 {
+					code = ""
+					break
+				}
+				code = code[idx+2:]
+				inBlockComment = false
+				continue
+			}
+			lineIdx := strings.Index(code, "//")
+			blockIdx := strings.Index(code, "/*")
+			if lineIdx >= 0 && (blockIdx < 0 || lineIdx < blockIdx) {
+				b.WriteString(code[:lineIdx])
+				code = ""
+				break
+			}
+			if blockIdx >= 0 {
+				b.WriteString(code[:blockIdx])
+				code = code[blockIdx+2:]
+				inBlockComment = true
+				continue
+			}
+			b.WriteString(code)
+			code = ""
+		}
+
+		text := b.String()
+		if strings.HasPrefix(strings.TrimSpace(text), "#") {
+			continue
+		}
+		counts.Ternary += strings.Count(text, "?")
+		counts.Logical += len(logicalOps.FindAllString(text, -1))
+		counts.Goto += len(gotoKeyword.FindAllString(text, -1))
+	}
+
+	return counts
+}
+
 func stripStringLiterals(s string) string {
 	var b strings.Builder
 	inSingle := false
@@ -0,0 +1,262 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+type SortKey string
+
+const (
+	SortCCN   SortKey = "ccn"
+	SortChurn SortKey = "churn"
+	SortPath  SortKey = "path"
+)
+
+var sortOrder = []SortKey{SortCCN, SortChurn, SortPath}
+
+type Browser struct {
+	report *model.ProjectReport
+
+	sortBy SortKey
+	filter model.CodeSmellKind
+
+	visible  []int
+	cursor   int
+	open     bool
+	fnCursor int
+}
+
+func NewBrowser(report *model.ProjectReport) *Browser {
+	b := &Browser{report: report, sortBy: SortCCN}
+	b.refresh()
+	return b
+}
+
+func (b *Browser) SortBy() SortKey {
+	return b.sortBy
+}
+
+func (b *Browser) SetSort(key SortKey) {
+	b.sortBy = key
+	b.refresh()
+}
+
+func (b *Browser) CycleSort() {
+	for i, key := range sortOrder {
+		if key == b.sortBy {
+			b.SetSort(sortOrder[(i+1)%len(sortOrder)])
+			return
+		}
+	}
+	b.SetSort(SortCCN)
+}
+
+func (b *Browser) SmellFilter() model.CodeSmellKind {
+	return b.filter
+}
+
+func (b *Browser) SetSmellFilter(kind model.CodeSmellKind) {
+	b.filter = kind
+	b.refresh()
+}
+
+func (b *Browser) CycleSmellFilter() {
+	kinds := b.presentSmellKinds()
+	next := model.CodeSmellKind("")
+	if b.filter == "" && len(kinds) > 0 {
+		next = kinds[0]
+	}
+	for i, kind := range kinds {
+		if kind == b.filter && i+1 < len(kinds) {
+			next = kinds[i+1]
+		}
+	}
+	b.SetSmellFilter(next)
+}
+
+func (b *Browser) Files() []model.FileMetrics {
+	files := make([]model.FileMetrics, 0, len(b.visible))
+	for _, idx := range b.visible {
+		files = append(files, b.report.Files[idx])
+	}
+	return files
+}
+
+func (b *Browser) Selected() *model.FileMetrics {
+	if len(b.visible) == 0 {
+		return nil
+	}
+	return &b.report.Files[b.visible[b.cursor]]
+}
+
+func (b *Browser) Functions() []model.FunctionMetrics {
+	f := b.Selected()
+	if f == nil {
+		return nil
+	}
+	fns := append([]model.FunctionMetrics(nil), f.Functions...)
+	sort.SliceStable(fns, func(i, j int) bool {
+		if fns[i].CCN != fns[j].CCN {
+			return fns[i].CCN > fns[j].CCN
+		}
+		return fns[i].StartLine < fns[j].StartLine
+	})
+	return fns
+}
+
+func (b *Browser) InFile() bool {
+	return b.open
+}
+
+func (b *Browser) Move(delta int) {
+	if b.open {
+		b.fnCursor = clamp(b.fnCursor+delta, len(b.Functions()))
+		return
+	}
+	b.cursor = clamp(b.cursor+delta, len(b.visible))
+}
+
+func (b *Browser) Enter() {
+	if b.Selected() != nil {
+		b.open = true
+		b.fnCursor = 0
+	}
+}
+
+func (b *Browser) Back() {
+	b.open = false
+}
+
+func (b *Browser) View(height int) string {
+	var sb strings.Builder
+	filter := "all"
+	if b.filter != "" {
+		filter = string(b.filter)
+	}
+	fmt.Fprintf(&sb, "codeaudit %s  sort=%s  smell=%s\n\n", b.report.RootPath, b.sortBy, filter)
+
+	rows := height - 4
+	if rows < 1 {
+		rows = 1
+	}
+	if b.open {
+		f := b.Selected()
+		fmt.Fprintf(&sb, "%s (%d functions, %d smells)\n", f.Path, len(f.Functions), len(f.Smells))
+		fns := b.Functions()
+		start := windowStart(b.fnCursor, rows)
+		for i := start; i < len(fns) && i < start+rows; i++ {
+			fn := fns[i]
+			fmt.Fprintf(&sb, "%s %-40s CCN=%3d  COG=%3d  NLOC=%4d  L%d\n",
+				marker(i == b.fnCursor), truncate(fn.Name, 40), fn.CCN, fn.CognitiveComplexity, fn.NLOC, fn.StartLine)
+		}
+		sb.WriteString("\nj/k move  h back  s sort  f smell filter  q quit\n")
+		return sb.String()
+	}
+
+	if len(b.visible) == 0 {
+		sb.WriteString("no files match the current filter\n")
+	}
+	start := windowStart(b.cursor, rows)
+	for i := start; i < len(b.visible) && i < start+rows; i++ {
+		f := b.report.Files[b.visible[i]]
+		fmt.Fprintf(&sb, "%s %-50s CCN=%4d  churn=%5d  smells=%2d\n",
+			marker(i == b.cursor), truncate(f.Path, 50), f.Summary.CCNTotal, churn(f), len(f.Smells))
+	}
+	sb.WriteString("\nj/k move  enter open  s sort  f smell filter  q quit\n")
+	return sb.String()
+}
+
+func (b *Browser) refresh() {
+	b.visible = b.visible[:0]
+	for i, f := range b.report.Files {
+		if b.filter == "" || hasSmell(f, b.filter) {
+			b.visible = append(b.visible, i)
+		}
+	}
+	files := b.report.Files
+	sort.SliceStable(b.visible, func(i, j int) bool {
+		a, c := files[b.visible[i]], files[b.visible[j]]
+		switch b.sortBy {
+		case SortChurn:
+			if churn(a) != churn(c) {
+				return churn(a) > churn(c)
+			}
+		case SortPath:
+		default:
+			if a.Summary.CCNTotal != c.Summary.CCNTotal {
+				return a.Summary.CCNTotal > c.Summary.CCNTotal
+			}
+		}
+		return a.Path < c.Path
+	})
+	b.cursor = clamp(b.cursor, len(b.visible))
+	b.open = false
+}
+
+func (b *Browser) presentSmellKinds() []model.CodeSmellKind {
+	var kinds []model.CodeSmellKind
+	for _, kind := range model.AllCodeSmellKinds() {
+		for _, f := range b.report.Files {
+			if hasSmell(f, kind) {
+				kinds = append(kinds, kind)
+				break
+			}
+		}
+	}
+	return kinds
+}
+
+func hasSmell(f model.FileMetrics, kind model.CodeSmellKind) bool {
+	for _, s := range f.Smells {
+		if s.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func churn(f model.FileMetrics) int {
+	if f.Git == nil {
+		return 0
+	}
+	return f.Git.LinesAdded + f.Git.LinesDeleted
+}
+
+func clamp(i, n int) int {
+	if i >= n {
+		i = n - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return i
+}
+
+func windowStart(cursor, rows int) int {
+	if start := cursor - rows + 1; start > 0 {
+		return start
+	}
+	return 0
+}
+
+func marker(selected bool) string {
+	if selected {
+		return ">"
+	}
+	return " "
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return "…" + string(runes[len(runes)-n+1:])
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+//go:build tui
+
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+type program struct {
+	browser *Browser
+	height  int
+}
+
+func Run(report *model.ProjectReport) error {
+	_, err := tea.NewProgram(&program{browser: NewBrowser(report), height: 24}, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (p *program) Init() tea.Cmd {
+	return nil
+}
+
+func (p *program) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.height = msg.Height
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return p, tea.Quit
+		case "j", "down":
+			p.browser.Move(1)
+		case "k", "up":
+			p.browser.Move(-1)
+		case "pgdown":
+			p.browser.Move(p.height / 2)
+		case "pgup":
+			p.browser.Move(-p.height / 2)
+		case "enter", "l", "right":
+			p.browser.Enter()
+		case "esc", "h", "left", "backspace":
+			p.browser.Back()
+		case "s":
+			p.browser.CycleSort()
+		case "f":
+			p.browser.CycleSmellFilter()
+		}
+	}
+	return p, nil
+}
+
+func (p *program) View() string {
+	return p.browser.View(p.height)
+}
@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+//go:build !tui
+
+package tui
+
+import (
+	"fmt"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+func Run(report *model.ProjectReport) error {
+	return fmt.Errorf("this codeaudit binary was built without the terminal UI; rebuild with -tags tui")
+}
@@ -12,6 +12,26 @@ const (
 	LanguageGo      Language = "go"
 	LanguageC       Language = "c"
 	LanguageCpp     Language = "cpp"
+	LanguageCSharp  Language = "csharp"
+)
+
+type FunctionRole string
+
+const (
+	RoleRegular   FunctionRole = "regular"
+	RoleTest      FunctionRole = "test"
+	RoleBenchmark FunctionRole = "benchmark"
+	RoleExample   FunctionRole = "example"
+	RoleFuzz      FunctionRole = "fuzz"
+	RoleInit      FunctionRole = "init"
+	RoleMain      FunctionRole = "main"
+)
+
+type Divergence string
+
+const (
+	DivergenceFlatBranching Divergence = "flat_branching"
+	DivergenceDeepNesting   Divergence = "deep_nesting"
 )
 
 type MetricID string
@@ -20,6 +40,7 @@ const (
 	MetricCyclomaticCCN        MetricID = "complexity.ccn"
 	MetricCognitiveComplexity  MetricID = "complexity.cognitive"
 	MetricMaxNesting           MetricID = "complexity.max_nesting"
+	MetricPreprocessor         MetricID = "complexity.preprocessor"
 	MetricNLOC                 MetricID = "size.nloc"
 	MetricFunctionNLOC         MetricID = "size.function_nloc"
 	MetricParamsCount          MetricID = "params.count"
@@ -29,6 +50,7 @@ const (
 	MetricAfferentCoupling     MetricID = "coupling.afferent"
 	MetricEfferentCoupling     MetricID = "coupling.efferent"
 	MetricInstability          MetricID = "coupling.instability"
+	MetricDistanceMainSequence MetricID = "coupling.distance"
 	MetricCommentDensity       MetricID = "comments.density"
 	MetricPublicAPIDocCoverage MetricID = "comments.public_api_doc"
 	MetricCloneDensity         MetricID = "clones.density"
@@ -39,82 +61,275 @@ const (
 	MetricGitBugfixCommits     MetricID = "git.commits.bugfix"
 	MetricGitAuthors           MetricID = "git.authors"
 	MetricHotspotScore         MetricID = "hotspot.score_complexity_churn"
+	MetricHealthGrade          MetricID = "health.grade"
 )
 
 type FunctionMetrics struct {
-	Name                string   `json:"name"`
-	Signature           string   `json:"signature"`
-	FilePath            string   `json:"filePath"`
-	Language            Language `json:"language"`
-	StartLine           int      `json:"startLine"`
-	EndLine             int      `json:"endLine"`
-	NLOC                int      `json:"nloc"`
-	Parameters          int      `json:"parameters"`
-	LocalVariables      int      `json:"localVariables"`
-	CCN                 int      `json:"ccn"`
-	CognitiveComplexity int      `json:"cognitiveComplexity"`
-	MaxNesting          int      `json:"maxNesting"`
-	FanIn               int      `json:"fanIn"`
-	FanOut              int      `json:"fanOut"`
-	CommentDensity      float64  `json:"commentDensity"`
-	HotspotScore        float64  `json:"hotspotScore,omitempty"`
-	Callees             []string `json:"callees,omitempty"`
-	IsPublic            bool     `json:"isPublic"`
-	IsDocumented        bool     `json:"isDocumented"`
+	Name                string          `json:"name"`
+	Signature           string          `json:"signature"`
+	FilePath            string          `json:"filePath"`
+	Language            Language        `json:"language"`
+	Role                FunctionRole    `json:"role,omitempty"`
+	StartLine           int             `json:"startLine"`
+	EndLine             int             `json:"endLine"`
+	NLOC                int             `json:"nloc"`
+	PhysicalLines       int             `json:"physicalLines,omitempty"`
+	Parameters          int             `json:"parameters"`
+	ReturnValueCount    int             `json:"returnValueCount,omitempty"`
+	LocalVariables      int             `json:"localVariables"`
+	SurfaceArea         int             `json:"surfaceArea,omitempty"`
+	CCN                 int             `json:"ccn"`
+	CognitiveComplexity int             `json:"cognitiveComplexity"`
+	MaxNesting          int             `json:"maxNesting"`
+	CognitiveToCCN      float64         `json:"cognitiveToCcn,omitempty"`
+	Divergence          Divergence      `json:"complexityDivergence,omitempty"`
+	FanIn               int             `json:"fanIn"`
+	FanOut              int             `json:"fanOut"`
+	InternalFanOut      int             `json:"internalFanOut"`
+	ExternalFanOut      int             `json:"externalFanOut"`
+	FanOutFiles         int             `json:"fanOutFiles"`
+	CommentDensity      float64         `json:"commentDensity"`
+	HotspotScore        float64         `json:"hotspotScore,omitempty"`
+	CCNVsMean           float64         `json:"ccnVsMean,omitempty"`
+	NLOCVsMean          float64         `json:"nlocVsMean,omitempty"`
+	ShadowedVariables   int             `json:"shadowedVariables,omitempty"`
+	GlobalReads         int             `json:"globalReads,omitempty"`
+	GlobalWrites        int             `json:"globalWrites,omitempty"`
+	AvgIdentifierLength float64         `json:"avgIdentifierLength,omitempty"`
+	ShortIdentifiers    int             `json:"shortIdentifiers,omitempty"`
+	Callees             []string        `json:"callees,omitempty"`
+	Operators           *OperatorCounts `json:"operators,omitempty"`
+	IsPublic            bool            `json:"isPublic"`
+	IsDocumented        bool            `json:"isDocumented"`
+	IsExternal          bool            `json:"isExternal,omitempty"`
+	IsDataFunction      bool            `json:"isDataFunction,omitempty"`
+	SuppressedSmells    []CodeSmellKind `json:"-"`
+	UnresolvedRefs      []IdentifierRef `json:"-"`
+}
+
+type IdentifierRef struct {
+	Name  string
+	Line  int
+	Write bool
+}
+
+type OperatorCounts struct {
+	Ternary int `json:"ternary"`
+	Logical int `json:"logical"`
+	Goto    int `json:"goto"`
 }
 
 type CommentMetrics struct {
-	TotalLines      int     `json:"totalLines"`
-	CommentLines    int     `json:"commentLines"`
-	CommentDensity  float64 `json:"commentDensity"`
-	PublicAPIDocPct float64 `json:"publicApiDocPct"`
+	TotalLines       int     `json:"totalLines"`
+	CommentLines     int     `json:"commentLines"`
+	CommentDensity   float64 `json:"commentDensity"`
+	PublicAPIDocPct  float64 `json:"publicApiDocPct"`
+	PublicSymbols    int     `json:"publicSymbols,omitempty"`
+	PublicDocumented int     `json:"publicDocumented,omitempty"`
 }
 
 type CodeSmellKind string
 
 const (
-	SmellManyParameters CodeSmellKind = "many_parameters"
-	SmellManyLocals     CodeSmellKind = "many_locals"
-	SmellDeepNesting    CodeSmellKind = "deep_nesting"
-	SmellGodFunction    CodeSmellKind = "god_function"
-	SmellGlobalState    CodeSmellKind = "global_state"
+	SmellManyParameters    CodeSmellKind = "many_parameters"
+	SmellManyLocals        CodeSmellKind = "many_locals"
+	SmellDeepNesting       CodeSmellKind = "deep_nesting"
+	SmellGodFunction       CodeSmellKind = "god_function"
+	SmellGlobalState       CodeSmellKind = "global_state"
+	SmellShadowedVariable  CodeSmellKind = "shadowed_variable"
+	SmellHighFanOut        CodeSmellKind = "high_fan_out"
+	SmellMixedIndentation  CodeSmellKind = "mixed_indentation"
+	SmellDeadCode          CodeSmellKind = "dead_code"
+	SmellBlankPadding      CodeSmellKind = "blank_padding"
+	SmellMissingDefault    CodeSmellKind = "missing_default"
+	SmellGlobalMutation    CodeSmellKind = "global_mutation"
+	SmellDuplicateInclude  CodeSmellKind = "duplicate_include"
+	SmellCrypticNames      CodeSmellKind = "cryptic_names"
+	SmellManyReturns       CodeSmellKind = "many_returns"
+	SmellLongFunction      CodeSmellKind = "long_function"
+	SmellErrorNotLast      CodeSmellKind = "error_not_last"
+	SmellLargeSurface      CodeSmellKind = "large_surface_area"
+	SmellExcessiveComments CodeSmellKind = "excessive_comments"
+
+	SmellConstructorManyParams CodeSmellKind = "constructor_many_params"
 )
 
+func AllCodeSmellKinds() []CodeSmellKind {
+	return []CodeSmellKind{
+		SmellManyParameters,
+		SmellManyLocals,
+		SmellDeepNesting,
+		SmellGodFunction,
+		SmellGlobalState,
+		SmellShadowedVariable,
+		SmellHighFanOut,
+		SmellMixedIndentation,
+		SmellDeadCode,
+		SmellBlankPadding,
+		SmellMissingDefault,
+		SmellGlobalMutation,
+		SmellDuplicateInclude,
+		SmellCrypticNames,
+		SmellManyReturns,
+		SmellLongFunction,
+		SmellErrorNotLast,
+		SmellLargeSurface,
+		SmellExcessiveComments,
+		SmellConstructorManyParams,
+	}
+}
+
+func SmellRemediation(kind CodeSmellKind) string {
+	switch kind {
+	case SmellManyParameters:
+		return "group related parameters into a struct or split the function by responsibility"
+	case SmellManyLocals:
+		return "extract cohesive groups of locals and the code using them into helper functions"
+	case SmellDeepNesting:
+		return "extract nested blocks into helper functions or use early returns"
+	case SmellGodFunction:
+		return "split the function into smaller functions that each do one thing"
+	case SmellGlobalState:
+		return "pass state explicitly or encapsulate it behind a type"
+	case SmellShadowedVariable:
+		return "rename the inner variable or assign to the outer one with ="
+	case SmellHighFanOut:
+		return "move the orchestration behind a narrower interface or split it by collaborator"
+	case SmellMixedIndentation:
+		return "reindent the file with one style, ideally via the language formatter"
+	case SmellDeadCode:
+		return "delete the function, or wire up the caller it was written for"
+	case SmellBlankPadding:
+		return "remove filler blank lines and stale comments so the function's real length shows"
+	case SmellMissingDefault:
+		return "add a default case that handles or rejects unexpected values"
+	case SmellGlobalMutation:
+		return "return the new value or mutate state owned by a receiver instead of a package variable"
+	case SmellDuplicateInclude:
+		return "remove the repeated #include; the first one already brings the header in"
+	case SmellCrypticNames:
+		return "give parameters and locals descriptive names; keep one-letter names for short loops"
+	case SmellManyReturns:
+		return "return a small struct, or split the function so each part returns less"
+	case SmellLongFunction:
+		return "extract cohesive blocks into well-named helper functions"
+	case SmellErrorNotLast:
+		return "move the error to the last result, as callers expect (v, err)"
+	case SmellLargeSurface:
+		return "split the function or group its inputs and outputs into types"
+	case SmellExcessiveComments:
+		return "delete commented-out code (version control keeps it) and prune stale comments"
+	case SmellConstructorManyParams:
+		return "introduce a parameter object or builder, or split the class"
+	default:
+		return ""
+	}
+}
+
 type CodeSmell struct {
 	Kind        CodeSmellKind `json:"kind"`
 	Description string        `json:"description"`
 	FilePath    string        `json:"filePath"`
 	Function    string        `json:"function,omitempty"`
 	Line        int           `json:"line,omitempty"`
+	Class       string        `json:"class,omitempty"`
+	Remediation string        `json:"remediation,omitempty"`
+}
+
+type IssueSeverity string
+
+const (
+	SeverityInfo    IssueSeverity = "info"
+	SeverityWarning IssueSeverity = "warning"
+	SeverityError   IssueSeverity = "error"
+)
+
+func SmellSeverity(kind CodeSmellKind) IssueSeverity {
+	switch kind {
+	case SmellGodFunction:
+		return SeverityError
+	case SmellManyLocals, SmellMixedIndentation, SmellBlankPadding, SmellDuplicateInclude, SmellCrypticNames, SmellExcessiveComments:
+		return SeverityInfo
+	default:
+		return SeverityWarning
+	}
+}
+
+type Issue struct {
+	RuleID      string        `json:"ruleId"`
+	Severity    IssueSeverity `json:"severity"`
+	Message     string        `json:"message"`
+	FilePath    string        `json:"filePath"`
+	Function    string        `json:"function,omitempty"`
+	Class       string        `json:"class,omitempty"`
+	Line        int           `json:"line,omitempty"`
+	Remediation string        `json:"remediation,omitempty"`
 }
 
 type GitFileMetrics struct {
-	FilePath      string `json:"filePath"`
-	LinesAdded    int    `json:"linesAdded"`
-	LinesDeleted  int    `json:"linesDeleted"`
-	Commits       int    `json:"commits"`
-	BugfixCommits int    `json:"bugfixCommits"`
-	Authors       int    `json:"authors"`
+	FilePath       string `json:"filePath"`
+	LinesAdded     int    `json:"linesAdded"`
+	LinesDeleted   int    `json:"linesDeleted"`
+	Commits        int    `json:"commits"`
+	BugfixCommits  int    `json:"bugfixCommits"`
+	Authors        int    `json:"authors"`
+	DominantAuthor string `json:"dominantAuthor,omitempty"`
 }
 
 type FileSummaryMetrics struct {
-	NLOC              int     `json:"nloc"`
-	CCNTotal          int     `json:"ccnTotal"`
-	CCNAvgPerFunction float64 `json:"ccnAvgPerFunction"`
-	CCNMaxFunction    int     `json:"ccnMaxFunction"`
-	FunctionsCount    int     `json:"functionsCount"`
-	FunctionsCCNGt10  int     `json:"functionsCcnGt10"`
-	FunctionsCCNGt20  int     `json:"functionsCcnGt20"`
+	NLOC                int     `json:"nloc"`
+	CCNTotal            int     `json:"ccnTotal"`
+	CCNAvgPerFunction   float64 `json:"ccnAvgPerFunction"`
+	CCNMaxFunction      int     `json:"ccnMaxFunction"`
+	FunctionsCount      int     `json:"functionsCount"`
+	FunctionsCCNGt10    int     `json:"functionsCcnGt10"`
+	FunctionsCCNGt20    int     `json:"functionsCcnGt20"`
+	LongestFunctionName string  `json:"longestFunctionName,omitempty"`
+	LongestFunctionNLOC int     `json:"longestFunctionNloc,omitempty"`
 }
 
 type FileMetrics struct {
-	Path      string             `json:"path"`
-	Language  Language           `json:"language"`
-	Summary   FileSummaryMetrics `json:"summary"`
-	Functions []FunctionMetrics  `json:"functions"`
-	Comments  CommentMetrics     `json:"comments"`
-	Smells    []CodeSmell        `json:"smells"`
-	Git       *GitFileMetrics    `json:"git,omitempty"`
+	Path           string             `json:"path"`
+	Language       Language           `json:"language"`
+	Package        string             `json:"package,omitempty"`
+	Test           bool               `json:"test,omitempty"`
+	Summary        FileSummaryMetrics `json:"summary"`
+	Functions      []FunctionMetrics  `json:"functions"`
+	Comments       CommentMetrics     `json:"comments"`
+	Smells         []CodeSmell        `json:"smells"`
+	Git            *GitFileMetrics    `json:"git,omitempty"`
+	Warnings       []string           `json:"warnings,omitempty"`
+	Imports        []string           `json:"imports,omitempty"`
+	TypeDecls      int                `json:"typeDecls,omitempty"`
+	InterfaceDecls int                `json:"interfaceDecls,omitempty"`
+
+	PreprocessorComplexity int `json:"preprocessorComplexity,omitempty"`
+
+	FileLinesTotal   int `json:"fileLinesTotal"`
+	FileLinesCode    int `json:"fileLinesCode"`
+	FileLinesComment int `json:"fileLinesComment"`
+
+	OtherFunctions    *FunctionAggregate `json:"otherFunctions,omitempty"`
+	ExternalFunctions []FunctionMetrics  `json:"externalFunctions,omitempty"`
+	PackageVars       []string           `json:"-"`
+}
+
+type FunctionAggregate struct {
+	Count      int             `json:"count"`
+	NLOC       int             `json:"nloc"`
+	CCNTotal   int             `json:"ccnTotal"`
+	CCNMax     int             `json:"ccnMax"`
+	Parameters int             `json:"parameters"`
+	ParamsGe5  int             `json:"paramsGe5,omitempty"`
+	Shapes     []FunctionShape `json:"shapes,omitempty"`
+}
+
+type FunctionShape struct {
+	NLOC                int  `json:"nloc"`
+	CCN                 int  `json:"ccn"`
+	CognitiveComplexity int  `json:"cognitiveComplexity"`
+	IsDataFunction      bool `json:"isDataFunction,omitempty"`
+	Count               int  `json:"count"`
 }
 
 type Hotspot struct {
@@ -133,6 +348,8 @@ type ProjectMetrics struct {
 	FunctionsCCNGt10Pct float64 `json:"functionsCcnGt10Pct"`
 	FunctionsCCNGt20Pct float64 `json:"functionsCcnGt20Pct"`
 
+	DeclarationOnlyFiles int `json:"declarationOnlyFiles"`
+
 	MedianFunctionSize  float64 `json:"medianFunctionSize"`
 	P95FunctionSize     float64 `json:"p95FunctionSize"`
 	FunctionsGt50Lines  int     `json:"functionsGt50Lines"`
@@ -142,11 +359,110 @@ type ProjectMetrics struct {
 	AvgParamsPerFunction float64 `json:"avgParamsPerFunction"`
 	FunctionsParamsGe5   int     `json:"functionsParamsGe5"`
 
-	CommentDensityAvg float64 `json:"commentDensityAvg"`
+	CommentDensityAvg      float64 `json:"commentDensityAvg"`
+	CommentDensityWeighted float64 `json:"commentDensityWeighted"`
+
+	PublicAPISymbols    int     `json:"publicApiSymbols"`
+	PublicAPIDocumented int     `json:"publicApiDocumented"`
+	PublicAPIDocPct     float64 `json:"publicApiDocPct"`
 
 	GitTotalLinesAdded   int `json:"gitTotalLinesAdded"`
 	GitTotalLinesDeleted int `json:"gitTotalLinesDeleted"`
 	GitTotalCommits      int `json:"gitTotalCommits"`
+
+	SmellCountsByKind map[CodeSmellKind]int `json:"smellCountsByKind,omitempty"`
+}
+
+type DirectoryMetrics struct {
+	Path            string  `json:"path"`
+	Files           int     `json:"files"`
+	Functions       int     `json:"functions"`
+	CCNTotal        int     `json:"ccnTotal"`
+	AvgFunctionNLOC float64 `json:"avgFunctionNloc"`
+	Smells          int     `json:"smells"`
+}
+
+type PackageMetrics struct {
+	Path         string   `json:"path"`
+	Files        int      `json:"files"`
+	Afferent     int      `json:"afferent"`
+	Efferent     int      `json:"efferent"`
+	Instability  *float64 `json:"instability,omitempty"`
+	Isolated     bool     `json:"isolated,omitempty"`
+	Abstractness *float64 `json:"abstractness,omitempty"`
+	Distance     *float64 `json:"distance,omitempty"`
+}
+
+type AuthorComplexity struct {
+	Author    string `json:"author"`
+	Files     int    `json:"files"`
+	Functions int    `json:"functions"`
+	CCNTotal  int    `json:"ccnTotal"`
+}
+
+type LineContribution struct {
+	Line       int      `json:"line"`
+	Code       string   `json:"code"`
+	CCN        int      `json:"ccn"`
+	Cognitive  int      `json:"cognitive"`
+	Constructs []string `json:"constructs,omitempty"`
+}
+
+type FunctionExplanation struct {
+	FilePath  string             `json:"filePath"`
+	Function  string             `json:"function"`
+	StartLine int                `json:"startLine"`
+	EndLine   int                `json:"endLine"`
+	BaseCCN   int                `json:"baseCcn"`
+	CCN       int                `json:"ccn"`
+	Cognitive int                `json:"cognitive"`
+	Lines     []LineContribution `json:"lines"`
+}
+
+type RunSummary struct {
+	Command         string   `json:"command"`
+	FilesAnalyzed   int      `json:"filesAnalyzed"`
+	Warnings        int      `json:"warnings"`
+	Error           string   `json:"error,omitempty"`
+	ThresholdPassed *bool    `json:"thresholdPassed,omitempty"`
+	Failing         []string `json:"failing,omitempty"`
+	ExitCode        int      `json:"exitCode"`
+	DurationMillis  int64    `json:"durationMillis"`
+}
+
+type CoChange struct {
+	FileA   string  `json:"fileA"`
+	FileB   string  `json:"fileB"`
+	Commits int     `json:"commits"`
+	Degree  float64 `json:"degree"`
+}
+
+type CIMetadata struct {
+	Branch      string `json:"branch,omitempty"`
+	Commit      string `json:"commit,omitempty"`
+	BuildID     string `json:"buildId,omitempty"`
+	PullRequest string `json:"pullRequest,omitempty"`
+}
+
+type HealthGrade struct {
+	Grade            string  `json:"grade"`
+	Score            float64 `json:"score"`
+	Complexity       float64 `json:"complexity"`
+	Size             float64 `json:"size"`
+	CyclomaticWeight float64 `json:"cyclomaticWeight"`
+	CognitiveWeight  float64 `json:"cognitiveWeight"`
+}
+
+type SkippedFile struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+type RunStats struct {
+	WallTimeMillis int64   `json:"wallTimeMillis"`
+	FilesAnalyzed  int     `json:"filesAnalyzed"`
+	FilesPerSecond float64 `json:"filesPerSecond"`
+	PeakHeapBytes  uint64  `json:"peakHeapBytes"`
 }
 
 type MetricSummary struct {
@@ -156,14 +472,47 @@ type MetricSummary struct {
 	Group       string   `json:"group"`
 }
 
+type MetricDelta struct {
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+	Change   float64 `json:"change"`
+}
+
+type ReportDelta struct {
+	BaselinePath        string      `json:"baselinePath,omitempty"`
+	BaselineGeneratedAt time.Time   `json:"baselineGeneratedAt"`
+	Files               MetricDelta `json:"files"`
+	Functions           MetricDelta `json:"functions"`
+	AvgCCNPerFunction   MetricDelta `json:"avgCcnPerFunction"`
+	MaxCCNPerFunction   MetricDelta `json:"maxCcnPerFunction"`
+	Smells              MetricDelta `json:"smells"`
+}
+
+type ReportFilter struct {
+	Glob       string `json:"glob"`
+	Matched    int    `json:"matched"`
+	TotalFiles int    `json:"totalFiles"`
+}
+
 type ProjectReport struct {
-	RootPath       string          `json:"rootPath"`
-	GeneratedAt    time.Time       `json:"generatedAt"`
-	Files          []FileMetrics   `json:"files"`
-	Project        ProjectMetrics  `json:"project"`
-	Hotspots       []Hotspot       `json:"hotspots"`
-	MetricMetadata []MetricSummary `json:"metricMetadata"`
-	Warnings       []string        `json:"warnings,omitempty"`
+	RootPath         string             `json:"rootPath"`
+	GeneratedAt      time.Time          `json:"generatedAt"`
+	Filter           *ReportFilter      `json:"filter,omitempty"`
+	CI               *CIMetadata        `json:"ci,omitempty"`
+	Files            []FileMetrics      `json:"files"`
+	Project          ProjectMetrics     `json:"project"`
+	Health           *HealthGrade       `json:"health,omitempty"`
+	Hotspots         []Hotspot          `json:"hotspots"`
+	Issues           []Issue            `json:"issues,omitempty"`
+	Directories      []DirectoryMetrics `json:"directories,omitempty"`
+	Packages         []PackageMetrics   `json:"packages,omitempty"`
+	AuthorComplexity []AuthorComplexity `json:"authorComplexity,omitempty"`
+	CoChanges        []CoChange         `json:"coChanges,omitempty"`
+	MetricMetadata   []MetricSummary    `json:"metricMetadata"`
+	Warnings         []string           `json:"warnings,omitempty"`
+	SkippedFiles     []SkippedFile      `json:"skippedFiles,omitempty"`
+	Stats            *RunStats          `json:"stats,omitempty"`
+	Delta            *ReportDelta       `json:"delta,omitempty"`
 }
 
 func AllMetricSummaries() []MetricSummary {
@@ -177,7 +526,7 @@ func AllMetricSummaries() []MetricSummary {
 		{
 			ID:          MetricCognitiveComplexity,
 			Name:        "Cognitive Complexity",
-			Description: "Nesting and boolean-logic–aware complexity per function.",
+			Description: "Nesting and boolean-logic–aware complexity per function (line-based model by default, Sonar-style AST model for Go with --cognitive-model sonar). --max-cognitive-per-line caps each line's increment in the line-based model; --skip-generated-cognitive scores files marked \"Code generated ... DO NOT EDIT.\" as 0. cognitiveToCcn is the cognitive/CCN ratio; --max-complexity-divergence notes functions far above (deep_nesting) or below (flat_branching) 1.",
 			Group:       "complexity",
 		},
 		{
@@ -186,6 +535,12 @@ func AllMetricSummaries() []MetricSummary {
 			Description: "Maximum depth of nested control structures.",
 			Group:       "complexity",
 		},
+		{
+			ID:          MetricPreprocessor,
+			Name:        "Preprocessor Complexity",
+			Description: "Conditional preprocessor branches (#if, #ifdef, #ifndef, #elif, #else) per C/C++ file, excluding include guards; kept separate from CCN. Enabled with --preprocessor-complexity.",
+			Group:       "complexity",
+		},
 		{
 			ID:          MetricNLOC,
 			Name:        "NLOC",
@@ -237,7 +592,13 @@ func AllMetricSummaries() []MetricSummary {
 		{
 			ID:          MetricInstability,
 			Name:        "Instability",
-			Description: "Ce / (Ca + Ce), 0 = stable, 1 = unstable.",
+			Description: "Ce / (Ca + Ce), 0 = stable, 1 = unstable. Isolated packages (Ca + Ce = 0) are marked isolated and score 0, or are left without a value with --omit-isolated-instability.",
+			Group:       "coupling",
+		},
+		{
+			ID:          MetricDistanceMainSequence,
+			Name:        "Distance from Main Sequence (D)",
+			Description: "|A + I - 1| per Go package, where A is the share of interface types.",
 			Group:       "coupling",
 		},
 		{
@@ -249,7 +610,7 @@ func AllMetricSummaries() []MetricSummary {
 		{
 			ID:          MetricPublicAPIDocCoverage,
 			Name:        "Public API Doc Coverage",
-			Description: "Percentage of public functions with documentation.",
+			Description: "Percentage of public functions, methods and types with documentation.",
 			Group:       "comments",
 		},
 		{
@@ -297,8 +658,14 @@ func AllMetricSummaries() []MetricSummary {
 		{
 			ID:          MetricHotspotScore,
 			Name:        "Hotspot Score",
-			Description: "Heuristic score combining complexity and churn.",
+			Description: "Heuristic score combining complexity and churn: ccn * log1p(churn) by default. --hotspot-model authors multiplies it by 1 + log1p(authors) so files touched by many hands rank higher; --hotspot-formula sets a custom expression.",
 			Group:       "hotspots",
 		},
+		{
+			ID:          MetricHealthGrade,
+			Name:        "Health Grade",
+			Description: "Letter grade (A-F) from a 0-100 score: 70% complexity, 30% function size. The complexity component blends per-function CCN and cognitive complexity, weighted cyclomatic=0.5,cognitive=0.5 by default; --health-weights changes the blend (e.g. cognitive=1 to grade on cognitive complexity only).",
+			Group:       "health",
+		},
 	}
 }
@@ -19,14 +19,35 @@ type FileReader interface {
 
 type CodeParser interface {
 	Name() string
+	Extensions() []string
 	SupportsFile(path string) bool
 	ParseFile(path string, src []byte) (*model.FileMetrics, error)
 }
 
+type ContentMatcher interface {
+	MatchesContent(path string, src []byte) bool
+}
+
+type FunctionExplainer interface {
+	ExplainFunction(path string, src []byte, function string) (*model.FunctionExplanation, error)
+}
+
 type GitClient interface {
 	CollectFileMetrics(ctx context.Context, root string) (map[string]*model.GitFileMetrics, error)
 }
 
+type AuthorMetricsCollector interface {
+	CollectFileMetricsWithAuthors(ctx context.Context, root string) (map[string]*model.GitFileMetrics, error)
+}
+
+type CoChangeCollector interface {
+	CollectCommitFiles(ctx context.Context, root string) ([][]string, error)
+}
+
+type DirtyFileLister interface {
+	DirtyFiles(ctx context.Context, root string) ([]string, error)
+}
+
 type ReportStorage interface {
 	Save(ctx context.Context, root string, report *model.ProjectReport) error
 	Load(ctx context.Context, root string) (*model.ProjectReport, error)
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package infrastructure
+
+import (
+	"regexp"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+var (
+	ciBranchEnv = []string{
+		"GITHUB_HEAD_REF", "GITHUB_REF_NAME", "CI_COMMIT_REF_NAME", "BUILDKITE_BRANCH",
+		"CIRCLE_BRANCH", "BRANCH_NAME", "GIT_BRANCH",
+	}
+	ciCommitEnv = []string{
+		"GITHUB_SHA", "CI_COMMIT_SHA", "BUILDKITE_COMMIT", "CIRCLE_SHA1", "GIT_COMMIT",
+	}
+	ciBuildIDEnv = []string{
+		"GITHUB_RUN_ID", "CI_PIPELINE_ID", "BUILDKITE_BUILD_NUMBER", "CIRCLE_BUILD_NUM", "BUILD_NUMBER",
+	}
+	ciPullRequestEnv = []string{
+		"CI_MERGE_REQUEST_IID", "BUILDKITE_PULL_REQUEST", "CIRCLE_PR_NUMBER", "CHANGE_ID",
+	}
+)
+
+var githubPullRefRe = regexp.MustCompile(`^refs/pull/(\d+)/`)
+
+func ResolveCIMetadata(explicit model.CIMetadata, getenv func(string) string) *model.CIMetadata {
+	ci := model.CIMetadata{
+		Branch:      firstEnv(getenv, ciBranchEnv),
+		Commit:      firstEnv(getenv, ciCommitEnv),
+		BuildID:     firstEnv(getenv, ciBuildIDEnv),
+		PullRequest: firstEnv(getenv, ciPullRequestEnv),
+	}
+	if ci.PullRequest == "" {
+		if m := githubPullRefRe.FindStringSubmatch(getenv("GITHUB_REF")); m != nil {
+			ci.PullRequest = m[1]
+		}
+	}
+
+	if explicit.Branch != "" {
+		ci.Branch = explicit.Branch
+	}
+	if explicit.Commit != "" {
+		ci.Commit = explicit.Commit
+	}
+	if explicit.BuildID != "" {
+		ci.BuildID = explicit.BuildID
+	}
+	if explicit.PullRequest != "" {
+		ci.PullRequest = explicit.PullRequest
+	}
+
+	if ci == (model.CIMetadata{}) {
+		return nil
+	}
+	return &ci
+}
+
+func firstEnv(getenv func(string) string, names []string) string {
+	for _, name := range names {
+		if v := getenv(name); v != "" && v != "false" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package infrastructure
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var codeOwnersLocations = []string{"CODEOWNERS", ".github/CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+type codeOwnersRule struct {
+	pattern string
+	re      *regexp.Regexp
+	owners  []string
+}
+
+type CodeOwners struct {
+	rules []codeOwnersRule
+}
+
+func LoadCodeOwners(root string) (*CodeOwners, error) {
+	for _, loc := range codeOwnersLocations {
+		f, err := os.Open(filepath.Join(root, filepath.FromSlash(loc)))
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+		owners, err := ParseCodeOwners(f)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", loc, err)
+		}
+		return owners, nil
+	}
+	return nil, fmt.Errorf("no CODEOWNERS file found under %s (looked in %s)", root, strings.Join(codeOwnersLocations, ", "))
+}
+
+func ParseCodeOwners(r io.Reader) (*CodeOwners, error) {
+	co := &CodeOwners{}
+	sc := bufio.NewScanner(r)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		fields := strings.Fields(line)
+		re, err := codeOwnersRegexp(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		co.rules = append(co.rules, codeOwnersRule{pattern: fields[0], re: re, owners: fields[1:]})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return co, nil
+}
+
+func (co *CodeOwners) Owners(relPath string) []string {
+	relPath = strings.TrimPrefix(filepath.ToSlash(relPath), "./")
+	for i := len(co.rules) - 1; i >= 0; i-- {
+		if co.rules[i].re.MatchString(relPath) {
+			return co.rules[i].owners
+		}
+	}
+	return nil
+}
+
+func (co *CodeOwners) IsOwnedBy(relPath, owner string) bool {
+	for _, o := range co.Owners(relPath) {
+		if strings.EqualFold(o, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+func codeOwnersRegexp(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.TrimSuffix(pattern, "/")
+	anchored := strings.HasPrefix(p, "/") || strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return nil, fmt.Errorf("empty pattern %q", pattern)
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	last := p[strings.LastIndex(p, "/")+1:]
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case strings.ContainsAny(last, "*?"):
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package infrastructure
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+func ValidateEncoding(name string) error {
+	_, err := lookupEncoding(name)
+	return err
+}
+
+func lookupEncoding(name string) (encoding.Encoding, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "utf-8", "utf8":
+		return nil, nil
+	}
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown source encoding %q: %w", name, err)
+	}
+	return enc, nil
+}
+
+func hasUTF16BOM(src []byte) bool {
+	return bytes.HasPrefix(src, []byte{0xFF, 0xFE}) || bytes.HasPrefix(src, []byte{0xFE, 0xFF})
+}
+
+func transcodeSource(src []byte, enc encoding.Encoding) ([]byte, error) {
+	if enc == nil && !hasUTF16BOM(src) {
+		return src, nil
+	}
+	fallback := encoding.Nop.NewDecoder()
+	if enc != nil {
+		fallback = enc.NewDecoder()
+	}
+	out, _, err := transform.Bytes(unicode.BOMOverride(fallback), src)
+	if err != nil {
+		return nil, fmt.Errorf("transcode to UTF-8: %w", err)
+	}
+	return out, nil
+}
@@ -10,15 +10,39 @@ import (
 	"path/filepath"
 	"strings"
 
+	"golang.org/x/text/encoding"
+
 	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
 )
 
-type FSScanner struct{}
+type FSScannerOptions struct {
+	IncludeVendored bool
+	NoDefaultSkips  bool
+	SkipHidden      bool
+
+	LimitDepth bool
+	MaxDepth   int
+
+	Encoding string
+
+	CodeOwners *CodeOwners
+	Owner      string
+}
+
+type FSScanner struct {
+	opts     FSScannerOptions
+	encoding encoding.Encoding
+}
 
 func NewFSScanner() *FSScanner {
 	return &FSScanner{}
 }
 
+func NewFSScannerWithOptions(opts FSScannerOptions) *FSScanner {
+	enc, _ := lookupEncoding(opts.Encoding)
+	return &FSScanner{opts: opts, encoding: enc}
+}
+
 var _ ports.SourceFileScanner = (*FSScanner)(nil)
 var _ ports.FileReader = (*FSScanner)(nil)
 
@@ -34,14 +58,15 @@ func (s *FSScanner) Scan(ctx context.Context, root string, includeExt []string)
 		if err != nil {
 			return err
 		}
+		hidden := s.opts.SkipHidden && path != root && isHidden(d.Name())
 		if d.IsDir() {
-			name := d.Name()
-			switch name {
-			case ".git", "vendor", "node_modules", ".codeaudit":
+			if hidden || s.skipDir(d.Name()) || s.tooDeep(root, path) {
 				return filepath.SkipDir
-			default:
-				return nil
 			}
+			return nil
+		}
+		if hidden {
+			return nil
 		}
 
 		select {
@@ -61,6 +86,10 @@ func (s *FSScanner) Scan(ctx context.Context, root string, includeExt []string)
 			}
 		}
 
+		if s.opts.Owner != "" && !s.ownedBy(root, path) {
+			return nil
+		}
+
 		files = append(files, path)
 		return nil
 	})
@@ -68,6 +97,50 @@ func (s *FSScanner) Scan(ctx context.Context, root string, includeExt []string)
 	return files, err
 }
 
+func (s *FSScanner) skipDir(name string) bool {
+	if s.opts.NoDefaultSkips {
+		return false
+	}
+	switch name {
+	case ".git", ".codeaudit":
+		return true
+	case "vendor", "node_modules":
+		return !s.opts.IncludeVendored
+	default:
+		return false
+	}
+}
+
+func (s *FSScanner) tooDeep(root, dir string) bool {
+	if !s.opts.LimitDepth || dir == root {
+		return false
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return false
+	}
+	return strings.Count(filepath.ToSlash(rel), "/")+1 > s.opts.MaxDepth
+}
+
+func (s *FSScanner) ownedBy(root, path string) bool {
+	if s.opts.CodeOwners == nil {
+		return false
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return s.opts.CodeOwners.IsOwnedBy(rel, s.opts.Owner)
+}
+
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, ".") && name != "." && name != ".."
+}
+
 func (s *FSScanner) ReadFile(path string) ([]byte, error) {
-	return os.ReadFile(path)
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return transcodeSource(src, s.encoding)
 }
@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package infrastructure
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const SourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
+func ResolveGeneratedAt(explicit string) (time.Time, error) {
+	if explicit != "" {
+		if t, err := time.Parse(time.RFC3339, explicit); err == nil {
+			return t.UTC(), nil
+		}
+		t, err := parseEpoch(explicit)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid generated-at %q: want RFC 3339 or unix seconds", explicit)
+		}
+		return t, nil
+	}
+	if epoch := os.Getenv(SourceDateEpochEnv); epoch != "" {
+		t, err := parseEpoch(epoch)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s %q: %w", SourceDateEpochEnv, epoch, err)
+		}
+		return t, nil
+	}
+	return time.Time{}, nil
+}
+
+func parseEpoch(s string) (time.Time, error) {
+	secs, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(secs, 0).UTC(), nil
+}
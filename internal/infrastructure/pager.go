@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package infrastructure
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+type PagerMode int
+
+const (
+	PagerAuto PagerMode = iota
+	PagerAlways
+	PagerNever
+)
+
+const defaultPagerCommand = "less"
+
+const defaultTerminalHeight = 24
+
+type PagerOptions struct {
+	Mode     PagerMode
+	Command  string
+	Out      io.Writer
+	Terminal bool
+	Height   int
+}
+
+type Pager struct {
+	opts PagerOptions
+}
+
+func NewPager(mode PagerMode) *Pager {
+	command := os.Getenv("PAGER")
+	if command == "" {
+		command = defaultPagerCommand
+	}
+	height := terminalHeight(os.Stdout)
+	if height <= 0 {
+		if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+			height = n
+		}
+	}
+	return NewPagerWithOptions(PagerOptions{
+		Mode:     mode,
+		Command:  command,
+		Out:      os.Stdout,
+		Terminal: isTerminal(os.Stdout),
+		Height:   height,
+	})
+}
+
+func NewPagerWithOptions(opts PagerOptions) *Pager {
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+	if opts.Height <= 0 {
+		opts.Height = defaultTerminalHeight
+	}
+	return &Pager{opts: opts}
+}
+
+func (p *Pager) Print(text string) error {
+	if !p.shouldPage(text) {
+		return p.printDirect(text)
+	}
+
+	args := strings.Fields(p.opts.Command)
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return p.printDirect(text)
+	}
+
+	cmd := exec.Command(path, args[1:]...)
+	cmd.Stdin = strings.NewReader(text + "\n")
+	cmd.Stdout = p.opts.Out
+	cmd.Stderr = os.Stderr
+	if os.Getenv("LESS") == "" {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	if err := cmd.Start(); err != nil {
+		return p.printDirect(text)
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("run pager %s: %w", args[0], err)
+	}
+	return nil
+}
+
+func (p *Pager) shouldPage(text string) bool {
+	if p.opts.Mode == PagerNever || !p.opts.Terminal {
+		return false
+	}
+	if len(strings.Fields(p.opts.Command)) == 0 {
+		return false
+	}
+	if p.opts.Mode == PagerAlways {
+		return true
+	}
+	return strings.Count(text, "\n")+1 > p.opts.Height
+}
+
+func (p *Pager) printDirect(text string) error {
+	_, err := fmt.Fprintln(p.opts.Out, text)
+	return err
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+func WritePerFileReports(dir string, report *model.ProjectReport, indent string) (int, error) {
+	written := 0
+	for i := range report.Files {
+		f := &report.Files[i]
+		name, err := perFileReportName(report.RootPath, f.Path)
+		if err != nil {
+			return written, err
+		}
+		dest := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return written, fmt.Errorf("create per-file report dir: %w", err)
+		}
+
+		var data []byte
+		if indent == "" {
+			data, err = json.Marshal(f)
+		} else {
+			data, err = json.MarshalIndent(f, "", indent)
+		}
+		if err != nil {
+			return written, fmt.Errorf("encode %s: %w", f.Path, err)
+		}
+		if err := os.WriteFile(dest, append(data, '\n'), 0o644); err != nil {
+			return written, fmt.Errorf("write per-file report %s: %w", dest, err)
+		}
+		written++
+	}
+	return written, nil
+}
+
+func perFileReportName(root, path string) (string, error) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return "", fmt.Errorf("per-file report name for %s: %w", path, err)
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("per-file report name for %s: outside root %s", path, root)
+	}
+	return rel + ".json", nil
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+type RunSummaryWriter struct {
+	path    string
+	started time.Time
+	summary model.RunSummary
+}
+
+func NewRunSummaryWriter(path, command string) *RunSummaryWriter {
+	return &RunSummaryWriter{
+		path:    path,
+		started: time.Now(),
+		summary: model.RunSummary{Command: command},
+	}
+}
+
+func (w *RunSummaryWriter) RecordReport(report *model.ProjectReport) {
+	if report == nil {
+		return
+	}
+	w.summary.FilesAnalyzed = len(report.Files)
+	w.summary.Warnings = len(report.Warnings)
+}
+
+func (w *RunSummaryWriter) RecordFiles(count int) {
+	w.summary.FilesAnalyzed = count
+}
+
+func (w *RunSummaryWriter) RecordThreshold(failing []string) {
+	passed := len(failing) == 0
+	w.summary.ThresholdPassed = &passed
+	w.summary.Failing = failing
+}
+
+func (w *RunSummaryWriter) Finish(runErr error) error {
+	if w.path == "" {
+		return runErr
+	}
+
+	w.summary.DurationMillis = time.Since(w.started).Milliseconds()
+	w.summary.ExitCode = 0
+	w.summary.Error = ""
+	if runErr != nil {
+		w.summary.ExitCode = 1
+		w.summary.Error = runErr.Error()
+	}
+
+	data, err := json.MarshalIndent(w.summary, "", DefaultJSONIndent)
+	if err == nil {
+		err = os.WriteFile(w.path, append(data, '\n'), 0o644)
+	}
+	if err != nil && runErr == nil {
+		return fmt.Errorf("write run summary: %w", err)
+	}
+	return runErr
+}
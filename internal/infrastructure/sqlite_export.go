@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS project (
+	root_path              TEXT NOT NULL,
+	generated_at           TEXT NOT NULL,
+	total_files            INTEGER NOT NULL,
+	total_functions        INTEGER NOT NULL,
+	avg_ccn_per_function   REAL NOT NULL,
+	max_ccn_per_function   INTEGER NOT NULL,
+	median_function_size   REAL NOT NULL,
+	comment_density        REAL NOT NULL,
+	git_total_commits      INTEGER NOT NULL,
+	PRIMARY KEY (root_path, generated_at)
+);
+CREATE TABLE IF NOT EXISTS files (
+	root_path       TEXT NOT NULL,
+	generated_at    TEXT NOT NULL,
+	path            TEXT NOT NULL,
+	language        TEXT NOT NULL,
+	nloc            INTEGER NOT NULL,
+	ccn_total       INTEGER NOT NULL,
+	ccn_max         INTEGER NOT NULL,
+	functions_count INTEGER NOT NULL,
+	comment_density REAL NOT NULL,
+	commits         INTEGER,
+	PRIMARY KEY (root_path, generated_at, path)
+);
+CREATE TABLE IF NOT EXISTS functions (
+	root_path    TEXT NOT NULL,
+	generated_at TEXT NOT NULL,
+	file_path    TEXT NOT NULL,
+	name         TEXT NOT NULL,
+	start_line   INTEGER NOT NULL,
+	end_line     INTEGER NOT NULL,
+	nloc         INTEGER NOT NULL,
+	ccn          INTEGER NOT NULL,
+	cognitive    INTEGER NOT NULL,
+	max_nesting  INTEGER NOT NULL,
+	parameters   INTEGER NOT NULL,
+	fan_in       INTEGER NOT NULL,
+	fan_out      INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS smells (
+	root_path    TEXT NOT NULL,
+	generated_at TEXT NOT NULL,
+	file_path    TEXT NOT NULL,
+	kind         TEXT NOT NULL,
+	function     TEXT,
+	line         INTEGER,
+	description  TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS functions_run ON functions (root_path, generated_at);
+CREATE INDEX IF NOT EXISTS smells_run ON smells (root_path, generated_at);
+`
+
+func ExportToSQLite(ctx context.Context, dbPath string, report *model.ProjectReport) error {
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return fmt.Errorf("open sqlite database: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.ExecContext(ctx, sqliteSchema); err != nil {
+		return fmt.Errorf("create sqlite schema: %w", err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin sqlite transaction: %w", err)
+	}
+	if err := insertReport(ctx, tx, report); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit sqlite transaction: %w", err)
+	}
+	return nil
+}
+
+func insertReport(ctx context.Context, tx *sql.Tx, report *model.ProjectReport) error {
+	root := report.RootPath
+	at := report.GeneratedAt.UTC().Format(time.RFC3339Nano)
+
+	for _, table := range []string{"project", "files", "functions", "smells"} {
+		if _, err := tx.ExecContext(ctx,
+			"DELETE FROM "+table+" WHERE root_path = ? AND generated_at = ?", root, at); err != nil {
+			return fmt.Errorf("clear previous %s rows: %w", table, err)
+		}
+	}
+
+	p := report.Project
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO project VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		root, at, p.TotalFiles, p.TotalFunctions, p.AvgCCNPerFunction, p.MaxCCNPerFunction,
+		p.MedianFunctionSize, p.CommentDensityWeighted, p.GitTotalCommits,
+	); err != nil {
+		return fmt.Errorf("insert project row: %w", err)
+	}
+
+	for _, f := range report.Files {
+		var commits sql.NullInt64
+		if f.Git != nil {
+			commits = sql.NullInt64{Int64: int64(f.Git.Commits), Valid: true}
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO files VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			root, at, f.Path, string(f.Language), f.Summary.NLOC, f.Summary.CCNTotal,
+			f.Summary.CCNMaxFunction, f.Summary.FunctionsCount, f.Comments.CommentDensity, commits,
+		); err != nil {
+			return fmt.Errorf("insert file %s: %w", f.Path, err)
+		}
+
+		for _, fn := range f.Functions {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO functions VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				root, at, f.Path, fn.Name, fn.StartLine, fn.EndLine, fn.NLOC, fn.CCN,
+				fn.CognitiveComplexity, fn.MaxNesting, fn.Parameters, fn.FanIn, fn.FanOut,
+			); err != nil {
+				return fmt.Errorf("insert function %s: %w", fn.Name, err)
+			}
+		}
+
+		for _, sm := range f.Smells {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO smells VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				root, at, f.Path, string(sm.Kind), sm.Function, sm.Line, sm.Description,
+			); err != nil {
+				return fmt.Errorf("insert smell %s: %w", sm.Kind, err)
+			}
+		}
+	}
+	return nil
+}
@@ -14,10 +14,18 @@ import (
 	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
 )
 
-type FileStorage struct{}
+const DefaultJSONIndent = "  "
+
+type FileStorage struct {
+	indent string
+}
 
 func NewFileStorage() *FileStorage {
-	return &FileStorage{}
+	return &FileStorage{indent: DefaultJSONIndent}
+}
+
+func NewFileStorageWithIndent(indent string) *FileStorage {
+	return &FileStorage{indent: indent}
 }
 
 var _ ports.ReportStorage = (*FileStorage)(nil)
@@ -38,7 +46,7 @@ func (s *FileStorage) Save(ctx context.Context, root string, report *model.Proje
 	defer f.Close()
 
 	enc := json.NewEncoder(f)
-	enc.SetIndent("", "  ")
+	enc.SetIndent("", s.indent)
 	if err := enc.Encode(report); err != nil {
 		return fmt.Errorf("encode report: %w", err)
 	}
@@ -48,7 +56,10 @@ func (s *FileStorage) Save(ctx context.Context, root string, report *model.Proje
 func (s *FileStorage) Load(ctx context.Context, root string) (*model.ProjectReport, error) {
 	_ = ctx
 
-	path := filepath.Join(root, ".codeaudit", "report.json")
+	return LoadReportFile(filepath.Join(root, ".codeaudit", "report.json"))
+}
+
+func LoadReportFile(path string) (*model.ProjectReport, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("open report: %w", err)
@@ -0,0 +1,12 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+//go:build !unix
+
+package infrastructure
+
+import "os"
+
+func terminalHeight(f *os.File) int {
+	return 0
+}
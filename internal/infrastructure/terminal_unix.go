@@ -0,0 +1,20 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package infrastructure
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func terminalHeight(f *os.File) int {
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0
+	}
+	return int(ws.Row)
+}
@@ -4,14 +4,18 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math"
+	"os"
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
 	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
@@ -20,6 +24,36 @@ import (
 type AnalyzeProjectRequest struct {
 	RootPath   string
 	IncludeExt []string
+	Strict     bool
+	AllowEmpty bool
+	Stats      bool
+
+	AuthorComplexity bool
+	CoChange         bool
+
+	Smells SmellConfig
+
+	Baseline     *model.ProjectReport
+	BaselinePath string
+	SeedReport   *model.ProjectReport
+
+	OnlyPaths []string
+	NoSave    bool
+
+	MaxLineLength       int
+	MaxFunctionsPerFile int
+
+	GeneratedAt time.Time
+	CI          *model.CIMetadata
+
+	HotspotFormula string
+	HotspotModel   string
+
+	HealthWeights HealthWeights
+
+	OmitIsolatedInstability bool
+
+	Tests TestFileConfig
 }
 
 type AnalyzeProjectUseCase struct {
@@ -50,6 +84,12 @@ func NewAnalyzeProjectUseCase(
 }
 
 func (uc *AnalyzeProjectUseCase) Execute(ctx context.Context, req AnalyzeProjectRequest) (*model.ProjectReport, error) {
+	started := time.Now()
+	var heap *heapSampler
+	if req.Stats {
+		heap = startHeapSampler(heapSampleInterval)
+		defer heap.Stop()
+	}
 	if req.RootPath == "" {
 		return nil, fmt.Errorf("root path is required")
 	}
@@ -60,17 +100,73 @@ func (uc *AnalyzeProjectUseCase) Execute(ctx context.Context, req AnalyzeProject
 		}
 	}
 
+	formulaSource, err := HotspotModelFormula(req.HotspotModel, req.HotspotFormula)
+	if err != nil {
+		return nil, err
+	}
+	hotspotFormula, err := ParseHotspotFormula(formulaSource)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateTestsMode(req.Tests.Mode); err != nil {
+		return nil, err
+	}
+	tests, err := newTestFileClassifier(req.Tests)
+	if err != nil {
+		return nil, err
+	}
+
+	root := reportRoot(req.RootPath)
+
+	var warnings []string
+	for _, ext := range req.IncludeExt {
+		if uc.selectParser("file"+ext, nil) == nil {
+			warnings = append(warnings, fmt.Sprintf("no enabled parser handles %s files", ext))
+		}
+	}
+
 	filesList, err := uc.scanner.Scan(ctx, req.RootPath, req.IncludeExt)
 	if err != nil {
 		return nil, fmt.Errorf("scan source files: %w", err)
 	}
+	if req.OnlyPaths != nil {
+		filesList = restrictToPaths(filesList, req.OnlyPaths)
+	}
+	filesList = tests.selectFiles(root, filesList, req.Tests.Mode)
 	if len(filesList) == 0 {
-		return nil, fmt.Errorf("no source files found under %s", req.RootPath)
+		if !req.AllowEmpty {
+			return nil, fmt.Errorf("no source files found under %s", req.RootPath)
+		}
+		report := buildProjectReport(root, []model.FileMetrics{}, warnings, reportOptions{GeneratedAt: req.GeneratedAt})
+		report.CI = req.CI
+		if req.NoSave {
+			return report, nil
+		}
+		if err := uc.storage.Save(ctx, root, report); err != nil {
+			return nil, fmt.Errorf("save report: %w", err)
+		}
+		return report, nil
 	}
 
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	jobs := make(chan string)
 	results := make(chan *model.FileMetrics)
 	errCh := make(chan error, len(filesList))
+	skippedCh := make(chan model.SkippedFile, len(filesList))
+
+	var strictErr error
+	var strictOnce sync.Once
+	fail := func(err error) {
+		errCh <- err
+		if req.Strict {
+			strictOnce.Do(func() {
+				strictErr = err
+				cancel()
+			})
+		}
+	}
 
 	var wg sync.WaitGroup
 	for i := 0; i < uc.workers; i++ {
@@ -79,28 +175,48 @@ func (uc *AnalyzeProjectUseCase) Execute(ctx context.Context, req AnalyzeProject
 			defer wg.Done()
 			for path := range jobs {
 				select {
-				case <-ctx.Done():
+				case <-workCtx.Done():
 					return
 				default:
 				}
 
 				src, err := uc.reader.ReadFile(path)
 				if err != nil {
-					errCh <- fmt.Errorf("read %s: %w", path, err)
+					fail(fmt.Errorf("read %s: %w", path, err))
 					continue
 				}
 
-				parser := uc.selectParser(path)
+				if reason := detectNonText(src); reason != "" {
+					errCh <- fmt.Errorf("skip %s: %s", path, reason)
+					continue
+				}
+				if reason := detectMinified(src, req.MaxLineLength); reason != "" {
+					skippedCh <- model.SkippedFile{Path: filepath.ToSlash(path), Reason: reason}
+					continue
+				}
+
+				parser := uc.selectParser(path, src)
 				if parser == nil {
 					continue
 				}
 
 				fm, err := parser.ParseFile(path, src)
 				if err != nil {
-					errCh <- fmt.Errorf("parse %s: %w", path, err)
+					fail(fmt.Errorf("parse %s: %w", path, err))
 					continue
 				}
 
+				if req.Smells.MixedIndentation {
+					if smell := detectMixedIndentation(fm.Path, src); smell != nil {
+						fm.Smells = append(fm.Smells, *smell)
+					}
+				}
+				if req.Smells.MaxCommentDensity > 0 {
+					if smell := detectExcessiveComments(fm, src, req.Smells.MaxCommentDensity); smell != nil {
+						fm.Smells = append(fm.Smells, *smell)
+					}
+				}
+
 				results <- fm
 			}
 		}()
@@ -109,7 +225,11 @@ func (uc *AnalyzeProjectUseCase) Execute(ctx context.Context, req AnalyzeProject
 	go func() {
 		defer close(jobs)
 		for _, path := range filesList {
-			jobs <- path
+			select {
+			case jobs <- path:
+			case <-workCtx.Done():
+				return
+			}
 		}
 	}()
 
@@ -117,23 +237,40 @@ func (uc *AnalyzeProjectUseCase) Execute(ctx context.Context, req AnalyzeProject
 		wg.Wait()
 		close(results)
 		close(errCh)
+		close(skippedCh)
 	}()
 
 	var files []model.FileMetrics
 	for fm := range results {
 		if fm != nil {
+			fm.Test = tests.isTest(root, fm.Path)
 			files = append(files, *fm)
+			for _, w := range fm.Warnings {
+				warnings = append(warnings, fmt.Sprintf("%s: %s", fm.Path, w))
+			}
 		}
 	}
 
-	var warnings []string
+	if strictErr != nil {
+		return nil, strictErr
+	}
+
 	for e := range errCh {
 		if e != nil {
 			warnings = append(warnings, e.Error())
 		}
 	}
+	var skipped []model.SkippedFile
+	for sf := range skippedCh {
+		skipped = append(skipped, sf)
+	}
+	sort.Slice(skipped, func(i, j int) bool { return skipped[i].Path < skipped[j].Path })
 
-	gitMetrics, err := uc.git.CollectFileMetrics(ctx, req.RootPath)
+	collect := uc.git.CollectFileMetrics
+	if collector, ok := uc.git.(ports.AuthorMetricsCollector); ok && req.AuthorComplexity {
+		collect = collector.CollectFileMetricsWithAuthors
+	}
+	gitMetrics, err := collect(ctx, root)
 	if err != nil {
 		warnings = append(warnings, fmt.Sprintf("git metrics disabled: %v", err))
 	}
@@ -145,7 +282,7 @@ func (uc *AnalyzeProjectUseCase) Execute(ctx context.Context, req AnalyzeProject
 				files[i].Git = gm
 				continue
 			}
-			if rel, err := filepath.Rel(req.RootPath, p); err == nil {
+			if rel, err := filepath.Rel(root, p); err == nil {
 				if gm, ok := gitMetrics[rel]; ok {
 					files[i].Git = gm
 				}
@@ -153,24 +290,351 @@ func (uc *AnalyzeProjectUseCase) Execute(ctx context.Context, req AnalyzeProject
 		}
 	}
 
-	report := buildProjectReport(req.RootPath, files, warnings)
+	normalizeFilePaths(files)
+
+	report := buildProjectReport(root, files, warnings, reportOptions{
+		Smells:         req.Smells,
+		GeneratedAt:    req.GeneratedAt,
+		HotspotFormula: hotspotFormula,
+		HealthWeights:  req.HealthWeights,
+		SeedFiles:      seedCouplingFiles(root, files, req.SeedReport),
+		ModulePrefix:   goModulePrefix(uc.reader, root),
+
+		OmitIsolatedInstability: req.OmitIsolatedInstability,
+	})
+	report.CI = req.CI
+	report.SkippedFiles = skipped
+	if req.AuthorComplexity {
+		report.AuthorComplexity = buildAuthorComplexity(files)
+	}
+	if req.CoChange {
+		if collector, ok := uc.git.(ports.CoChangeCollector); ok {
+			commits, err := collector.CollectCommitFiles(ctx, root)
+			if err != nil {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("co-change analysis disabled: %v", err))
+			} else {
+				report.CoChanges = buildCoChanges(root, files, commits)
+			}
+		}
+	}
+	if req.Stats {
+		report.Stats = collectRunStats(started, len(files), heap.Stop())
+	}
+	if req.Baseline != nil {
+		report.Delta = buildReportDelta(req.Baseline, report)
+		report.Delta.BaselinePath = req.BaselinePath
+	}
+	capFunctionsPerFile(report.Files, req.MaxFunctionsPerFile)
 
-	if err := uc.storage.Save(ctx, req.RootPath, report); err != nil {
+	if req.NoSave {
+		return report, nil
+	}
+	persisted := *report
+	persisted.Delta = nil
+	if err := uc.storage.Save(ctx, root, &persisted); err != nil {
 		return nil, fmt.Errorf("save report: %w", err)
 	}
 	return report, nil
 }
 
-func (uc *AnalyzeProjectUseCase) selectParser(path string) ports.CodeParser {
-	for _, p := range uc.parsers {
-		if p.SupportsFile(path) {
-			return p
+func normalizeFilePaths(files []model.FileMetrics) {
+	for i := range files {
+		f := &files[i]
+		f.Path = filepath.ToSlash(f.Path)
+		for j := range f.Functions {
+			f.Functions[j].FilePath = filepath.ToSlash(f.Functions[j].FilePath)
+		}
+		for j := range f.Smells {
+			f.Smells[j].FilePath = filepath.ToSlash(f.Smells[j].FilePath)
+		}
+		if f.Git != nil {
+			f.Git.FilePath = filepath.ToSlash(f.Git.FilePath)
 		}
 	}
-	return nil
 }
 
-func buildProjectReport(root string, files []model.FileMetrics, warnings []string) *model.ProjectReport {
+func capFunctionsPerFile(files []model.FileMetrics, max int) {
+	if max <= 0 {
+		return
+	}
+	for i := range files {
+		f := &files[i]
+		if len(f.Functions) <= max {
+			continue
+		}
+		fns := append([]model.FunctionMetrics(nil), f.Functions...)
+		sort.SliceStable(fns, func(a, b int) bool { return fns[a].CCN > fns[b].CCN })
+
+		others := &model.FunctionAggregate{}
+		shapes := make(map[model.FunctionShape]int)
+		for _, fn := range fns[max:] {
+			others.Count++
+			others.NLOC += fn.NLOC
+			others.CCNTotal += fn.CCN
+			others.Parameters += fn.Parameters
+			if fn.CCN > others.CCNMax {
+				others.CCNMax = fn.CCN
+			}
+			if fn.Parameters >= 5 {
+				others.ParamsGe5++
+			}
+			shapes[model.FunctionShape{
+				NLOC:                fn.NLOC,
+				CCN:                 fn.CCN,
+				CognitiveComplexity: fn.CognitiveComplexity,
+				IsDataFunction:      fn.IsDataFunction,
+			}]++
+		}
+		for shape, n := range shapes {
+			shape.Count = n
+			others.Shapes = append(others.Shapes, shape)
+		}
+		sort.Slice(others.Shapes, func(a, b int) bool {
+			x, y := others.Shapes[a], others.Shapes[b]
+			switch {
+			case x.NLOC != y.NLOC:
+				return x.NLOC < y.NLOC
+			case x.CCN != y.CCN:
+				return x.CCN < y.CCN
+			case x.CognitiveComplexity != y.CognitiveComplexity:
+				return x.CognitiveComplexity < y.CognitiveComplexity
+			default:
+				return !x.IsDataFunction && y.IsDataFunction
+			}
+		})
+
+		kept := fns[:max:max]
+		sort.SliceStable(kept, func(a, b int) bool { return kept[a].StartLine < kept[b].StartLine })
+		f.Functions = kept
+		f.OtherFunctions = others
+	}
+}
+
+func restrictToPaths(files, only []string) []string {
+	allowed := make(map[string]struct{}, len(only))
+	for _, p := range only {
+		allowed[canonicalPath(p)] = struct{}{}
+	}
+	var kept []string
+	for _, f := range files {
+		if _, ok := allowed[canonicalPath(f)]; ok {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+func canonicalPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+func buildReportDelta(baseline, current *model.ProjectReport) *model.ReportDelta {
+	delta := func(before, after float64) model.MetricDelta {
+		return model.MetricDelta{Baseline: before, Current: after, Change: after - before}
+	}
+	totalSmells := func(report *model.ProjectReport) float64 {
+		n := 0
+		if report.Project.SmellCountsByKind == nil {
+			for _, f := range report.Files {
+				n += len(f.Smells)
+			}
+		}
+		for _, count := range report.Project.SmellCountsByKind {
+			n += count
+		}
+		return float64(n)
+	}
+
+	return &model.ReportDelta{
+		BaselineGeneratedAt: baseline.GeneratedAt,
+		Files:               delta(float64(baseline.Project.TotalFiles), float64(current.Project.TotalFiles)),
+		Functions:           delta(float64(baseline.Project.TotalFunctions), float64(current.Project.TotalFunctions)),
+		AvgCCNPerFunction:   delta(baseline.Project.AvgCCNPerFunction, current.Project.AvgCCNPerFunction),
+		MaxCCNPerFunction:   delta(float64(baseline.Project.MaxCCNPerFunction), float64(current.Project.MaxCCNPerFunction)),
+		Smells:              delta(totalSmells(baseline), totalSmells(current)),
+	}
+}
+
+func reportRoot(path string) string {
+	if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+		return filepath.Dir(path)
+	}
+	return path
+}
+
+const heapSampleInterval = 10 * time.Millisecond
+
+type heapSampler struct {
+	peak uint64
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+func startHeapSampler(interval time.Duration) *heapSampler {
+	s := &heapSampler{stop: make(chan struct{}), done: make(chan struct{})}
+	s.sample()
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.sample()
+			}
+		}
+	}()
+	return s
+}
+
+func (s *heapSampler) sample() {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	if mem.HeapInuse > s.peak {
+		s.peak = mem.HeapInuse
+	}
+}
+
+func (s *heapSampler) Stop() uint64 {
+	s.once.Do(func() {
+		close(s.stop)
+		<-s.done
+		s.sample()
+	})
+	return s.peak
+}
+
+func collectRunStats(started time.Time, files int, peakHeap uint64) *model.RunStats {
+	elapsed := time.Since(started)
+
+	stats := &model.RunStats{
+		WallTimeMillis: elapsed.Milliseconds(),
+		FilesAnalyzed:  files,
+		PeakHeapBytes:  peakHeap,
+	}
+	if secs := elapsed.Seconds(); secs > 0 {
+		stats.FilesPerSecond = float64(files) / secs
+	}
+	return stats
+}
+
+func (uc *AnalyzeProjectUseCase) selectParser(path string, src []byte) ports.CodeParser {
+	return selectParser(uc.parsers, path, src)
+}
+
+const textSniffLen = 1024
+
+func detectNonText(src []byte) string {
+	head := src
+	if len(head) > textSniffLen {
+		head = head[:textSniffLen]
+		i := len(head) - 1
+		for i > 0 && i > len(head)-utf8.UTFMax && !utf8.RuneStart(head[i]) {
+			i--
+		}
+		if !utf8.FullRune(head[i:]) {
+			head = head[:i]
+		}
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return "binary content (NUL byte)"
+	}
+	if !utf8.Valid(head) {
+		return "invalid UTF-8 content"
+	}
+	return ""
+}
+
+func detectMinified(src []byte, maxLineLength int) string {
+	if maxLineLength <= 0 || len(src) <= maxLineLength/3 {
+		return ""
+	}
+	var longest, longestLine, lines, total int
+	for i, line := range bytes.Split(src, []byte("\n")) {
+		n := len(bytes.TrimRight(line, "\r"))
+		if n == 0 {
+			continue
+		}
+		lines++
+		total += n
+		if n > longest {
+			longest, longestLine = n, i+1
+		}
+	}
+	if longest > maxLineLength {
+		return fmt.Sprintf("likely minified or generated (line %d is %d chars > %d)", longestLine, longest, maxLineLength)
+	}
+	if lines > 0 && total/lines > maxLineLength/3 {
+		return fmt.Sprintf("likely minified or generated (average line is %d chars)", total/lines)
+	}
+	return ""
+}
+
+type reportOptions struct {
+	Smells         SmellConfig
+	GeneratedAt    time.Time
+	HotspotFormula *HotspotFormula
+	HealthWeights  HealthWeights
+	SeedFiles      []model.FileMetrics
+	ModulePrefix   string
+
+	OmitIsolatedInstability bool
+}
+
+func buildProjectReport(root string, files []model.FileMetrics, warnings []string, opts reportOptions) *model.ProjectReport {
+	resolvePackageGlobals(files)
+	annotateFunctionCoupling(files, opts.SeedFiles)
+	detectFunctionSmells(files, opts.Smells)
+	annotateRemediations(files)
+
+	proj := computeProjectMetrics(files)
+
+	annotateFunctionHotspots(files)
+
+	if proj.TotalFunctions > 0 {
+		var totalNLOC int
+		for _, f := range files {
+			for _, fn := range f.Functions {
+				totalNLOC += fn.NLOC
+			}
+		}
+		annotateDeviationFromMean(files, proj.AvgCCNPerFunction, float64(totalNLOC)/float64(proj.TotalFunctions))
+	}
+
+	hotspots := buildHotspots(files, opts.HotspotFormula)
+	directories := buildDirectoryMetrics(root, files)
+	packages := buildPackageMetrics(root, opts.ModulePrefix, files, opts.OmitIsolatedInstability)
+
+	generatedAt := opts.GeneratedAt
+	if generatedAt.IsZero() {
+		generatedAt = time.Now()
+	}
+
+	return &model.ProjectReport{
+		RootPath:       root,
+		GeneratedAt:    generatedAt.UTC(),
+		Files:          files,
+		Project:        proj,
+		Health:         computeHealthGrade(files, opts.HealthWeights),
+		Hotspots:       hotspots,
+		Issues:         collectIssues(files),
+		Directories:    directories,
+		Packages:       packages,
+		MetricMetadata: model.AllMetricSummaries(),
+		Warnings:       warnings,
+	}
+}
+
+func computeProjectMetrics(files []model.FileMetrics) model.ProjectMetrics {
 	var proj model.ProjectMetrics
 
 	proj.TotalFiles = len(files)
@@ -187,12 +651,31 @@ func buildProjectReport(root string, files []model.FileMetrics, warnings []strin
 
 	var sumCommentDensity float64
 	var filesWithComments int
+	var totalLines, totalCommentLines int
+	var publicSymbols, publicDocumented int
 
 	var gitLinesAdded, gitLinesDeleted, gitCommits int
 
+	smellCounts := make(map[model.CodeSmellKind]int)
+
 	for _, f := range files {
+		if len(f.Functions) == 0 {
+			proj.DeclarationOnlyFiles++
+		}
 		proj.TotalFunctions += len(f.Functions)
 		totalFunctions += len(f.Functions)
+		if o := f.OtherFunctions; o != nil {
+			proj.TotalFunctions += o.Count
+			totalFunctions += o.Count
+			sumParams += float64(o.Parameters)
+			paramsGe5 += o.ParamsGe5
+			for _, shape := range o.Shapes {
+				for k := 0; k < shape.Count; k++ {
+					sizes = append(sizes, shape.NLOC)
+					fnGt50, fnGt80, fnGt100 = countLongFunction(shape.NLOC, fnGt50, fnGt80, fnGt100)
+				}
+			}
+		}
 		totalCCN += f.Summary.CCNTotal
 
 		if f.Summary.CCNMaxFunction > maxCCN {
@@ -204,8 +687,17 @@ func buildProjectReport(root string, files []model.FileMetrics, warnings []strin
 		if f.Comments.TotalLines > 0 {
 			sumCommentDensity += f.Comments.CommentDensity
 			filesWithComments++
+			totalLines += f.Comments.TotalLines
+			totalCommentLines += f.Comments.CommentLines
+		}
+
+		for _, sm := range f.Smells {
+			smellCounts[sm.Kind]++
 		}
 
+		publicSymbols += f.Comments.PublicSymbols
+		publicDocumented += f.Comments.PublicDocumented
+
 		if f.Git != nil {
 			gitLinesAdded += f.Git.LinesAdded
 			gitLinesDeleted += f.Git.LinesDeleted
@@ -215,15 +707,7 @@ func buildProjectReport(root string, files []model.FileMetrics, warnings []strin
 		for _, fn := range f.Functions {
 			sizes = append(sizes, fn.NLOC)
 			sumParams += float64(fn.Parameters)
-			if fn.NLOC > 50 {
-				fnGt50++
-			}
-			if fn.NLOC > 80 {
-				fnGt80++
-			}
-			if fn.NLOC > 100 {
-				fnGt100++
-			}
+			fnGt50, fnGt80, fnGt100 = countLongFunction(fn.NLOC, fnGt50, fnGt80, fnGt100)
 			if fn.Parameters >= 5 {
 				paramsGe5++
 			}
@@ -245,11 +729,24 @@ func buildProjectReport(root string, files []model.FileMetrics, warnings []strin
 	if filesWithComments > 0 {
 		proj.CommentDensityAvg = sumCommentDensity / float64(filesWithComments)
 	}
+	if totalLines > 0 {
+		proj.CommentDensityWeighted = float64(totalCommentLines) / float64(totalLines)
+	}
+
+	proj.PublicAPISymbols = publicSymbols
+	proj.PublicAPIDocumented = publicDocumented
+	if publicSymbols > 0 {
+		proj.PublicAPIDocPct = float64(publicDocumented) / float64(publicSymbols)
+	}
 
 	proj.GitTotalLinesAdded = gitLinesAdded
 	proj.GitTotalLinesDeleted = gitLinesDeleted
 	proj.GitTotalCommits = gitCommits
 
+	if len(smellCounts) > 0 {
+		proj.SmellCountsByKind = smellCounts
+	}
+
 	if len(sizes) > 0 {
 		sort.Ints(sizes)
 		mid := len(sizes) / 2
@@ -268,37 +765,52 @@ func buildProjectReport(root string, files []model.FileMetrics, warnings []strin
 		proj.P95FunctionSize = float64(sizes[idxP95])
 	}
 
-	annotateFunctionCoupling(files)
-	annotateFunctionHotspots(files)
-
-	hotspots := buildHotspots(files)
+	return proj
+}
 
-	return &model.ProjectReport{
-		RootPath:       root,
-		GeneratedAt:    time.Now().UTC(),
-		Files:          files,
-		Project:        proj,
-		Hotspots:       hotspots,
-		MetricMetadata: model.AllMetricSummaries(),
-		Warnings:       warnings,
+func countLongFunction(nloc, gt50, gt80, gt100 int) (int, int, int) {
+	if nloc > 50 {
+		gt50++
+	}
+	if nloc > 80 {
+		gt80++
 	}
+	if nloc > 100 {
+		gt100++
+	}
+	return gt50, gt80, gt100
 }
 
-func buildHotspots(files []model.FileMetrics) []model.Hotspot {
+func buildHotspots(files []model.FileMetrics, formula *HotspotFormula) []model.Hotspot {
 	var hs []model.Hotspot
 
+	reason := "complexity × churn"
+	if formula == nil {
+		formula, _ = ParseHotspotFormula(DefaultHotspotFormula)
+	} else if formula.String() != DefaultHotspotFormula {
+		reason = formula.String()
+	}
+
 	for _, f := range files {
-		if f.Summary.CCNTotal == 0 || f.Git == nil {
+		if f.Git == nil {
 			continue
 		}
 		churn := f.Git.LinesAdded + f.Git.LinesDeleted
-		if churn == 0 {
+		score := formula.Eval(map[string]float64{
+			"ccn":      float64(f.Summary.CCNTotal),
+			"churn":    float64(churn),
+			"commits":  float64(f.Git.Commits),
+			"authors":  float64(f.Git.Authors),
+			"bugfixes": float64(f.Git.BugfixCommits),
+			"smells":   float64(len(f.Smells)),
+			"nloc":     float64(f.Summary.NLOC),
+		})
+		if !(score > 0) || math.IsInf(score, 0) {
 			continue
 		}
-		score := float64(f.Summary.CCNTotal) * math.Log1p(float64(churn))
 		hs = append(hs, model.Hotspot{
 			FilePath: f.Path,
-			Reason:   "complexity × churn",
+			Reason:   reason,
 			Score:    score,
 			CCN:      f.Summary.CCNTotal,
 			Churn:    churn,
@@ -315,16 +827,99 @@ func buildHotspots(files []model.FileMetrics) []model.Hotspot {
 	return hs
 }
 
-func annotateFunctionCoupling(files []model.FileMetrics) {
+func buildDirectoryMetrics(root string, files []model.FileMetrics) []model.DirectoryMetrics {
+	type dirAgg struct {
+		metrics model.DirectoryMetrics
+		nloc    int
+	}
+
+	byDir := make(map[string]*dirAgg)
+	for _, f := range files {
+		dir := filepath.Dir(f.Path)
+		if rel, err := filepath.Rel(root, dir); err == nil {
+			dir = rel
+		}
+		dir = filepath.ToSlash(dir)
+
+		a := byDir[dir]
+		if a == nil {
+			a = &dirAgg{metrics: model.DirectoryMetrics{Path: dir}}
+			byDir[dir] = a
+		}
+		a.metrics.Files++
+		a.metrics.Functions += len(f.Functions)
+		a.metrics.CCNTotal += f.Summary.CCNTotal
+		a.metrics.Smells += len(f.Smells)
+		for _, fn := range f.Functions {
+			a.nloc += fn.NLOC
+		}
+	}
+
+	out := make([]model.DirectoryMetrics, 0, len(byDir))
+	for _, a := range byDir {
+		if a.metrics.Functions > 0 {
+			a.metrics.AvgFunctionNLOC = float64(a.nloc) / float64(a.metrics.Functions)
+		}
+		out = append(out, a.metrics)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CCNTotal == out[j].CCNTotal {
+			return out[i].Path < out[j].Path
+		}
+		return out[i].CCNTotal > out[j].CCNTotal
+	})
+	return out
+}
+
+func buildAuthorComplexity(files []model.FileMetrics) []model.AuthorComplexity {
+	byAuthor := make(map[string]*model.AuthorComplexity)
+	for _, f := range files {
+		if f.Git == nil || f.Git.DominantAuthor == "" {
+			continue
+		}
+		a := byAuthor[f.Git.DominantAuthor]
+		if a == nil {
+			a = &model.AuthorComplexity{Author: f.Git.DominantAuthor}
+			byAuthor[f.Git.DominantAuthor] = a
+		}
+		a.Files++
+		a.Functions += len(f.Functions)
+		a.CCNTotal += f.Summary.CCNTotal
+	}
+
+	out := make([]model.AuthorComplexity, 0, len(byAuthor))
+	for _, a := range byAuthor {
+		out = append(out, *a)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CCNTotal == out[j].CCNTotal {
+			return out[i].Author < out[j].Author
+		}
+		return out[i].CCNTotal > out[j].CCNTotal
+	})
+	return out
+}
+
+func annotateFunctionCoupling(files []model.FileMetrics, seed []model.FileMetrics) {
 	type funcRef struct {
 		fileIdx int
 		fnIdx   int
 	}
 
+	fileAt := func(i int) *model.FileMetrics {
+		if i < len(files) {
+			return &files[i]
+		}
+		return &seed[i-len(files)]
+	}
+	total := len(files) + len(seed)
+
 	byName := make(map[string][]funcRef)
-	for i := range files {
-		for j := range files[i].Functions {
-			name := files[i].Functions[j].Name
+	for i := 0; i < total; i++ {
+		f := fileAt(i)
+		for j := range f.Functions {
+			name := f.Functions[j].Name
 			if name == "" {
 				continue
 			}
@@ -332,15 +927,82 @@ func annotateFunctionCoupling(files []model.FileMetrics) {
 		}
 	}
 
+	for i := len(files); i < total; i++ {
+		for _, fn := range fileAt(i).Functions {
+			for _, cname := range fn.Callees {
+				for _, ref := range byName[cname] {
+					if ref.fileIdx < len(files) {
+						files[ref.fileIdx].Functions[ref.fnIdx].FanIn++
+					}
+				}
+			}
+		}
+	}
+
 	for i := range files {
 		for j := range files[i].Functions {
-			callees := files[i].Functions[j].Callees
-			for _, cname := range callees {
+			fn := &files[i].Functions[j]
+			calleeFiles := make(map[int]struct{})
+			for _, cname := range fn.Callees {
 				refs := byName[cname]
+				if len(refs) == 0 {
+					fn.ExternalFanOut++
+					continue
+				}
+				fn.InternalFanOut++
 				for _, ref := range refs {
+					if ref.fileIdx >= len(files) {
+						calleeFiles[ref.fileIdx] = struct{}{}
+						continue
+					}
 					files[ref.fileIdx].Functions[ref.fnIdx].FanIn++
+					if ref.fileIdx != i {
+						calleeFiles[ref.fileIdx] = struct{}{}
+					}
 				}
 			}
+			fn.FanOutFiles = len(calleeFiles)
+		}
+	}
+}
+
+func seedCouplingFiles(root string, files []model.FileMetrics, seed *model.ProjectReport) []model.FileMetrics {
+	if seed == nil {
+		return nil
+	}
+
+	analyzed := make(map[string]struct{}, len(files))
+	for _, f := range files {
+		analyzed[reportRelPath(root, f.Path)] = struct{}{}
+	}
+
+	var out []model.FileMetrics
+	for _, f := range seed.Files {
+		if _, ok := analyzed[reportRelPath(seed.RootPath, f.Path)]; ok {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func reportRelPath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		path = rel
+	}
+	return filepath.ToSlash(path)
+}
+
+func annotateDeviationFromMean(files []model.FileMetrics, meanCCN, meanNLOC float64) {
+	for i := range files {
+		for j := range files[i].Functions {
+			fn := &files[i].Functions[j]
+			if meanCCN > 0 {
+				fn.CCNVsMean = float64(fn.CCN) / meanCCN
+			}
+			if meanNLOC > 0 {
+				fn.NLOCVsMean = float64(fn.NLOC) / meanNLOC
+			}
 		}
 	}
 }
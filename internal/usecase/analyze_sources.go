@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package usecase
+
+import (
+	"context"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+)
+
+func AnalyzeSources(ctx context.Context, sources map[string][]byte, parsers []ports.CodeParser, req AnalyzeProjectRequest) (*model.ProjectReport, error) {
+	if req.RootPath == "" {
+		req.RootPath = "."
+	}
+	req.NoSave = true
+
+	mem := &memorySource{files: sources}
+	return NewAnalyzeProjectUseCase(mem, mem, parsers, noGit{}, discardStorage{}, 0).Execute(ctx, req)
+}
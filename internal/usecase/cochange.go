@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package usecase
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+const (
+	maxCoChangeCommitFiles = 50
+	minCoChangeCommits     = 2
+	coChangeLimit          = 20
+)
+
+func buildCoChanges(root string, files []model.FileMetrics, commits [][]string) []model.CoChange {
+	known := make(map[string]struct{}, len(files))
+	for _, f := range files {
+		p := f.Path
+		if rel, err := filepath.Rel(root, p); err == nil {
+			p = rel
+		}
+		known[filepath.ToSlash(p)] = struct{}{}
+	}
+
+	type pair struct{ a, b string }
+	perFile := make(map[string]int)
+	shared := make(map[pair]int)
+	for _, commit := range commits {
+		seen := make(map[string]struct{}, len(commit))
+		var touched []string
+		for _, p := range commit {
+			if _, ok := known[p]; !ok {
+				continue
+			}
+			if _, dup := seen[p]; dup {
+				continue
+			}
+			seen[p] = struct{}{}
+			touched = append(touched, p)
+		}
+		if len(touched) > maxCoChangeCommitFiles {
+			continue
+		}
+		sort.Strings(touched)
+		for i, a := range touched {
+			perFile[a]++
+			for _, b := range touched[i+1:] {
+				shared[pair{a, b}]++
+			}
+		}
+	}
+
+	var out []model.CoChange
+	for p, n := range shared {
+		if n < minCoChangeCommits {
+			continue
+		}
+		out = append(out, model.CoChange{
+			FileA:   p.a,
+			FileB:   p.b,
+			Commits: n,
+			Degree:  float64(n) / float64(perFile[p.a]+perFile[p.b]-n),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Commits != out[j].Commits {
+			return out[i].Commits > out[j].Commits
+		}
+		if out[i].Degree != out[j].Degree {
+			return out[i].Degree > out[j].Degree
+		}
+		if out[i].FileA != out[j].FileA {
+			return out[i].FileA < out[j].FileA
+		}
+		return out[i].FileB < out[j].FileB
+	})
+	if len(out) > coChangeLimit {
+		out = out[:coChangeLimit]
+	}
+	return out
+}
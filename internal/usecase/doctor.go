@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+)
+
+var doctorSample = map[string]string{
+	"sample/main.go": "package main\n\nfunc main() {\n\tif len(\"x\") > 0 {\n\t\thelper()\n\t}\n}\n\nfunc helper() {}\n",
+	"sample/util.c":  "int twice(int x) {\n\treturn x * 2;\n}\n",
+}
+
+type DoctorParser struct {
+	Name       string
+	Extensions []string
+}
+
+type DoctorReport struct {
+	GoVersion string
+	GitPath   string
+	GitError  string
+	Parsers   []DoctorParser
+	Renderers []string
+
+	SampleFiles     int
+	SampleFunctions int
+	SampleError     string
+}
+
+func (r *DoctorReport) Healthy() bool {
+	return r.SampleError == "" && r.SampleFunctions > 0
+}
+
+type DoctorUseCase struct {
+	parsers  []ports.CodeParser
+	registry ports.RendererRegistry
+	lookPath func(file string) (string, error)
+}
+
+func NewDoctorUseCase(parsers []ports.CodeParser, registry ports.RendererRegistry, lookPath func(file string) (string, error)) *DoctorUseCase {
+	return &DoctorUseCase{
+		parsers:  parsers,
+		registry: registry,
+		lookPath: lookPath,
+	}
+}
+
+func (uc *DoctorUseCase) Execute(ctx context.Context) *DoctorReport {
+	report := &DoctorReport{GoVersion: runtime.Version()}
+
+	if path, err := uc.lookPath("git"); err != nil {
+		report.GitError = err.Error()
+	} else {
+		report.GitPath = path
+	}
+
+	for _, p := range uc.parsers {
+		report.Parsers = append(report.Parsers, DoctorParser{
+			Name:       p.Name(),
+			Extensions: append([]string(nil), p.Extensions()...),
+		})
+	}
+
+	if uc.registry != nil {
+		for _, r := range uc.registry.List() {
+			report.Renderers = append(report.Renderers, r.Format())
+		}
+		sort.Strings(report.Renderers)
+	}
+
+	sample, err := uc.analyzeSample(ctx)
+	if err != nil {
+		report.SampleError = err.Error()
+	} else {
+		report.SampleFiles = sample.Project.TotalFiles
+		report.SampleFunctions = sample.Project.TotalFunctions
+	}
+	return report
+}
+
+func (uc *DoctorUseCase) analyzeSample(ctx context.Context) (*model.ProjectReport, error) {
+	sources := make(map[string][]byte, len(doctorSample))
+	for path, src := range doctorSample {
+		sources[path] = []byte(src)
+	}
+	report, err := AnalyzeSources(ctx, sources, uc.parsers, AnalyzeProjectRequest{RootPath: "sample", Strict: true})
+	if err != nil {
+		return nil, fmt.Errorf("sample analysis: %w", err)
+	}
+	return report, nil
+}
+
+type memorySource struct {
+	files map[string][]byte
+}
+
+func (m *memorySource) Scan(ctx context.Context, root string, includeExt []string) ([]string, error) {
+	allowed := make(map[string]struct{}, len(includeExt))
+	for _, e := range includeExt {
+		allowed[strings.ToLower(e)] = struct{}{}
+	}
+
+	paths := make([]string, 0, len(m.files))
+	for path := range m.files {
+		if len(allowed) > 0 {
+			if _, ok := allowed[strings.ToLower(filepath.Ext(path))]; !ok {
+				continue
+			}
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func (m *memorySource) ReadFile(path string) ([]byte, error) {
+	src, ok := m.files[path]
+	if !ok {
+		return nil, fmt.Errorf("no in-memory file %s", path)
+	}
+	return src, nil
+}
+
+type noGit struct{}
+
+func (noGit) CollectFileMetrics(ctx context.Context, root string) (map[string]*model.GitFileMetrics, error) {
+	return nil, nil
+}
+
+type discardStorage struct{}
+
+func (discardStorage) Save(ctx context.Context, root string, report *model.ProjectReport) error {
+	return nil
+}
+
+func (discardStorage) Load(ctx context.Context, root string) (*model.ProjectReport, error) {
+	return nil, fmt.Errorf("doctor does not persist reports")
+}
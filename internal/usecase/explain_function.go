@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+)
+
+type ExplainFunctionRequest struct {
+	Path     string
+	Function string
+}
+
+type ExplainFunctionUseCase struct {
+	reader  ports.FileReader
+	parsers []ports.CodeParser
+}
+
+func NewExplainFunctionUseCase(reader ports.FileReader, parsers []ports.CodeParser) *ExplainFunctionUseCase {
+	return &ExplainFunctionUseCase{reader: reader, parsers: parsers}
+}
+
+func ParseExplainTarget(spec string) (ExplainFunctionRequest, error) {
+	i := strings.LastIndex(spec, ":")
+	for i > 0 && spec[i-1] == ':' {
+		i = strings.LastIndex(spec[:i-1], ":")
+	}
+	if i <= 0 || i == len(spec)-1 {
+		return ExplainFunctionRequest{}, fmt.Errorf("invalid --explain %q: want <file>:<function>", spec)
+	}
+	return ExplainFunctionRequest{Path: spec[:i], Function: spec[i+1:]}, nil
+}
+
+func (uc *ExplainFunctionUseCase) Execute(ctx context.Context, req ExplainFunctionRequest) (*model.FunctionExplanation, error) {
+	_ = ctx
+	src, err := uc.reader.ReadFile(req.Path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", req.Path, err)
+	}
+	p := selectParser(uc.parsers, req.Path, src)
+	if p == nil {
+		return nil, fmt.Errorf("no parser supports %s", req.Path)
+	}
+	explainer, ok := p.(ports.FunctionExplainer)
+	if !ok {
+		return nil, fmt.Errorf("parser %s cannot explain functions", p.Name())
+	}
+	return explainer.ExplainFunction(req.Path, src, req.Function)
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+type GateRule struct {
+	Metric string
+	Op     string
+	Limit  float64
+}
+
+type gateMetric func(p *model.ProjectMetrics) (float64, bool)
+
+var gateMetrics = map[string]gateMetric{
+	"public-doc-coverage": func(p *model.ProjectMetrics) (float64, bool) {
+		return p.PublicAPIDocPct * 100, p.PublicAPISymbols > 0
+	},
+	"comment-density": func(p *model.ProjectMetrics) (float64, bool) {
+		return p.CommentDensityWeighted * 100, true
+	},
+	"avg-ccn": func(p *model.ProjectMetrics) (float64, bool) {
+		return p.AvgCCNPerFunction, p.TotalFunctions > 0
+	},
+	"max-ccn": func(p *model.ProjectMetrics) (float64, bool) {
+		return float64(p.MaxCCNPerFunction), p.TotalFunctions > 0
+	},
+}
+
+var gateOps = []string{">=", "<=", ">", "<"}
+
+func ParseGateRule(spec string) (GateRule, error) {
+	for _, op := range gateOps {
+		name, value, ok := strings.Cut(spec, op)
+		if !ok {
+			continue
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, known := gateMetrics[name]; !known {
+			return GateRule{}, fmt.Errorf("invalid gate %q: unknown metric %q (want one of %s)", spec, name, strings.Join(GateMetricNames(), ", "))
+		}
+		limit, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return GateRule{}, fmt.Errorf("invalid gate %q: %w", spec, err)
+		}
+		return GateRule{Metric: name, Op: op, Limit: limit}, nil
+	}
+	return GateRule{}, fmt.Errorf("invalid gate %q: want metric>=value (or <=, >, <)", spec)
+}
+
+func GateMetricNames() []string {
+	names := make([]string, 0, len(gateMetrics))
+	for name := range gateMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r GateRule) String() string {
+	return fmt.Sprintf("%s%s%g", r.Metric, r.Op, r.Limit)
+}
+
+func (r GateRule) holds(value float64) bool {
+	switch r.Op {
+	case ">=":
+		return value >= r.Limit
+	case "<=":
+		return value <= r.Limit
+	case ">":
+		return value > r.Limit
+	default:
+		return value < r.Limit
+	}
+}
+
+func EvaluateGates(report *model.ProjectReport, rules []GateRule) []string {
+	var failing []string
+	for _, rule := range rules {
+		value, ok := gateMetrics[rule.Metric](&report.Project)
+		if !ok || rule.holds(value) {
+			continue
+		}
+		failing = append(failing, fmt.Sprintf("%s (got %.2f)", rule, value))
+	}
+	return failing
+}
@@ -6,14 +6,33 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
 	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
 )
 
 type GenerateReportRequest struct {
 	RootPath string
 	Format   string
+	Filter   string
+}
+
+type WorstFilesRequest struct {
+	RootPath string
+	Format   string
+	Count    int
+	FailCCN  int
+	Filter   string
+}
+
+type WorstFilesResult struct {
+	Output  string
+	Files   []model.FileMetrics
+	Failing []string
 }
 
 type GenerateReportUseCase struct {
@@ -29,20 +48,182 @@ func NewGenerateReportUseCase(storage ports.ReportStorage, registry ports.Render
 }
 
 func (uc *GenerateReportUseCase) Execute(ctx context.Context, req GenerateReportRequest) (string, error) {
-	report, err := uc.storage.Load(ctx, req.RootPath)
+	renderer, err := uc.renderer(req.Format)
 	if err != nil {
 		return "", err
 	}
 
-	format := strings.ToLower(req.Format)
+	report, err := uc.Load(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return renderer.Render(report)
+}
+
+func (uc *GenerateReportUseCase) Load(ctx context.Context, req GenerateReportRequest) (*model.ProjectReport, error) {
+	report, err := uc.storage.Load(ctx, reportRoot(req.RootPath))
+	if err != nil {
+		return nil, err
+	}
+	if req.Filter != "" {
+		return filterReport(report, req.Filter)
+	}
+	return report, nil
+}
+
+func (uc *GenerateReportUseCase) ExecuteWorst(ctx context.Context, req WorstFilesRequest) (*WorstFilesResult, error) {
+	if req.Count <= 0 {
+		return nil, fmt.Errorf("worst file count must be positive, got %d", req.Count)
+	}
+
+	report, err := uc.storage.Load(ctx, reportRoot(req.RootPath))
+	if err != nil {
+		return nil, err
+	}
+
+	renderer, err := uc.renderer(req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Filter != "" {
+		report, err = filterReport(report, req.Filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	worst := rankFilesByRisk(report.Files)
+	if len(worst) > req.Count {
+		worst = worst[:req.Count]
+	}
+
+	keep := make(map[string]struct{}, len(worst))
+	var failing []string
+	for _, f := range worst {
+		keep[f.Path] = struct{}{}
+		if req.FailCCN > 0 && f.Summary.CCNMaxFunction > req.FailCCN {
+			failing = append(failing, f.Path)
+		}
+	}
+
+	subset := *report
+	subset.Files = worst
+	subset.Hotspots = nil
+	for _, h := range report.Hotspots {
+		if _, ok := keep[h.FilePath]; ok {
+			subset.Hotspots = append(subset.Hotspots, h)
+		}
+	}
+
+	out, err := renderer.Render(&subset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WorstFilesResult{
+		Output:  out,
+		Files:   worst,
+		Failing: failing,
+	}, nil
+}
+
+func (uc *GenerateReportUseCase) renderer(format string) (ports.OutputRenderer, error) {
+	format = strings.ToLower(format)
 	if format == "" {
 		format = "text"
 	}
 
 	renderer, ok := uc.registry.Get(format)
 	if !ok {
-		return "", fmt.Errorf("unknown format %q", format)
+		return nil, fmt.Errorf("unknown format %q", format)
 	}
+	return renderer, nil
+}
 
-	return renderer.Render(report)
+func filterReport(report *model.ProjectReport, glob string) (*model.ProjectReport, error) {
+	if _, err := path.Match(glob, ""); err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", glob, err)
+	}
+
+	var files []model.FileMetrics
+	keep := make(map[string]struct{})
+	for _, f := range report.Files {
+		rel := f.Path
+		if r, err := filepath.Rel(report.RootPath, f.Path); err == nil {
+			rel = r
+		}
+		if matchFilter(glob, filepath.ToSlash(rel)) {
+			files = append(files, f)
+			keep[f.Path] = struct{}{}
+		}
+	}
+
+	subset := *report
+	subset.Files = files
+	subset.Project = computeProjectMetrics(files)
+	subset.Directories = buildDirectoryMetrics(report.RootPath, files)
+	if h := report.Health; h != nil {
+		subset.Health = computeHealthGrade(files, HealthWeights{Cyclomatic: h.CyclomaticWeight, Cognitive: h.CognitiveWeight})
+	}
+	if report.Issues != nil {
+		subset.Issues = collectIssues(files)
+	}
+	subset.Filter = &model.ReportFilter{
+		Glob:       glob,
+		Matched:    len(files),
+		TotalFiles: len(report.Files),
+	}
+
+	subset.Hotspots = nil
+	for _, h := range report.Hotspots {
+		if _, ok := keep[h.FilePath]; ok {
+			subset.Hotspots = append(subset.Hotspots, h)
+		}
+	}
+
+	subset.Packages = nil
+	for _, p := range report.Packages {
+		for _, d := range subset.Directories {
+			if d.Path == p.Path {
+				subset.Packages = append(subset.Packages, p)
+				break
+			}
+		}
+	}
+
+	if report.AuthorComplexity != nil {
+		subset.AuthorComplexity = buildAuthorComplexity(files)
+	}
+
+	subset.CoChanges = nil
+	for _, c := range report.CoChanges {
+		if matchFilter(glob, c.FileA) || matchFilter(glob, c.FileB) {
+			subset.CoChanges = append(subset.CoChanges, c)
+		}
+	}
+	return &subset, nil
+}
+
+func matchFilter(glob, rel string) bool {
+	for p := rel; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+		if ok, _ := path.Match(glob, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func rankFilesByRisk(files []model.FileMetrics) []model.FileMetrics {
+	ranked := append([]model.FileMetrics(nil), files...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Summary.CCNTotal != ranked[j].Summary.CCNTotal {
+			return ranked[i].Summary.CCNTotal > ranked[j].Summary.CCNTotal
+		}
+		if ranked[i].Summary.CCNMaxFunction != ranked[j].Summary.CCNMaxFunction {
+			return ranked[i].Summary.CCNMaxFunction > ranked[j].Summary.CCNMaxFunction
+		}
+		return ranked[i].Path < ranked[j].Path
+	})
+	return ranked
 }
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package usecase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+const (
+	DefaultHealthCyclomaticWeight = 0.5
+	DefaultHealthCognitiveWeight  = 0.5
+)
+
+const (
+	healthComplexityShare = 0.7
+	healthSizeShare       = 0.3
+
+	healthComplexityGood = 5
+	healthComplexityBad  = 25
+	healthSizeGood       = 30
+	healthSizeBad        = 120
+)
+
+type HealthWeights struct {
+	Cyclomatic float64
+	Cognitive  float64
+}
+
+func DefaultHealthWeights() HealthWeights {
+	return HealthWeights{
+		Cyclomatic: DefaultHealthCyclomaticWeight,
+		Cognitive:  DefaultHealthCognitiveWeight,
+	}
+}
+
+func ParseHealthWeights(s string) (HealthWeights, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultHealthWeights(), nil
+	}
+
+	var w HealthWeights
+	for _, part := range strings.Split(s, ",") {
+		key, raw, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return HealthWeights{}, fmt.Errorf("invalid health weight %q (want name=value)", part)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || v < 0 {
+			return HealthWeights{}, fmt.Errorf("invalid health weight %q: want a non-negative number", part)
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "cyclomatic", "ccn":
+			w.Cyclomatic = v
+		case "cognitive":
+			w.Cognitive = v
+		default:
+			return HealthWeights{}, fmt.Errorf("unknown health weight %q (want cyclomatic or cognitive)", key)
+		}
+	}
+	if w.Cyclomatic+w.Cognitive == 0 {
+		return HealthWeights{}, fmt.Errorf("health weights %q must not all be zero", s)
+	}
+	return w, nil
+}
+
+func (w HealthWeights) normalized() HealthWeights {
+	sum := w.Cyclomatic + w.Cognitive
+	if sum <= 0 {
+		return DefaultHealthWeights()
+	}
+	return HealthWeights{Cyclomatic: w.Cyclomatic / sum, Cognitive: w.Cognitive / sum}
+}
+
+func computeHealthGrade(files []model.FileMetrics, weights HealthWeights) *model.HealthGrade {
+	w := weights.normalized()
+
+	var functions int
+	var complexity, size float64
+	for _, f := range files {
+		for _, fn := range f.Functions {
+			functions++
+			cyclomatic, cognitive, fnSize := healthContributions(fn)
+			complexity += w.Cyclomatic*cyclomatic + w.Cognitive*cognitive
+			size += fnSize
+		}
+		if o := f.OtherFunctions; o != nil {
+			for _, shape := range o.Shapes {
+				functions += shape.Count
+				cyclomatic, cognitive, fnSize := healthContributions(model.FunctionMetrics{
+					NLOC:                shape.NLOC,
+					CCN:                 shape.CCN,
+					CognitiveComplexity: shape.CognitiveComplexity,
+					IsDataFunction:      shape.IsDataFunction,
+				})
+				n := float64(shape.Count)
+				complexity += n * (w.Cyclomatic*cyclomatic + w.Cognitive*cognitive)
+				size += n * fnSize
+			}
+		}
+	}
+	if functions == 0 {
+		return nil
+	}
+
+	complexity = complexity / float64(functions) * 100
+	size = size / float64(functions) * 100
+	score := healthComplexityShare*complexity + healthSizeShare*size
+
+	return &model.HealthGrade{
+		Grade:            healthLetter(score),
+		Score:            score,
+		Complexity:       complexity,
+		Size:             size,
+		CyclomaticWeight: w.Cyclomatic,
+		CognitiveWeight:  w.Cognitive,
+	}
+}
+
+func healthContributions(fn model.FunctionMetrics) (cyclomatic, cognitive, size float64) {
+	cyclomatic = healthRamp(fn.CCN, healthComplexityGood, healthComplexityBad)
+	cognitive = healthRamp(fn.CognitiveComplexity, healthComplexityGood, healthComplexityBad)
+	size = 1
+	if !fn.IsDataFunction {
+		size = healthRamp(fn.NLOC, healthSizeGood, healthSizeBad)
+	}
+	return cyclomatic, cognitive, size
+}
+
+func healthRamp(v, good, bad int) float64 {
+	switch {
+	case v <= good:
+		return 1
+	case v >= bad:
+		return 0
+	default:
+		return float64(bad-v) / float64(bad-good)
+	}
+}
+
+func healthLetter(score float64) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	default:
+		return "F"
+	}
+}
@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package usecase
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultHotspotFormula        = "ccn * log1p(churn)"
+	AuthorWeightedHotspotFormula = "ccn * log1p(churn) * (1 + log1p(authors))"
+)
+
+const (
+	HotspotModelComplexityChurn = "complexity-churn"
+	HotspotModelAuthors         = "authors"
+)
+
+var hotspotModels = map[string]string{
+	HotspotModelComplexityChurn: DefaultHotspotFormula,
+	HotspotModelAuthors:         AuthorWeightedHotspotFormula,
+}
+
+func HotspotModelFormula(name, formula string) (string, error) {
+	if name == "" {
+		return formula, nil
+	}
+	preset, ok := hotspotModels[name]
+	if !ok {
+		return "", fmt.Errorf("unknown hotspot model %q (want %s or %s)", name, HotspotModelComplexityChurn, HotspotModelAuthors)
+	}
+	if strings.TrimSpace(formula) != "" && formula != preset {
+		return "", fmt.Errorf("hotspot model %q and a custom hotspot formula are mutually exclusive", name)
+	}
+	return preset, nil
+}
+
+var hotspotFormulaVars = []string{"authors", "bugfixes", "ccn", "churn", "commits", "nloc", "smells"}
+
+var hotspotFormulaFuncs = map[string]struct {
+	arity int
+	fn    func(args []float64) float64
+}{
+	"log1p": {1, func(a []float64) float64 { return math.Log1p(a[0]) }},
+	"log":   {1, func(a []float64) float64 { return math.Log(a[0]) }},
+	"sqrt":  {1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"abs":   {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"min":   {2, func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"max":   {2, func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+	"pow":   {2, func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+}
+
+type HotspotFormula struct {
+	source string
+	expr   ast.Expr
+}
+
+func ParseHotspotFormula(source string) (*HotspotFormula, error) {
+	if strings.TrimSpace(source) == "" {
+		source = DefaultHotspotFormula
+	}
+	expr, err := parser.ParseExpr(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hotspot formula %q: %w", source, err)
+	}
+	if err := validateFormulaExpr(expr); err != nil {
+		return nil, fmt.Errorf("invalid hotspot formula %q: %w", source, err)
+	}
+	return &HotspotFormula{source: source, expr: expr}, nil
+}
+
+func (f *HotspotFormula) String() string {
+	return f.source
+}
+
+func (f *HotspotFormula) Eval(vars map[string]float64) float64 {
+	return evalFormulaExpr(f.expr, vars)
+}
+
+func validateFormulaExpr(expr ast.Expr) error {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.INT && e.Kind != token.FLOAT {
+			return fmt.Errorf("unsupported literal %s", e.Value)
+		}
+		return nil
+	case *ast.Ident:
+		i := sort.SearchStrings(hotspotFormulaVars, e.Name)
+		if i == len(hotspotFormulaVars) || hotspotFormulaVars[i] != e.Name {
+			return fmt.Errorf("unknown variable %q (want one of %s)", e.Name, strings.Join(hotspotFormulaVars, ", "))
+		}
+		return nil
+	case *ast.ParenExpr:
+		return validateFormulaExpr(e.X)
+	case *ast.UnaryExpr:
+		if e.Op != token.SUB && e.Op != token.ADD {
+			return fmt.Errorf("unsupported operator %s", e.Op)
+		}
+		return validateFormulaExpr(e.X)
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.ADD, token.SUB, token.MUL, token.QUO:
+		default:
+			return fmt.Errorf("unsupported operator %s", e.Op)
+		}
+		if err := validateFormulaExpr(e.X); err != nil {
+			return err
+		}
+		return validateFormulaExpr(e.Y)
+	case *ast.CallExpr:
+		name, ok := e.Fun.(*ast.Ident)
+		if !ok {
+			return fmt.Errorf("unsupported function call")
+		}
+		fn, ok := hotspotFormulaFuncs[name.Name]
+		if !ok {
+			return fmt.Errorf("unknown function %q", name.Name)
+		}
+		if len(e.Args) != fn.arity || e.Ellipsis.IsValid() {
+			return fmt.Errorf("%s takes %d argument(s), got %d", name.Name, fn.arity, len(e.Args))
+		}
+		for _, arg := range e.Args {
+			if err := validateFormulaExpr(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported expression %T", expr)
+	}
+}
+
+func evalFormulaExpr(expr ast.Expr, vars map[string]float64) float64 {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		v, _ := strconv.ParseFloat(e.Value, 64)
+		return v
+	case *ast.Ident:
+		return vars[e.Name]
+	case *ast.ParenExpr:
+		return evalFormulaExpr(e.X, vars)
+	case *ast.UnaryExpr:
+		if e.Op == token.SUB {
+			return -evalFormulaExpr(e.X, vars)
+		}
+		return evalFormulaExpr(e.X, vars)
+	case *ast.BinaryExpr:
+		x, y := evalFormulaExpr(e.X, vars), evalFormulaExpr(e.Y, vars)
+		switch e.Op {
+		case token.ADD:
+			return x + y
+		case token.SUB:
+			return x - y
+		case token.MUL:
+			return x * y
+		default:
+			return x / y
+		}
+	case *ast.CallExpr:
+		fn := hotspotFormulaFuncs[e.Fun.(*ast.Ident).Name]
+		args := make([]float64, len(e.Args))
+		for i, arg := range e.Args {
+			args[i] = evalFormulaExpr(arg, vars)
+		}
+		return fn.fn(args)
+	default:
+		return math.NaN()
+	}
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package usecase
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+func resolvePackageGlobals(files []model.FileMetrics) {
+	scopes := make(map[string]map[string]struct{})
+	for _, f := range files {
+		if f.Language != model.LanguageGo || len(f.PackageVars) == 0 {
+			continue
+		}
+		key := packageScopeKey(f)
+		if scopes[key] == nil {
+			scopes[key] = make(map[string]struct{})
+		}
+		for _, name := range f.PackageVars {
+			scopes[key][name] = struct{}{}
+		}
+	}
+
+	for i := range files {
+		f := &files[i]
+		scope := scopes[packageScopeKey(*f)]
+		if scope == nil {
+			continue
+		}
+		for j := range f.Functions {
+			fn := &f.Functions[j]
+			var written []string
+			seen := make(map[string]struct{})
+			first := 0
+			for _, ref := range fn.UnresolvedRefs {
+				if _, ok := scope[ref.Name]; !ok {
+					continue
+				}
+				if !ref.Write {
+					fn.GlobalReads++
+					continue
+				}
+				fn.GlobalWrites++
+				if _, ok := seen[ref.Name]; !ok {
+					seen[ref.Name] = struct{}{}
+					written = append(written, ref.Name)
+				}
+				if first == 0 || ref.Line < first {
+					first = ref.Line
+				}
+			}
+			if len(written) > 0 {
+				addGlobalMutation(f, fn, written, first)
+			}
+		}
+	}
+}
+
+func packageScopeKey(f model.FileMetrics) string {
+	return filepath.Dir(filepath.FromSlash(f.Path)) + "\x00" + f.Package
+}
+
+func addGlobalMutation(f *model.FileMetrics, fn *model.FunctionMetrics, written []string, line int) {
+	for k := range f.Smells {
+		smell := &f.Smells[k]
+		if smell.Kind != model.SmellGlobalMutation || smell.Function != fn.Name {
+			continue
+		}
+		smell.Description += ", " + strings.Join(written, ", ")
+		smell.Line = min(smell.Line, line)
+		return
+	}
+	addFunctionSmell(f, fn, model.CodeSmell{
+		Kind:        model.SmellGlobalMutation,
+		Description: fmt.Sprintf("function writes package variable(s) %s", strings.Join(written, ", ")),
+		FilePath:    f.Path,
+		Function:    fn.Name,
+		Line:        line,
+	})
+}
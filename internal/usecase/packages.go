@@ -0,0 +1,178 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package usecase
+
+import (
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+)
+
+func buildPackageMetrics(root, modulePrefix string, files []model.FileMetrics, omitIsolated bool) []model.PackageMetrics {
+	type pkgAgg struct {
+		metrics    model.PackageMetrics
+		imports    map[string]struct{}
+		types      int
+		interfaces int
+		dependents map[string]struct{}
+		dependsOn  map[string]struct{}
+	}
+
+	byPkg := make(map[string]*pkgAgg)
+	for _, f := range files {
+		if f.Language != model.LanguageGo {
+			continue
+		}
+		dir := filepath.Dir(f.Path)
+		if rel, err := filepath.Rel(root, dir); err == nil {
+			dir = rel
+		}
+		dir = filepath.ToSlash(dir)
+
+		a := byPkg[dir]
+		if a == nil {
+			a = &pkgAgg{
+				metrics:    model.PackageMetrics{Path: dir},
+				imports:    make(map[string]struct{}),
+				dependents: make(map[string]struct{}),
+				dependsOn:  make(map[string]struct{}),
+			}
+			byPkg[dir] = a
+		}
+		a.metrics.Files++
+		a.types += f.TypeDecls
+		a.interfaces += f.InterfaceDecls
+		for _, imp := range f.Imports {
+			a.imports[imp] = struct{}{}
+		}
+	}
+	if len(byPkg) == 0 {
+		return nil
+	}
+
+	known := make(map[string]struct{}, len(byPkg))
+	for path := range byPkg {
+		known[path] = struct{}{}
+	}
+	for path, a := range byPkg {
+		for imp := range a.imports {
+			target := resolvePackageImport(imp, modulePrefix, known)
+			if target == "" || target == path {
+				continue
+			}
+			a.dependsOn[target] = struct{}{}
+			byPkg[target].dependents[path] = struct{}{}
+		}
+	}
+
+	out := make([]model.PackageMetrics, 0, len(byPkg))
+	for _, a := range byPkg {
+		m := a.metrics
+		m.Afferent = len(a.dependents)
+		m.Efferent = len(a.dependsOn)
+		if m.Afferent+m.Efferent > 0 {
+			instability := float64(m.Efferent) / float64(m.Afferent+m.Efferent)
+			m.Instability = &instability
+		} else {
+			m.Isolated = true
+			if !omitIsolated {
+				instability := 0.0
+				m.Instability = &instability
+			}
+		}
+		if a.types > 0 && m.Instability != nil {
+			abstractness := float64(a.interfaces) / float64(a.types)
+			distance := math.Abs(abstractness + *m.Instability - 1)
+			m.Abstractness = &abstractness
+			m.Distance = &distance
+		}
+		out = append(out, m)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		ii, ij := sortableInstability(out[i]), sortableInstability(out[j])
+		if ii == ij {
+			return out[i].Path < out[j].Path
+		}
+		return ii > ij
+	})
+	return out
+}
+
+func sortableInstability(p model.PackageMetrics) float64 {
+	if p.Instability == nil {
+		return -1
+	}
+	return *p.Instability
+}
+
+func resolvePackageImport(imp, modulePrefix string, packages map[string]struct{}) string {
+	if modulePrefix != "" {
+		rel, ok := strings.CutPrefix(imp, modulePrefix)
+		switch {
+		case !ok:
+			return ""
+		case rel == "":
+			rel = "."
+		case rel[0] == '/':
+			rel = rel[1:]
+		default:
+			return ""
+		}
+		if _, known := packages[rel]; known {
+			return rel
+		}
+		return ""
+	}
+
+	best := ""
+	for path := range packages {
+		if path == "." || len(path) <= len(best) {
+			continue
+		}
+		if imp == path || strings.HasSuffix(imp, "/"+path) {
+			best = path
+		}
+	}
+	return best
+}
+
+func goModulePrefix(reader ports.FileReader, root string) string {
+	for dir := root; ; dir = filepath.Dir(dir) {
+		if data, err := reader.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+			module := goModulePath(data)
+			if module == "" {
+				return ""
+			}
+			rel, err := filepath.Rel(dir, root)
+			if err != nil {
+				return ""
+			}
+			if rel == "." {
+				return module
+			}
+			return module + "/" + filepath.ToSlash(rel)
+		}
+		if filepath.Dir(dir) == dir {
+			return ""
+		}
+	}
+}
+
+func goModulePath(gomod []byte) string {
+	for _, line := range strings.Split(string(gomod), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], "\"`")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package usecase
+
+import (
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+)
+
+func selectParser(parsers []ports.CodeParser, path string, src []byte) ports.CodeParser {
+	if src != nil {
+		for _, p := range parsers {
+			if m, ok := p.(ports.ContentMatcher); ok && m.MatchesContent(path, src) {
+				return p
+			}
+		}
+	}
+	var best ports.CodeParser
+	bestLen := -1
+	for _, p := range parsers {
+		if !p.SupportsFile(path) {
+			continue
+		}
+		if n := matchedExtensionLen(p, path); n > bestLen {
+			best, bestLen = p, n
+		}
+	}
+	return best
+}
+
+func matchedExtensionLen(p ports.CodeParser, path string) int {
+	lower := strings.ToLower(path)
+	longest := 0
+	for _, ext := range p.Extensions() {
+		if len(ext) > longest && strings.HasSuffix(lower, strings.ToLower(ext)) {
+			longest = len(ext)
+		}
+	}
+	return longest
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+)
+
+type PublishReportRequest struct {
+	Format        string
+	OutputPath    string
+	SummaryFormat string
+}
+
+type PublishReportUseCase struct {
+	registry  ports.RendererRegistry
+	writeFile func(path string, data []byte) error
+}
+
+func NewPublishReportUseCase(registry ports.RendererRegistry, writeFile func(path string, data []byte) error) *PublishReportUseCase {
+	return &PublishReportUseCase{
+		registry:  registry,
+		writeFile: writeFile,
+	}
+}
+
+func (uc *PublishReportUseCase) Execute(report *model.ProjectReport, req PublishReportRequest) (string, error) {
+	var stdout string
+
+	if req.OutputPath != "" || req.SummaryFormat == "" {
+		out, err := uc.render(report, req.Format)
+		if err != nil {
+			return "", err
+		}
+		if req.OutputPath != "" {
+			if err := uc.writeFile(req.OutputPath, []byte(out)); err != nil {
+				return "", fmt.Errorf("write %s: %w", req.OutputPath, err)
+			}
+		} else {
+			stdout = out
+		}
+	}
+
+	if req.SummaryFormat != "" {
+		summary, err := uc.render(report, req.SummaryFormat)
+		if err != nil {
+			return "", err
+		}
+		stdout = summary
+	}
+	return stdout, nil
+}
+
+func (uc *PublishReportUseCase) render(report *model.ProjectReport, format string) (string, error) {
+	renderer, ok := uc.registry.Get(strings.ToLower(format))
+	if !ok {
+		return "", fmt.Errorf("unknown format %q", format)
+	}
+	return renderer.Render(report)
+}
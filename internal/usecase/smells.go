@@ -0,0 +1,226 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+const minPaddedFunctionLines = 10
+
+const minCommentedCodeLines = 3
+
+const minDivergenceComplexity = 6
+
+var commentedCodeRe = regexp.MustCompile(
+	`^(?:(?:if|for|while|switch|return|else|case|func|var|const|int|void|char|struct)\b.*|.*[;{}]|.*:=.*|.*\w\(.*\))$`)
+
+type SmellConfig struct {
+	MaxFanOutFiles   int
+	MixedIndentation bool
+	MaxPaddingRatio  float64
+	MaxFunctionNLOC  int
+	MaxSurfaceArea   int
+
+	MaxCommentDensity float64
+
+	MaxComplexityDivergence float64
+}
+
+func detectFunctionSmells(files []model.FileMetrics, cfg SmellConfig) {
+	for i := range files {
+		f := &files[i]
+		for j := range f.Functions {
+			fn := &f.Functions[j]
+			if fn.EndLine >= fn.StartLine && fn.StartLine > 0 {
+				fn.PhysicalLines = fn.EndLine - fn.StartLine + 1
+			}
+			if cfg.MaxPaddingRatio > 0 && !fn.IsDataFunction && fn.PhysicalLines >= minPaddedFunctionLines && fn.NLOC > 0 {
+				if ratio := float64(fn.PhysicalLines) / float64(fn.NLOC); ratio > cfg.MaxPaddingRatio {
+					addFunctionSmell(f, fn, model.CodeSmell{
+						Kind: model.SmellBlankPadding,
+						Description: fmt.Sprintf("function spans %d lines but has only %d logical lines (%.1fx > %.1fx)",
+							fn.PhysicalLines, fn.NLOC, ratio, cfg.MaxPaddingRatio),
+						FilePath: f.Path,
+						Function: fn.Name,
+						Line:     fn.StartLine,
+					})
+				}
+			}
+			if cfg.MaxFunctionNLOC > 0 && !fn.IsDataFunction && fn.NLOC > cfg.MaxFunctionNLOC {
+				addFunctionSmell(f, fn, model.CodeSmell{
+					Kind:        model.SmellLongFunction,
+					Description: fmt.Sprintf("function has %d logical lines (>%d)", fn.NLOC, cfg.MaxFunctionNLOC),
+					FilePath:    f.Path,
+					Function:    fn.Name,
+					Line:        fn.StartLine,
+				})
+			}
+			annotateComplexityDivergence(fn, cfg.MaxComplexityDivergence)
+			fn.SurfaceArea = fn.Parameters + fn.ReturnValueCount + fn.LocalVariables
+			if cfg.MaxSurfaceArea > 0 && fn.SurfaceArea > cfg.MaxSurfaceArea {
+				addFunctionSmell(f, fn, model.CodeSmell{
+					Kind: model.SmellLargeSurface,
+					Description: fmt.Sprintf("function has a surface area of %d (%d parameters + %d results + %d locals > %d)",
+						fn.SurfaceArea, fn.Parameters, fn.ReturnValueCount, fn.LocalVariables, cfg.MaxSurfaceArea),
+					FilePath: f.Path,
+					Function: fn.Name,
+					Line:     fn.StartLine,
+				})
+			}
+			if cfg.MaxFanOutFiles > 0 && fn.FanOutFiles > cfg.MaxFanOutFiles {
+				addFunctionSmell(f, fn, model.CodeSmell{
+					Kind:        model.SmellHighFanOut,
+					Description: fmt.Sprintf("function calls into %d other files (>%d)", fn.FanOutFiles, cfg.MaxFanOutFiles),
+					FilePath:    f.Path,
+					Function:    fn.Name,
+					Line:        fn.StartLine,
+				})
+			}
+		}
+	}
+}
+
+func addFunctionSmell(f *model.FileMetrics, fn *model.FunctionMetrics, smell model.CodeSmell) {
+	if slices.Contains(fn.SuppressedSmells, smell.Kind) {
+		return
+	}
+	f.Smells = append(f.Smells, smell)
+}
+
+func annotateComplexityDivergence(fn *model.FunctionMetrics, maxRatio float64) {
+	if fn.CCN <= 0 {
+		return
+	}
+	fn.CognitiveToCCN = float64(fn.CognitiveComplexity) / float64(fn.CCN)
+	if maxRatio <= 1 || max(fn.CCN, fn.CognitiveComplexity) < minDivergenceComplexity {
+		return
+	}
+	switch {
+	case fn.CognitiveToCCN > maxRatio:
+		fn.Divergence = model.DivergenceDeepNesting
+	case fn.CognitiveToCCN < 1/maxRatio:
+		fn.Divergence = model.DivergenceFlatBranching
+	}
+}
+
+func annotateRemediations(files []model.FileMetrics) {
+	for i := range files {
+		for j := range files[i].Smells {
+			smell := &files[i].Smells[j]
+			if smell.Remediation == "" {
+				smell.Remediation = model.SmellRemediation(smell.Kind)
+			}
+		}
+	}
+}
+
+func collectIssues(files []model.FileMetrics) []model.Issue {
+	var issues []model.Issue
+	for _, f := range files {
+		for _, smell := range f.Smells {
+			path := smell.FilePath
+			if path == "" {
+				path = f.Path
+			}
+			issues = append(issues, model.Issue{
+				RuleID:      string(smell.Kind),
+				Severity:    model.SmellSeverity(smell.Kind),
+				Message:     smell.Description,
+				FilePath:    path,
+				Function:    smell.Function,
+				Class:       smell.Class,
+				Line:        smell.Line,
+				Remediation: smell.Remediation,
+			})
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].FilePath != issues[j].FilePath {
+			return issues[i].FilePath < issues[j].FilePath
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+func detectExcessiveComments(fm *model.FileMetrics, src []byte, maxDensity float64) *model.CodeSmell {
+	if fm.Comments.CommentDensity <= maxDensity {
+		return nil
+	}
+	codeLines, first := 0, 0
+	inBlock := false
+	for i, line := range strings.Split(string(src), "\n") {
+		text := strings.TrimSpace(line)
+		var comment string
+		switch {
+		case inBlock:
+			comment = text
+		case strings.HasPrefix(text, "//"):
+			comment = strings.TrimPrefix(text, "//")
+		case strings.HasPrefix(text, "/*"):
+			comment = strings.TrimPrefix(text, "/*")
+			inBlock = true
+		default:
+			continue
+		}
+		if inBlock && strings.Contains(comment, "*/") {
+			comment, _, _ = strings.Cut(comment, "*/")
+			inBlock = false
+		}
+		comment = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(comment), "*"))
+		if comment != "" && commentedCodeRe.MatchString(comment) {
+			if codeLines == 0 {
+				first = i + 1
+			}
+			codeLines++
+		}
+	}
+	if codeLines < minCommentedCodeLines {
+		return nil
+	}
+	return &model.CodeSmell{
+		Kind: model.SmellExcessiveComments,
+		Description: fmt.Sprintf("comment density %.0f%% exceeds %.0f%% with %d commented-out code lines (first at line %d)",
+			fm.Comments.CommentDensity*100, maxDensity*100, codeLines, first),
+		FilePath: fm.Path,
+		Line:     first,
+	}
+}
+
+func detectMixedIndentation(path string, src []byte) *model.CodeSmell {
+	var style byte
+	for i, line := range bytes.Split(src, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 || (line[0] != ' ' && line[0] != '\t') {
+			continue
+		}
+		if style == 0 {
+			style = line[0]
+			continue
+		}
+		indent := line[:len(line)-len(bytes.TrimLeft(line, " \t"))]
+		var mixed bool
+		if style == '\t' {
+			mixed = indent[0] == ' '
+		} else {
+			mixed = bytes.IndexByte(indent, '\t') >= 0
+		}
+		if mixed {
+			return &model.CodeSmell{
+				Kind:        model.SmellMixedIndentation,
+				Description: fmt.Sprintf("file mixes tab and space indentation (first at line %d)", i+1),
+				FilePath:    path,
+				Line:        i + 1,
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package usecase
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+const (
+	TestsInclude = "include"
+	TestsExclude = "exclude"
+	TestsOnly    = "only"
+)
+
+var DefaultTestDirs = []string{"test", "tests", "spec", "__tests__"}
+
+var DefaultTestFilePatterns = map[model.Language][]string{
+	model.LanguageGo:     {"*_test.go"},
+	model.LanguageC:      {"test_*.c", "*_test.c"},
+	model.LanguageCpp:    {"test_*.cpp", "*_test.cpp", "*_test.cc", "*_unittest.cc"},
+	model.LanguageCSharp: {"*Tests.cs", "*Test.cs"},
+}
+
+type TestFileConfig struct {
+	Mode     string
+	Dirs     []string
+	Patterns map[model.Language][]string
+}
+
+func ValidateTestsMode(mode string) error {
+	switch mode {
+	case "", TestsInclude, TestsExclude, TestsOnly:
+		return nil
+	default:
+		return fmt.Errorf("unknown tests mode %q (want %s, %s or %s)", mode, TestsInclude, TestsExclude, TestsOnly)
+	}
+}
+
+func ValidateTestFileConfig(cfg TestFileConfig) error {
+	if err := ValidateTestsMode(cfg.Mode); err != nil {
+		return err
+	}
+	_, err := newTestFileClassifier(cfg)
+	return err
+}
+
+var testFileLanguages = map[string][]model.Language{
+	".go":  {model.LanguageGo},
+	".c":   {model.LanguageC},
+	".h":   {model.LanguageC, model.LanguageCpp},
+	".cpp": {model.LanguageCpp},
+	".cc":  {model.LanguageCpp},
+	".cxx": {model.LanguageCpp},
+	".hpp": {model.LanguageCpp},
+	".hh":  {model.LanguageCpp},
+	".hxx": {model.LanguageCpp},
+	".cs":  {model.LanguageCSharp},
+}
+
+type testFileClassifier struct {
+	dirs     map[string]struct{}
+	patterns map[model.Language][]string
+}
+
+func newTestFileClassifier(cfg TestFileConfig) (*testFileClassifier, error) {
+	dirs := cfg.Dirs
+	if dirs == nil {
+		dirs = DefaultTestDirs
+	}
+	byLang := cfg.Patterns
+	if byLang == nil {
+		byLang = DefaultTestFilePatterns
+	}
+
+	c := &testFileClassifier{dirs: make(map[string]struct{}, len(dirs)), patterns: byLang}
+	for _, d := range dirs {
+		if d = strings.Trim(strings.TrimSpace(d), "/"); d != "" {
+			c.dirs[d] = struct{}{}
+		}
+	}
+	for lang := range byLang {
+		if _, ok := DefaultTestFilePatterns[lang]; !ok {
+			return nil, fmt.Errorf("unknown test pattern language %q (want go, c, cpp or csharp)", lang)
+		}
+	}
+	return c, nil
+}
+
+func (c *testFileClassifier) isTest(root, file string) bool {
+	rel := file
+	if r, err := filepath.Rel(root, file); err == nil && !strings.HasPrefix(r, "..") {
+		rel = r
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for _, dir := range parts[:len(parts)-1] {
+		if _, ok := c.dirs[dir]; ok {
+			return true
+		}
+	}
+	base := parts[len(parts)-1]
+	for _, lang := range testFileLanguages[strings.ToLower(path.Ext(base))] {
+		for _, pattern := range c.patterns[lang] {
+			if ok, _ := path.Match(pattern, base); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *testFileClassifier) selectFiles(root string, files []string, mode string) []string {
+	if mode == "" || mode == TestsInclude {
+		return files
+	}
+	var kept []string
+	for _, f := range files {
+		if c.isTest(root, f) == (mode == TestsOnly) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
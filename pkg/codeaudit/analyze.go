@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package codeaudit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/adapter/parser"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+	"github.com/rafaelvolkmer/codeaudit/internal/usecase"
+)
+
+type (
+	Parser          = ports.CodeParser
+	Report          = model.ProjectReport
+	FileMetrics     = model.FileMetrics
+	FunctionMetrics = model.FunctionMetrics
+)
+
+type Options struct {
+	Parsers    []Parser
+	IncludeExt []string
+
+	MaxLineLength       int
+	MaxFunctionsPerFile int
+
+	GeneratedAt time.Time
+}
+
+func DefaultParsers() []Parser {
+	return []Parser{parser.NewGoParser(), parser.NewCppParser(), parser.NewCSharpParser(), parser.NewCParser()}
+}
+
+func AnalyzeSources(ctx context.Context, sources map[string][]byte) (*Report, error) {
+	return AnalyzeSourcesWithOptions(ctx, sources, Options{})
+}
+
+func AnalyzeSourcesWithOptions(ctx context.Context, sources map[string][]byte, opts Options) (*Report, error) {
+	parsers := opts.Parsers
+	if len(parsers) == 0 {
+		parsers = DefaultParsers()
+	}
+	includeExt := opts.IncludeExt
+	if len(includeExt) == 0 {
+		includeExt = parser.Extensions(parsers)
+	}
+
+	report, err := usecase.AnalyzeSources(ctx, sources, parsers, usecase.AnalyzeProjectRequest{
+		IncludeExt:          includeExt,
+		MaxLineLength:       opts.MaxLineLength,
+		MaxFunctionsPerFile: opts.MaxFunctionsPerFile,
+		GeneratedAt:         opts.GeneratedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("analyze sources: %w", err)
+	}
+	return report, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
 	"github.com/rafaelvolkmer/codeaudit/internal/infrastructure"
 	"github.com/rafaelvolkmer/codeaudit/internal/usecase"
+	"github.com/rafaelvolkmer/codeaudit/pkg/codeaudit"
 )
 
 func TestAnalyzeSampleProject(t *testing.T) {
@@ -52,3 +53,66 @@ func TestAnalyzeSampleProject(t *testing.T) {
 		t.Fatalf("expected at least one function in project metrics")
 	}
 }
+
+func TestAnalyzeSourcesInMemory(t *testing.T) {
+	sources := map[string][]byte{
+		"svc/handler.go": []byte(`package svc
+
+func Handle(n int) int {
+	if n > 0 {
+		return helper(n)
+	}
+	return 0
+}
+
+func helper(n int) int { return n * 2 }
+`),
+		"native/sum.c": []byte("int sum(int a, int b) {\n\tif (a > b) {\n\t\treturn a;\n\t}\n\treturn a + b;\n}\n"),
+		"README.md":    []byte("# not source\n"),
+	}
+	parsers := []ports.CodeParser{parser.NewGoParser(), parser.NewCParser()}
+
+	report, err := usecase.AnalyzeSources(context.Background(), sources, parsers, usecase.AnalyzeProjectRequest{})
+	if err != nil {
+		t.Fatalf("analyze sources: %v", err)
+	}
+	if report.Project.TotalFiles != 2 || report.Project.TotalFunctions != 3 {
+		t.Fatalf("expected 2 files / 3 functions, got %d / %d", report.Project.TotalFiles, report.Project.TotalFunctions)
+	}
+	for _, f := range report.Files {
+		if f.Git != nil {
+			t.Fatalf("in-memory analysis must not collect git metrics, got %+v", f.Git)
+		}
+		if f.Path == "svc/handler.go" && f.Summary.CCNTotal != 3 {
+			t.Fatalf("unexpected CCN for %s: %d", f.Path, f.Summary.CCNTotal)
+		}
+	}
+
+	report, err = usecase.AnalyzeSources(context.Background(), sources, parsers, usecase.AnalyzeProjectRequest{IncludeExt: []string{".c"}})
+	if err != nil || len(report.Files) != 1 || report.Files[0].Path != "native/sum.c" {
+		t.Fatalf("expected IncludeExt to restrict in-memory files, got %v (%v)", report, err)
+	}
+}
+
+func TestPublicAnalyzeSourcesUsesDefaultParsers(t *testing.T) {
+	sources := map[string][]byte{
+		"svc/handler.go":  []byte("package svc\n\nfunc Handle(n int) int {\n\tif n > 0 {\n\t\treturn n\n\t}\n\treturn 0\n}\n"),
+		"native/box.cc":   []byte("int Box::open(int x) {\n    return x > 0 ? x : 0;\n}\n"),
+		"app/Service.cs":  []byte("namespace App;\n\npublic class Service\n{\n    public int Run(int x) { return x; }\n}\n"),
+		"docs/README.md":  []byte("# not source\n"),
+		"native/legacy.c": []byte("int legacy(void) {\n\treturn 0;\n}\n"),
+	}
+
+	report, err := codeaudit.AnalyzeSources(context.Background(), sources)
+	if err != nil {
+		t.Fatalf("analyze sources: %v", err)
+	}
+	if report.Project.TotalFiles != 4 || report.Project.TotalFunctions != 4 {
+		t.Fatalf("expected 4 files / 4 functions with the default parsers, got %d / %d", report.Project.TotalFiles, report.Project.TotalFunctions)
+	}
+
+	report, err = codeaudit.AnalyzeSourcesWithOptions(context.Background(), sources, codeaudit.Options{IncludeExt: []string{".go"}})
+	if err != nil || len(report.Files) != 1 || report.Files[0].Path != "svc/handler.go" {
+		t.Fatalf("expected IncludeExt to restrict the analysis, got %v (%v)", report, err)
+	}
+}
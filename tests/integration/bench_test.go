@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	parser "github.com/rafaelvolkmer/codeaudit/internal/adapter/parser"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+	"github.com/rafaelvolkmer/codeaudit/internal/infrastructure"
+	"github.com/rafaelvolkmer/codeaudit/internal/usecase"
+)
+
+const benchFunctions = 400
+
+func largeGoSource(n int) []byte {
+	var b strings.Builder
+	b.WriteString("package bench\n\nimport \"strings\"\n\nvar total int\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, `
+// Step%[1]d folds the input into the running total.
+func Step%[1]d(xs []string, limit int) (int, error) {
+	count := 0
+	for i, x := range xs {
+		switch {
+		case i > limit && strings.HasPrefix(x, "a"):
+			count += len(x)
+		case x == "":
+			continue
+		default:
+			if err := check%[1]d(x); err != nil {
+				return count, err
+			}
+		}
+	}
+	total += count
+	return count, nil
+}
+
+func check%[1]d(s string) error {
+	if len(s) > 3 || s == "x" {
+		return nil
+	}
+	return nil
+}
+`, i)
+	}
+	return []byte(b.String())
+}
+
+func largeCSource(n int) []byte {
+	var b strings.Builder
+	b.WriteString("#include <stdio.h>\n\nstatic int total;\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, `
+/** step%[1]d folds the input into the running total. */
+int step%[1]d(const char **xs, int len, int limit)
+{
+    int count = 0;
+    for (int i = 0; i < len; i++) {
+        /* skip empty strings */
+        if (xs[i] == NULL || xs[i][0] == '\0') {
+            continue;
+        } else if (i > limit && xs[i][0] == 'a') {
+            count += 2;
+        }
+        switch (xs[i][0]) {
+        case 'b':
+            count++;
+            break;
+        default:
+            count += helper%[1]d(i);
+        }
+    }
+    total += count;
+    return count;
+}
+
+static int helper%[1]d(int x)
+{
+    return x > 3 ? x : -x;
+}
+`, i)
+	}
+	return []byte(b.String())
+}
+
+func benchmarkParser(b *testing.B, p ports.CodeParser, path string, src []byte) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(src)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.ParseFile(path, src); err != nil {
+			b.Fatalf("parse: %v", err)
+		}
+	}
+}
+
+func BenchmarkGoParserParseFile(b *testing.B) {
+	benchmarkParser(b, parser.NewGoParser(), "bench.go", largeGoSource(benchFunctions))
+}
+
+func BenchmarkCParserParseFile(b *testing.B) {
+	benchmarkParser(b, parser.NewCParser(), "bench.c", largeCSource(benchFunctions))
+}
+
+func BenchmarkCppParserStrictParseFile(b *testing.B) {
+	p := parser.NewCppParserWithOptions(parser.CppParserOptions{CppMode: parser.CppModeStrict})
+	benchmarkParser(b, p, "bench.cpp", largeCSource(benchFunctions))
+}
+
+type noGitClient struct{}
+
+func (noGitClient) CollectFileMetrics(ctx context.Context, root string) (map[string]*model.GitFileMetrics, error) {
+	return nil, nil
+}
+
+func BenchmarkAnalyzeProjectSampleData(b *testing.B) {
+	scanner := infrastructure.NewFSScanner()
+	uc := usecase.NewAnalyzeProjectUseCase(
+		scanner,
+		scanner,
+		[]ports.CodeParser{parser.NewGoParser(), parser.NewCParser()},
+		noGitClient{},
+		&memStorage{},
+		2,
+	)
+	req := usecase.AnalyzeProjectRequest{
+		RootPath:   filepath.Join("..", "data"),
+		IncludeExt: []string{".go", ".c"},
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := uc.Execute(context.Background(), req); err != nil {
+			b.Fatalf("analyze: %v", err)
+		}
+	}
+}
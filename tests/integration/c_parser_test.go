@@ -0,0 +1,632 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	outputadapter "github.com/rafaelvolkmer/codeaudit/internal/adapter/output"
+	parser "github.com/rafaelvolkmer/codeaudit/internal/adapter/parser"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+	"github.com/rafaelvolkmer/codeaudit/internal/usecase"
+)
+
+func parseC(t *testing.T, path, src string) *model.FileMetrics {
+	t.Helper()
+	fm, err := parser.NewCParser().ParseFile(path, []byte(src))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return fm
+}
+
+func TestCOperatorCountsIgnoreStringLiterals(t *testing.T) {
+	src := `int pick(int a, int b) {
+    const char *msg = "a && b || c ? d : e; goto end";
+    char q = '?';
+    /* a || b in a comment */
+    if (a && b || a > b) {
+        return a > 0 ? a : b; // x ? y : z
+    }
+    goto out;
+out:
+    return 0;
+}
+`
+	fm := parseC(t, "ops.c", src)
+	fn := findFunction(t, fm, "pick")
+	if fn.Operators == nil {
+		t.Fatalf("expected operator counts on C functions")
+	}
+	want := model.OperatorCounts{Ternary: 1, Logical: 2, Goto: 1}
+	if *fn.Operators != want {
+		t.Fatalf("operators = %+v, want %+v", *fn.Operators, want)
+	}
+}
+
+func TestCParserIgnoresFunctionLikeMacroBodies(t *testing.T) {
+	src := `#define FN(name) \
+    int name(int x) { \
+        if (x) { \
+            return 1; \
+        } \
+        return 0; \
+    }
+
+FN(generated)
+
+int real(int a) {
+    if (a) {
+        return a;
+    }
+    return 0;
+}
+`
+	fm := parseC(t, "macro.c", src)
+	if len(fm.Functions) != 1 || fm.Functions[0].Name != "real" {
+		names := make([]string, 0, len(fm.Functions))
+		for _, fn := range fm.Functions {
+			names = append(names, fn.Name)
+		}
+		t.Fatalf("expected only the real function, got %v", names)
+	}
+	if fn := fm.Functions[0]; fn.StartLine != 11 || fn.EndLine != 16 {
+		t.Fatalf("unexpected range for real: %d-%d", fn.StartLine, fn.EndLine)
+	}
+}
+
+func parseCpp(t *testing.T, mode, src string) *model.FileMetrics {
+	t.Helper()
+	fm, err := parser.NewCppParserWithOptions(parser.CppParserOptions{CppMode: mode}).ParseFile("fixture.cpp", []byte(src))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return fm
+}
+
+func TestStrictCppModeTemplatedFunction(t *testing.T) {
+	src := `template <typename T, typename U = std::enable_if_t<(sizeof(T) > 4)>>
+T clamp_all(const std::vector<T>& xs, T&& lo, U* u) {
+    T best = lo;
+    const char* open = R"({ if (a && b))";
+    for (const auto& x : xs) {
+        if (x > best && x < lo) {
+            best = x;
+        }
+    }
+    std::map<std::string, std::vector<int>> m{
+        {"a", {1, 2}},
+    };
+    return best;
+}
+
+int after(int a) {
+    return a;
+}
+`
+	strict := parseCpp(t, parser.CppModeStrict, src)
+	fn := findFunction(t, strict, "clamp_all")
+	if fn.CCN != 4 {
+		t.Fatalf("expected strict CCN 4 (for, if, &&), got %d", fn.CCN)
+	}
+	if fn.MaxNesting != 3 {
+		t.Fatalf("expected strict nesting 3, got %d", fn.MaxNesting)
+	}
+	if fn.EndLine != 14 {
+		t.Fatalf("expected clamp_all to end at line 14, got %d", fn.EndLine)
+	}
+	if after := findFunction(t, strict, "after"); after.StartLine != 16 {
+		t.Fatalf("expected after() at line 16, got %d", after.StartLine)
+	}
+
+	heuristic := parseCpp(t, parser.CppModeHeuristic, src)
+	if len(heuristic.Functions) == len(strict.Functions) {
+		t.Fatalf("expected the raw string brace to confuse the heuristic mode")
+	}
+}
+
+func TestStrictCppModeLambdasAndOperators(t *testing.T) {
+	src := `int count_matches(const std::vector<int>& v, int k) {
+    auto pred = [&, k](int x) mutable -> bool {
+        return x > k || x == 0;
+    };
+    auto twice = [=](auto&& f) { return f(1) && f(2); };
+    return std::count_if(v.begin(), v.end(), pred) + (twice(pred) ? 1 : 0);
+}
+
+bool Point::operator<(const Point& o) const {
+    return x < o.x || (x == o.x && y < o.y);
+}
+`
+	strict := parseCpp(t, parser.CppModeStrict, src)
+	count := findFunction(t, strict, "count_matches")
+	if count.CCN != 2 {
+		t.Fatalf("expected strict CCN 2 (?) with lambda bodies scored separately, got %d", count.CCN)
+	}
+	if count.MaxNesting != 1 {
+		t.Fatalf("expected lambda bodies not to nest, got %d", count.MaxNesting)
+	}
+	for _, name := range []string{"count_matches::lambda@2", "count_matches::lambda@5"} {
+		if lambda := findFunction(t, strict, name); lambda.CCN != 2 {
+			t.Fatalf("expected %s CCN 2 (|| or &&), got %d", name, lambda.CCN)
+		}
+	}
+	if op := findFunction(t, strict, "Point::operator<"); op.CCN != 3 {
+		t.Fatalf("expected operator< CCN 3, got %d", op.CCN)
+	}
+
+	heuristic := parseCpp(t, parser.CppModeHeuristic, src)
+	if findFunction(t, heuristic, "count_matches").CCN == count.CCN {
+		t.Fatalf("expected heuristic mode to count auto&& as a boolean operator")
+	}
+}
+
+func TestCUnusedStaticFunctionsAreDeadCode(t *testing.T) {
+	src := `static int helper(int x);
+
+static int helper(int x) {
+	return x + 1;
+}
+
+static int unused(int x) {
+	return x * 2;
+}
+
+static int countdown(int n) {
+	return n > 0 ? countdown(n - 1) : 0;
+}
+
+static void on_event(void) {
+}
+
+int exported_unused(void) {
+	return 0;
+}
+
+int api(void) {
+	register_callback(on_event);
+	return helper(1);
+}
+`
+	fm := parseC(t, "dead.c", src)
+
+	dead := make(map[string]int)
+	for _, s := range smellsOfKind(fm, model.SmellDeadCode) {
+		dead[s.Function] = s.Line
+	}
+	if len(dead) != 2 || dead["unused"] != 7 || dead["countdown"] != 11 {
+		t.Fatalf("expected unused (line 7) and countdown (line 11) flagged, got %v", dead)
+	}
+
+	header := `class Registry {
+public:
+    static Registry *instance() {
+        return nullptr;
+    }
+};
+
+static inline int clamp(int x) {
+    return x < 0 ? 0 : x;
+}
+`
+	for _, name := range []string{"registry.h", "registry.hpp"} {
+		for _, mode := range []string{parser.CppModeHeuristic, parser.CppModeStrict} {
+			p := parser.NewCppParserWithOptions(parser.CppParserOptions{CppMode: mode})
+			fm, err := p.ParseFile(name, []byte(header))
+			if err != nil {
+				t.Fatalf("parse %s: %v", name, err)
+			}
+			if got := smellsOfKind(fm, model.SmellDeadCode); len(got) != 0 {
+				t.Fatalf("%s (%s): statics in headers must not be dead code, got %+v", name, mode, got)
+			}
+		}
+	}
+}
+
+func TestCppConstructorWithManyParameters(t *testing.T) {
+	src := `class Widget {
+public:
+    Widget(int a, int b, int c, int d, int e, int f, int g, int h) {
+        init();
+    }
+    void resize(int a, int b, int c, int d, int e, int f, int g, int h) {
+        apply();
+    }
+    ~Widget() {
+    }
+};
+
+Gadget::Gadget(std::map<int, int> a, int b, int c, int d, int e, int f, int g, int h) {
+    init();
+}
+
+Gadget::Gadget(void) {
+}
+`
+	fm := parseC(t, "widget.cpp", src)
+
+	smells := smellsOfKind(fm, model.SmellConstructorManyParams)
+	if len(smells) != 2 {
+		t.Fatalf("expected two constructor smells, got %+v", smells)
+	}
+	if smells[0].Class != "Widget" || smells[0].Line != 3 || smells[1].Class != "Gadget" || smells[1].Line != 13 {
+		t.Fatalf("unexpected constructor smells: %+v", smells)
+	}
+	if !strings.Contains(smells[1].Description, "8 parameters") {
+		t.Fatalf("template commas must not count as parameters: %s", smells[1].Description)
+	}
+	if n := len(smellsOfKind(fm, model.SmellManyParameters)); n != 0 {
+		t.Fatalf("constructor smell must stay separate from many_parameters, got %d", n)
+	}
+	if p := findFunction(t, fm, "resize").Parameters; p != 8 {
+		t.Fatalf("expected resize to record 8 parameters, got %d", p)
+	}
+
+	relaxed, err := parser.NewCParserWithOptions(parser.CParserOptions{MaxConstructorParams: 8}).
+		ParseFile("widget.cpp", []byte(src))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if n := len(smellsOfKind(relaxed, model.SmellConstructorManyParams)); n != 0 {
+		t.Fatalf("threshold not honored, got %d smells", n)
+	}
+}
+
+func TestCppNamespaceCapturedAsPackage(t *testing.T) {
+	src := `#include <string>
+using namespace std;
+namespace fs = std::filesystem;
+
+namespace acme { inline namespace v2 {
+namespace {
+int hidden() { return 1; }
+}
+int visible() { return hidden(); }
+} }
+`
+	for _, mode := range []string{parser.CppModeHeuristic, parser.CppModeStrict} {
+		if got := parseCpp(t, mode, src).Package; got != "acme::v2" {
+			t.Fatalf("%s: expected acme::v2, got %q", mode, got)
+		}
+	}
+	if got := parseCpp(t, parser.CppModeStrict, "namespace a::b {\nvoid f() {}\n}\n").Package; got != "a::b" {
+		t.Fatalf("expected nested namespace a::b, got %q", got)
+	}
+	fm, err := parser.NewCParser().ParseFile("plain.c", []byte("int f(void) { return 0; }\n"))
+	if err != nil || fm.Package != "" {
+		t.Fatalf("C files have no package, got %q (%v)", fm.Package, err)
+	}
+}
+
+func TestCPublicAPIDocCoverage(t *testing.T) {
+	src := `#include "api.h"
+
+/** Adds two numbers. */
+int add(int a, int b);
+
+/// Subtracts b from a.
+int sub(int a, int b);
+int mul(int a, int b);
+typedef int (*binop)(int, int);
+static int helper(int x);
+
+/*
+ * Plain block comment, not a doc comment.
+ */
+int add(int a, int b) {
+	return helper(a) + b;
+}
+
+/**
+ * Divides a by b.
+ */
+int div_(int a, int b)
+{
+	return a / b;
+}
+
+int mod_(int a, int b) {
+	return a % b;
+}
+
+static int helper(int x) {
+	return x;
+}
+`
+	fm, err := parser.NewCParser().ParseFile("api.c", []byte(src))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if fm.Comments.PublicSymbols != 5 || fm.Comments.PublicDocumented != 3 {
+		t.Fatalf("expected 3 of 5 public functions documented, got %d of %d",
+			fm.Comments.PublicDocumented, fm.Comments.PublicSymbols)
+	}
+	if fm.Comments.PublicAPIDocPct != 0.6 {
+		t.Fatalf("expected 60%% doc coverage, got %.2f", fm.Comments.PublicAPIDocPct)
+	}
+	if len(fm.Functions) != 4 {
+		t.Fatalf("prototypes must not become functions, got %d", len(fm.Functions))
+	}
+}
+
+func TestCDuplicateIncludes(t *testing.T) {
+	src := `#include <stdio.h>
+#include "util.h"
+#include <stdlib.h>
+#  include <stdio.h>
+#include "util.h"
+#include <util.h>
+
+int main(void) {
+	return 0;
+}
+`
+	fm := parseC(t, "dup.c", src)
+
+	dups := smellsOfKind(fm, model.SmellDuplicateInclude)
+	if len(dups) != 2 || dups[0].Line != 4 || dups[1].Line != 5 {
+		t.Fatalf("expected repeated includes on lines 4 and 5, got %+v", dups)
+	}
+	if !strings.Contains(dups[0].Description, "line 1") {
+		t.Fatalf("description should point at the first include: %q", dups[0].Description)
+	}
+}
+
+func TestCPreprocessorComplexity(t *testing.T) {
+	src := `#ifndef PLATFORM_H
+#define PLATFORM_H
+
+#ifdef _WIN32
+#  include <windows.h>
+#elif defined(__APPLE__)
+#  include <mach/mach.h>
+#else
+#  include <unistd.h>
+#endif
+
+int page_size(void) {
+#if defined(_WIN32)
+    return 4096;
+#else
+#ifndef PAGE_SIZE
+    return sysconf(_SC_PAGESIZE);
+#else
+    return PAGE_SIZE;
+#endif
+#endif
+}
+
+#endif
+`
+	fm, err := parser.NewCParserWithOptions(parser.CParserOptions{PreprocessorComplexity: true}).ParseFile("platform.h", []byte(src))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if fm.PreprocessorComplexity != 7 {
+		t.Fatalf("expected 7 preprocessor branches (include guard excluded), got %d", fm.PreprocessorComplexity)
+	}
+	if len(fm.Functions) != 1 || fm.Functions[0].CCN != 1 {
+		t.Fatalf("preprocessor branches must not feed CCN, got %+v", fm.Functions)
+	}
+
+	if off := parseC(t, "platform.h", src); off.PreprocessorComplexity != 0 {
+		t.Fatalf("expected preprocessor complexity to be off by default, got %d", off.PreprocessorComplexity)
+	}
+}
+
+func TestFileLineCountsIndependentOfFunctions(t *testing.T) {
+	src := `/*
+ * Lookup tables.
+ */
+#include <stdint.h>
+
+// Top-level data is not part of any function.
+static const uint8_t table[] = {
+    1, 2, 3, /* inline note */
+    4, 5, 6,
+};
+
+int first(void) { return table[0]; } // trailing note
+`
+	fm := parseC(t, "tables.c", src)
+	if fm.FileLinesTotal != 12 || fm.FileLinesCode != 6 || fm.FileLinesComment != fm.Comments.CommentLines {
+		t.Fatalf("expected 12 total / 6 code lines and comment lines matching the comment metrics, got %d / %d / %d",
+			fm.FileLinesTotal, fm.FileLinesCode, fm.FileLinesComment)
+	}
+	if fm.Summary.NLOC >= fm.FileLinesCode {
+		t.Fatalf("function NLOC (%d) should not cover top-level code (%d code lines)", fm.Summary.NLOC, fm.FileLinesCode)
+	}
+
+	out, err := outputadapter.NewTextRendererWithOptions(outputadapter.TextRendererOptions{Tree: true}).
+		Render(&model.ProjectReport{Files: []model.FileMetrics{*fm}})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(stripANSI(out), "lines=12") {
+		t.Fatalf("expected the text output to show file total lines:\n%s", stripANSI(out))
+	}
+}
+
+func TestCParserLanguageFollowsExtension(t *testing.T) {
+	src := "int twice(int x) {\n    return x * 2;\n}\n"
+	for path, want := range map[string]model.Language{
+		"util.c":   model.LanguageC,
+		"util.h":   model.LanguageC,
+		"util.cpp": model.LanguageCpp,
+		"util.hpp": model.LanguageCpp,
+		"util.cc":  model.LanguageCpp,
+		"util.hh":  model.LanguageCpp,
+		"util.cxx": model.LanguageCpp,
+	} {
+		fm := parseC(t, path, src)
+		if fm.Language != want {
+			t.Fatalf("%s: expected language %s, got %s", path, want, fm.Language)
+		}
+		if len(fm.Functions) != 1 || fm.Functions[0].Language != want {
+			t.Fatalf("%s: expected one %s function, got %+v", path, want, fm.Functions)
+		}
+	}
+}
+
+func TestCppParserClassesTemplatesAndLambdas(t *testing.T) {
+	src := `#include <vector>
+#include <algorithm>
+
+namespace gfx {
+
+class Widget {
+public:
+    int size() const { return n_; }
+    void draw(int x);
+private:
+    int n_;
+};
+
+void Widget::draw(int x) {
+    std::vector<int> v(10);
+    try {
+        std::sort(v.begin(), v.end());
+    } catch (const std::exception &e) {
+        log(e);
+    }
+    auto twice = [&](int y) {
+        if (y > 0) {
+            return y * 2;
+        }
+        return 0;
+    };
+    twice(size());
+}
+
+template <typename T>
+T clamp(T v, T lo, T hi) {
+    if (v < lo) {
+        return lo;
+    }
+    return v > hi ? hi : v;
+}
+
+}
+`
+	fm, err := parser.NewCppParser().ParseFile("widget.cpp", []byte(src))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if fm.Language != model.LanguageCpp || fm.Package != "gfx" {
+		t.Fatalf("expected C++ in namespace gfx, got %s / %q", fm.Language, fm.Package)
+	}
+
+	size := findFunction(t, fm, "Widget::size")
+	if !size.IsPublic || size.CCN != 1 {
+		t.Fatalf("expected public inline member with CCN 1, got %+v", size)
+	}
+
+	draw := findFunction(t, fm, "Widget::draw")
+	if draw.CCN != 2 || draw.Parameters != 1 {
+		t.Fatalf("expected draw to count catch as a branch (CCN 2) with 1 param, got CCN %d params %d", draw.CCN, draw.Parameters)
+	}
+	callees := strings.Join(draw.Callees, ",")
+	for _, want := range []string{"std::sort", "log", "Widget::size"} {
+		if !strings.Contains(","+callees+",", ","+want+",") {
+			t.Fatalf("expected callee %s, got %v", want, draw.Callees)
+		}
+	}
+	for _, c := range draw.Callees {
+		if c == "vector" || c == "std::vector" || c == "v" {
+			t.Fatalf("declaration recorded as a call: %v", draw.Callees)
+		}
+	}
+
+	lambda := findFunction(t, fm, "Widget::draw::lambda@21")
+	if lambda.CCN != 2 || lambda.Parameters != 1 {
+		t.Fatalf("expected the lambda to carry its own if (CCN 2), got %+v", lambda)
+	}
+
+	clamp := findFunction(t, fm, "clamp")
+	if clamp.CCN != 3 || clamp.Parameters != 3 {
+		t.Fatalf("expected template clamp CCN 3 with 3 params, got CCN %d params %d", clamp.CCN, clamp.Parameters)
+	}
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"widget.cpp": src})
+	parsers := []ports.CodeParser{parser.NewGoParser(), parser.NewCppParser(), parser.NewCParser()}
+	report := analyzeWithParsers(t, parsers, usecase.AnalyzeProjectRequest{RootPath: root, IncludeExt: []string{".cpp"}})
+	if len(report.Files) != 1 || len(report.Files[0].Functions) != 4 {
+		t.Fatalf("expected .cpp routed to the C++ parser with 4 functions, got %+v", report.Files)
+	}
+}
+
+func TestCppModeRoutesBothFrontEnds(t *testing.T) {
+	src := "int walk(int *v, int n) {\n\tfor (int i = 0; i < n; i++) {\n\t\tif (v[i]) {\n\t\t\tif (v[i] > 1 && v[i] < 9) {\n\t\t\t\treturn i;\n\t\t\t}\n\t\t}\n\t}\n\treturn -1;\n}\n"
+
+	uncapped, err := parser.NewCppParser().ParseFile("walk.cpp", []byte(src))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	capped, err := parser.NewCppParserWithOptions(parser.CppParserOptions{CognitiveLineCap: 1}).ParseFile("walk.cpp", []byte(src))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got, full := capped.Functions[0].CognitiveComplexity, uncapped.Functions[0].CognitiveComplexity; got != 3 || full <= got {
+		t.Fatalf("expected the line cap to score one point per contributing line (3), got %d (uncapped %d)", got, full)
+	}
+
+	heuristic, err := parser.NewCppParserWithOptions(parser.CppParserOptions{CppMode: parser.CppModeHeuristic}).ParseFile("walk.cpp", []byte(src))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	line := parseC(t, "walk.cpp", src)
+	if heuristic.Language != model.LanguageCpp || heuristic.Functions[0].CognitiveComplexity != line.Functions[0].CognitiveComplexity {
+		t.Fatalf("expected heuristic mode to use the line scanner, got %+v", heuristic.Functions[0])
+	}
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"widget.h": "namespace gfx {\nclass Widget {\npublic:\n    int size() const { return n_ ? n_ : 0; }\n};\n}\n",
+		"plain.h":  "int twice(int x) {\n    return x * 2;\n}\n",
+	})
+	parsers := []ports.CodeParser{parser.NewCParser(), parser.NewCppParserWithOptions(parser.CppParserOptions{CppMode: parser.CppModeStrict})}
+	report := analyzeWithParsers(t, parsers, usecase.AnalyzeProjectRequest{RootPath: root, IncludeExt: []string{".h"}})
+	byName := map[string]model.FileMetrics{}
+	for _, f := range report.Files {
+		byName[filepath.Base(f.Path)] = f
+	}
+	if w := byName["widget.h"]; w.Language != model.LanguageCpp || len(w.Functions) != 1 || w.Functions[0].Name != "Widget::size" {
+		t.Fatalf("expected the C++ header to be selected for the c++ parser, got %+v", w.Functions)
+	}
+	if c := byName["plain.h"]; c.Language != model.LanguageC || len(c.Functions) != 1 {
+		t.Fatalf("expected the C header to stay with the C parser, got %+v", c)
+	}
+}
+
+func TestCppFunctionStartAfterSkippedBraces(t *testing.T) {
+	src := `enum class Mode { Fast, Slow };
+std::vector<int> table{1, 2, 3};
+int guarded(int x) try {
+    return x;
+} catch (...) {
+    return 0;
+}
+
+/// Documented.
+int after(int x) {
+    return x;
+}
+`
+	fm, err := parser.NewCppParser().ParseFile("start.cpp", []byte(src))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if g := findFunction(t, fm, "guarded"); g.StartLine != 3 {
+		t.Fatalf("expected guarded at line 3, got %d", g.StartLine)
+	}
+	after := findFunction(t, fm, "after")
+	if after.StartLine != 10 || after.NLOC != 3 || !after.IsDocumented {
+		t.Fatalf("expected documented after() at line 10 with 3 NLOC, got line %d NLOC %d documented %v",
+			after.StartLine, after.NLOC, after.IsDocumented)
+	}
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"strings"
+	"testing"
+
+	parser "github.com/rafaelvolkmer/codeaudit/internal/adapter/parser"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+	"github.com/rafaelvolkmer/codeaudit/internal/usecase"
+)
+
+func TestCSharpParserMembersLambdasAndDocs(t *testing.T) {
+	src := `using System;
+
+namespace Shop.Orders;
+
+public class OrderService
+{
+    /// <summary>Total of all lines.</summary>
+    public decimal Total
+    {
+        get
+        {
+            if (_lines == null) return 0;
+            return _lines.Sum(l => l.Price);
+        }
+    }
+
+    public int Count { get; set; }
+
+    public string Name { get => _name; set => _name = value; }
+
+    /// <summary>Processes an order.</summary>
+    public bool Process(Order order)
+    {
+        foreach (var line in order.Lines)
+        {
+            if (line.Qty > 0 && line.Price > 0)
+            {
+                Validate(line);
+            }
+        }
+        try
+        {
+            Save(order?.Id ?? 0);
+        }
+        catch (Exception ex)
+        {
+            Console.WriteLine(ex.Message);
+        }
+        int Twice(int x) => x * 2;
+        return Twice(1) > 0;
+    }
+
+    private void Validate(Line line) { }
+
+    private void Save(int id) { }
+}
+`
+	fm, err := parser.NewCSharpParser().ParseFile("OrderService.cs", []byte(src))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if fm.Language != model.LanguageCSharp || fm.Package != "Shop.Orders" {
+		t.Fatalf("expected C# in Shop.Orders, got %s / %q", fm.Language, fm.Package)
+	}
+
+	total := findFunction(t, fm, "OrderService.Total")
+	if total.CCN != 2 || !total.IsPublic || !total.IsDocumented {
+		t.Fatalf("expected a documented public property with CCN 2, got %+v", total)
+	}
+	if lambda := findFunction(t, fm, "OrderService.Total.lambda@13"); lambda.Parameters != 1 {
+		t.Fatalf("expected the lambda to take 1 parameter, got %d", lambda.Parameters)
+	}
+
+	for _, f := range fm.Functions {
+		if strings.HasPrefix(f.Name, "OrderService.Name.lambda") {
+			t.Fatalf("expected expression-bodied accessors not to be lambdas, got %s", f.Name)
+		}
+	}
+
+	process := findFunction(t, fm, "OrderService.Process")
+	if process.CCN != 7 {
+		t.Fatalf("expected CCN 7 (foreach, if, &&, ?., ??, catch), got %d", process.CCN)
+	}
+	want := map[string]bool{"Console.WriteLine": true, "OrderService.Process.Twice": true, "OrderService.Save": true, "OrderService.Validate": true}
+	if len(process.Callees) != len(want) {
+		t.Fatalf("expected callees %v, got %v", want, process.Callees)
+	}
+	for _, c := range process.Callees {
+		if !want[c] {
+			t.Fatalf("unexpected callee %s in %v", c, process.Callees)
+		}
+	}
+	findFunction(t, fm, "OrderService.Process.Twice")
+
+	if validate := findFunction(t, fm, "OrderService.Validate"); validate.IsPublic {
+		t.Fatalf("expected private method to be non-public")
+	}
+	if fm.Comments.PublicSymbols != 4 || fm.Comments.PublicDocumented != 2 {
+		t.Fatalf("expected 2 of 4 public members documented, got %d/%d", fm.Comments.PublicDocumented, fm.Comments.PublicSymbols)
+	}
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"OrderService.cs": src})
+	parsers := []ports.CodeParser{parser.NewGoParser(), parser.NewCppParser(), parser.NewCSharpParser(), parser.NewCParser()}
+	report := analyzeWithParsers(t, parsers, usecase.AnalyzeProjectRequest{RootPath: root, IncludeExt: []string{".cs"}})
+	if len(report.Files) != 1 || report.Files[0].Language != model.LanguageCSharp {
+		t.Fatalf("expected .cs routed to the C# parser, got %+v", report.Files)
+	}
+}
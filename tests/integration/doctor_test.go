@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	outputadapter "github.com/rafaelvolkmer/codeaudit/internal/adapter/output"
+	parser "github.com/rafaelvolkmer/codeaudit/internal/adapter/parser"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+	"github.com/rafaelvolkmer/codeaudit/internal/usecase"
+)
+
+func TestDoctorReportsEnvironmentAndSelfCheck(t *testing.T) {
+	registry := outputadapter.NewRendererRegistry(outputadapter.NewTextRenderer(), outputadapter.NewJSONRenderer())
+	missingGit := func(string) (string, error) { return "", errors.New("not on PATH") }
+
+	report := usecase.NewDoctorUseCase(
+		[]ports.CodeParser{parser.NewGoParser(), parser.NewCParser()},
+		registry,
+		missingGit,
+	).Execute(context.Background())
+
+	if report.GoVersion == "" || report.GitPath != "" || report.GitError != "not on PATH" {
+		t.Fatalf("unexpected environment: %+v", report)
+	}
+	if len(report.Parsers) != 2 || report.Parsers[0].Name != "go" || len(report.Parsers[0].Extensions) != 1 {
+		t.Fatalf("unexpected parsers: %+v", report.Parsers)
+	}
+	if len(report.Renderers) != 2 || report.Renderers[0] != "json" || report.Renderers[1] != "text" {
+		t.Fatalf("unexpected renderers: %v", report.Renderers)
+	}
+	if !report.Healthy() || report.SampleFiles != 2 || report.SampleFunctions != 3 {
+		t.Fatalf("self-check failed: %+v", report)
+	}
+
+	configured, err := parser.Configure([]ports.CodeParser{parser.NewGoParser()}, parser.Config{
+		Extensions: map[string][]string{"go": {".go", ".gotmpl"}},
+	})
+	if err != nil {
+		t.Fatalf("configure parsers: %v", err)
+	}
+	overridden := usecase.NewDoctorUseCase(configured, registry, missingGit).Execute(context.Background())
+	if len(overridden.Parsers) != 1 || strings.Join(overridden.Parsers[0].Extensions, ",") != ".go,.gotmpl" {
+		t.Fatalf("doctor should list the configured extensions, got %+v", overridden.Parsers)
+	}
+
+	broken := usecase.NewDoctorUseCase(nil, registry, missingGit).Execute(context.Background())
+	if broken.Healthy() {
+		t.Fatalf("self-check without parsers should fail")
+	}
+}
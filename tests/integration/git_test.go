@@ -0,0 +1,435 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	gitadapter "github.com/rafaelvolkmer/codeaudit/internal/adapter/git"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/infrastructure"
+	"github.com/rafaelvolkmer/codeaudit/internal/usecase"
+)
+
+func TestGitBugfixDetectionReadsCommitBody(t *testing.T) {
+	root := initRepo(t)
+
+	commitFiles(t, root, "alice", map[string]string{"a.go": "package a\n"}, "Add package a")
+	commitFiles(t, root, "alice", map[string]string{"a.go": "package a\n\nvar x = 1\n"},
+		"Tweak defaults\n\nThe old value broke startup on slow disks.\n\nFixes: #123\n")
+	commitFiles(t, root, "bob", map[string]string{"b.go": "package a\n"},
+		"Add b\n\n1 2 not-a-numstat-line\nSee https://example.com/org/repo/issues/42 for details.\n")
+	commitFiles(t, root, "bob", map[string]string{"b.go": "package a\n\nvar y = 2\n"},
+		"Refactor b\n\nNothing to see here,\njust moving code around.\n")
+
+	metrics, err := gitadapter.NewGitCLI().CollectFileMetrics(context.Background(), root)
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+
+	a := metrics["a.go"]
+	if a == nil || a.Commits != 2 || a.BugfixCommits != 1 {
+		t.Fatalf("a.go: expected 2 commits / 1 bugfix, got %+v", a)
+	}
+	b := metrics["b.go"]
+	if b == nil || b.Commits != 2 || b.BugfixCommits != 1 {
+		t.Fatalf("b.go: expected 2 commits / 1 bugfix, got %+v", b)
+	}
+	if len(metrics) != 2 {
+		t.Fatalf("body lines must not be parsed as numstat entries, got %v", metrics)
+	}
+	if a.LinesAdded != 3 || b.LinesAdded != 3 {
+		t.Fatalf("unexpected churn: a=%+v b=%+v", a, b)
+	}
+}
+
+func TestAuthorComplexityRanking(t *testing.T) {
+	root := initRepo(t)
+
+	commitFiles(t, root, "alice", map[string]string{"complex.go": `package a
+
+func Complex(x int) int {
+	if x > 0 {
+		if x > 10 {
+			return 2
+		}
+		return 1
+	}
+	for i := 0; i < x; i++ {
+		x--
+	}
+	return 0
+}
+`}, "Add complex")
+	commitFiles(t, root, "bob", map[string]string{"simple.go": "package a\n\nfunc Simple() int {\n\treturn 1\n}\n"}, "Add simple")
+	commitFiles(t, root, "bob", map[string]string{"complex.go": `package a
+
+func Complex(x int) int {
+	if x > 0 {
+		if x > 10 {
+			return 2
+		}
+		return 1
+	}
+	for i := 0; i < x; i++ {
+		x -= 1
+	}
+	return 0
+}
+`}, "Tweak complex")
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	if report.AuthorComplexity != nil {
+		t.Fatalf("author complexity must be opt-in")
+	}
+	for _, f := range report.Files {
+		if f.Git == nil || f.Git.DominantAuthor != "" || f.Git.Authors == 0 {
+			t.Fatalf("expected author counts without per-author attribution for %s, got %+v", f.Path, f.Git)
+		}
+	}
+
+	report = analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, AuthorComplexity: true})
+	if len(report.AuthorComplexity) != 2 {
+		t.Fatalf("expected two authors, got %+v", report.AuthorComplexity)
+	}
+	top, second := report.AuthorComplexity[0], report.AuthorComplexity[1]
+	if top.Author != "alice" || top.Files != 1 || top.CCNTotal != 4 {
+		t.Fatalf("expected alice to own complex.go with CCN 4, got %+v", top)
+	}
+	if second.Author != "bob" || second.CCNTotal != 1 {
+		t.Fatalf("expected bob to own simple.go, got %+v", second)
+	}
+}
+
+func TestAnalyzeSingleFilePath(t *testing.T) {
+	root := initRepo(t)
+	commitFiles(t, root, "alice", map[string]string{
+		"top.go":       "package top\n\nfunc Top() {}\n",
+		"pkg/main.go":  "package pkg\n\nfunc Main() {}\n",
+		"pkg/other.go": "package pkg\n\nfunc Other() {}\n",
+	}, "Initial commit")
+	commitFiles(t, root, "bob", map[string]string{
+		"pkg/main.go": "package pkg\n\nfunc Main() {\n\tprintln(1)\n}\n",
+	}, "Touch main")
+
+	file := filepath.Join(root, "pkg", "main.go")
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: file})
+
+	if len(report.Files) != 1 || report.Files[0].Path != file {
+		t.Fatalf("expected only %s, got %d files", file, len(report.Files))
+	}
+	if report.RootPath != filepath.Join(root, "pkg") {
+		t.Fatalf("expected report rooted at the file's directory, got %s", report.RootPath)
+	}
+	if g := report.Files[0].Git; g == nil || g.Commits != 2 {
+		t.Fatalf("expected git metrics with 2 commits, got %+v", g)
+	}
+	if _, err := os.Stat(filepath.Join(root, "pkg", ".codeaudit", "report.json")); err != nil {
+		t.Fatalf("report not saved next to the file: %v", err)
+	}
+
+	loaded, err := infrastructure.NewFileStorage().Load(context.Background(), filepath.Join(root, "pkg"))
+	if err != nil || len(loaded.Files) != 1 {
+		t.Fatalf("load saved report: %v", err)
+	}
+}
+
+func TestDirtyFilesRestrictAnalysis(t *testing.T) {
+	root := initRepo(t)
+	commitFiles(t, root, "alice", map[string]string{
+		"clean.go":  "package p\n\nfunc Clean() {}\n",
+		"edited.go": "package p\n\nfunc Edited() {}\n",
+		"gone.go":   "package p\n\nfunc Gone() {}\n",
+	}, "Initial commit")
+
+	ctx := context.Background()
+	git := gitadapter.NewGitCLI()
+	if dirty, err := git.DirtyFiles(ctx, root); err != nil || len(dirty) != 0 {
+		t.Fatalf("expected a clean tree, got %v (%v)", dirty, err)
+	}
+
+	writeTree(t, root, map[string]string{
+		"edited.go":  "package p\n\nfunc Edited() {\n\tprintln(1)\n}\n",
+		"sub/new.go": "package sub\n\nfunc New() {}\n",
+	})
+	if err := os.Remove(filepath.Join(root, "gone.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	dirty, err := git.DirtyFiles(ctx, root)
+	if err != nil {
+		t.Fatalf("dirty files: %v", err)
+	}
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, OnlyPaths: dirty, NoSave: true})
+
+	got := make([]string, 0, len(report.Files))
+	for _, f := range report.Files {
+		rel, _ := filepath.Rel(root, f.Path)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	sort.Strings(got)
+	if len(got) != 2 || got[0] != "edited.go" || got[1] != "sub/new.go" {
+		t.Fatalf("expected only the dirty files, got %v", got)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".codeaudit", "report.json")); !os.IsNotExist(err) {
+		t.Fatalf("dirty analysis must not overwrite the stored report (stat err %v)", err)
+	}
+
+	if _, err := git.DirtyFiles(ctx, t.TempDir()); err == nil {
+		t.Fatalf("expected an error outside a repository")
+	}
+	empty := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, OnlyPaths: []string{}, AllowEmpty: true, NoSave: true})
+	if len(empty.Files) != 0 {
+		t.Fatalf("an empty dirty set should analyze nothing, got %d files", len(empty.Files))
+	}
+}
+
+func TestSeedReportSuppliesFanInForChangedFiles(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"lib.go":    "package p\n\nfunc Shared() int { return 1 }\n",
+		"a.go":      "package p\n\nfunc A() int { return Shared() }\n",
+		"b.go":      "package p\n\nfunc B() int { return Shared() + Helper() }\n",
+		"helper.go": "package p\n\nfunc Helper() int { return 2 }\n",
+	})
+
+	full := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, NoSave: true})
+	changed := []string{filepath.Join(root, "lib.go")}
+
+	fanIn := func(report *model.ProjectReport) int {
+		t.Helper()
+		if len(report.Files) != 1 {
+			t.Fatalf("expected only the changed file, got %d files", len(report.Files))
+		}
+		return findFunction(t, &report.Files[0], "Shared").FanIn
+	}
+
+	unseeded := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, OnlyPaths: changed, NoSave: true})
+	if got := fanIn(unseeded); got != 0 {
+		t.Fatalf("without a seed the subset cannot see callers, got fan-in %d", got)
+	}
+
+	seeded := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, OnlyPaths: changed, NoSave: true, SeedReport: full})
+	if got := fanIn(seeded); got != 2 {
+		t.Fatalf("expected fan-in 2 from the seed report's callers, got %d", got)
+	}
+	for _, f := range full.Files {
+		if filepath.Base(f.Path) == "lib.go" && f.Functions[0].FanIn != 2 {
+			t.Fatalf("seed report fan-in changed to %d", f.Functions[0].FanIn)
+		}
+		if filepath.Base(f.Path) == "helper.go" && f.Functions[0].FanIn != 1 {
+			t.Fatalf("seed report fan-in for Helper changed to %d", f.Functions[0].FanIn)
+		}
+	}
+
+	callers := analyzeTree(t, usecase.AnalyzeProjectRequest{
+		RootPath:   root,
+		OnlyPaths:  []string{filepath.Join(root, "b.go")},
+		NoSave:     true,
+		SeedReport: full,
+	})
+	fn := findFunction(t, &callers.Files[0], "B")
+	if fn.InternalFanOut != 2 || fn.ExternalFanOut != 0 {
+		t.Fatalf("expected seeded callees to count as internal, got internal %d / external %d", fn.InternalFanOut, fn.ExternalFanOut)
+	}
+}
+
+func TestCoChangePairsFromHistory(t *testing.T) {
+	root := initRepo(t)
+	commitFiles(t, root, "alice", map[string]string{
+		"api.go":    "package p\n\nfunc API() {}\n",
+		"store.go":  "package p\n\nfunc Store() {}\n",
+		"alone.go":  "package p\n\nfunc Alone() {}\n",
+		"notes.txt": "v1\n",
+	}, "Initial commit")
+	for i := 2; i <= 3; i++ {
+		body := fmt.Sprintf("\n\nfunc V%d() {}\n", i)
+		commitFiles(t, root, "alice", map[string]string{
+			"api.go":    "package p\n\nfunc API() {}" + body,
+			"store.go":  "package p\n\nfunc Store() {}" + body,
+			"notes.txt": fmt.Sprintf("v%d\n", i),
+		}, fmt.Sprintf("Change %d", i))
+	}
+	commitFiles(t, root, "bob", map[string]string{
+		"alone.go": "package p\n\nfunc Alone() {\n\tprintln(1)\n}\n",
+		"api.go":   "package p\n\nfunc API() {\n\tprintln(1)\n}\n",
+	}, "Touch alone")
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	if len(report.CoChanges) != 0 {
+		t.Fatalf("co-change analysis must be opt-in, got %+v", report.CoChanges)
+	}
+
+	report = analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, CoChange: true})
+	if len(report.CoChanges) != 2 {
+		t.Fatalf("expected two pairs committed together at least twice, got %+v", report.CoChanges)
+	}
+	top := report.CoChanges[0]
+	if top.FileA != "api.go" || top.FileB != "store.go" || top.Commits != 3 || top.Degree != 0.75 {
+		t.Fatalf("unexpected top pair: %+v", top)
+	}
+	if second := report.CoChanges[1]; second.FileA != "alone.go" || second.FileB != "api.go" || second.Commits != 2 {
+		t.Fatalf("unexpected second pair: %+v", second)
+	}
+}
+
+func TestHotspotFormulaRanksFiles(t *testing.T) {
+	root := initRepo(t)
+
+	commitFiles(t, root, "alice", map[string]string{
+		"complex.go": `package a
+
+func Complex(x int) int {
+	if x > 0 {
+		for i := 0; i < x; i++ {
+			if i%2 == 0 && x > 10 {
+				return i
+			}
+		}
+	}
+	return 0
+}
+`,
+		"simple.go": "package a\n\nfunc Simple() int { return 1 }\n",
+	}, "Add files")
+	for i, author := range []string{"bob", "carol", "dave"} {
+		commitFiles(t, root, author, map[string]string{
+			"simple.go": fmt.Sprintf("package a\n\nfunc Simple() int { return %d }\n", i+2),
+		}, "Bump simple")
+	}
+
+	first := func(formula string) (string, string) {
+		t.Helper()
+		report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, HotspotFormula: formula})
+		if len(report.Hotspots) == 0 {
+			t.Fatalf("%s: no hotspots", formula)
+		}
+		return filepath.Base(report.Hotspots[0].FilePath), report.Hotspots[0].Reason
+	}
+
+	if top, reason := first(""); top != "complex.go" || reason != "complexity × churn" {
+		t.Fatalf("default formula: expected complex.go first, got %s (%s)", top, reason)
+	}
+	if top, reason := first("commits * authors"); top != "simple.go" || reason != "commits * authors" {
+		t.Fatalf("commits*authors: expected simple.go first, got %s (%s)", top, reason)
+	}
+	if top, _ := first("max(ccn, 1) * pow(churn, 0.5) - smells"); top != "complex.go" {
+		t.Fatalf("custom formula: expected complex.go first, got %s", top)
+	}
+
+	for _, bad := range []string{"ccn *", "ccn * lines", "exec(ccn)", "log1p(ccn, churn)", `"ccn"`, "ccn % 2"} {
+		if _, err := usecase.ParseHotspotFormula(bad); err == nil {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestCloneRemoteRepositoryForAnalysis(t *testing.T) {
+	src := initRepo(t)
+	commitFiles(t, src, "alice", map[string]string{"a.go": "package a\n\nfunc A() int { return 1 }\n"}, "Add a")
+	runGit(t, src, "branch", "-M", "main")
+	runGit(t, src, "checkout", "-q", "-b", "feature")
+	commitFiles(t, src, "bob", map[string]string{"b.go": "package a\n\nfunc B() int { return 2 }\n"}, "Add b")
+	commitFiles(t, src, "bob", map[string]string{"b.go": "package a\n\nfunc B() int { return 3 }\n"}, "Tweak b")
+
+	bare := filepath.Join(t.TempDir(), "remote.git")
+	runGit(t, src, "clone", "-q", "--bare", src, bare)
+	url := "file://" + filepath.ToSlash(bare)
+
+	git := gitadapter.NewGitCLI()
+	ctx := context.Background()
+
+	dest := filepath.Join(t.TempDir(), "checkout")
+	if err := git.Clone(ctx, url, "feature", 0, dest); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: dest, NoSave: true})
+	if len(report.Files) != 2 {
+		t.Fatalf("expected both files from the feature branch, got %d", len(report.Files))
+	}
+	for _, f := range report.Files {
+		if f.Git == nil {
+			t.Fatalf("%s: git metrics should come from the cloned history", f.Path)
+		}
+		if filepath.Base(f.Path) == "b.go" && f.Git.Commits != 2 {
+			t.Fatalf("b.go: expected 2 commits in the cloned history, got %d", f.Git.Commits)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dest, ".codeaudit")); !os.IsNotExist(err) {
+		t.Fatalf("NoSave analysis must not write into the clone")
+	}
+
+	shallow := filepath.Join(t.TempDir(), "shallow")
+	if err := git.Clone(ctx, url, "feature", 1, shallow); err != nil {
+		t.Fatalf("shallow clone: %v", err)
+	}
+	if count := runGit(t, shallow, "rev-list", "--count", "HEAD"); count != "1\n" {
+		t.Fatalf("depth 1 clone should hold one commit, got %q", count)
+	}
+
+	err := git.Clone(ctx, url, "no-such-branch", 1, filepath.Join(t.TempDir(), "missing"))
+	if err == nil {
+		t.Fatalf("expected cloning an unknown revision to fail")
+	}
+}
+
+func TestHotspotAuthorWeighting(t *testing.T) {
+	root := initRepo(t)
+
+	body := func(n int) string {
+		return fmt.Sprintf("package a\n\nfunc F%%s(x int) int {\n\tif x > %d {\n\t\treturn x\n\t}\n\treturn 0\n}\n", n)
+	}
+	commitFiles(t, root, "alice", map[string]string{
+		"solo.go": fmt.Sprintf(body(0), "Solo"),
+		"team.go": fmt.Sprintf(body(0), "Team"),
+	}, "Add files")
+	for i, author := range []string{"bob", "carol"} {
+		commitFiles(t, root, "alice", map[string]string{"solo.go": fmt.Sprintf(body(i+1), "Solo")}, "Tweak solo")
+		commitFiles(t, root, author, map[string]string{"team.go": fmt.Sprintf(body(i+1), "Team")}, "Tweak team")
+	}
+
+	scores := func(req usecase.AnalyzeProjectRequest) map[string]float64 {
+		t.Helper()
+		req.RootPath = root
+		report := analyzeTree(t, req)
+		out := map[string]float64{}
+		for _, h := range report.Hotspots {
+			out[filepath.Base(h.FilePath)] = h.Score
+		}
+		if len(out) != 2 {
+			t.Fatalf("expected both files as hotspots, got %v", out)
+		}
+		return out
+	}
+
+	plain := scores(usecase.AnalyzeProjectRequest{HotspotModel: usecase.HotspotModelComplexityChurn})
+	if plain["solo.go"] != plain["team.go"] {
+		t.Fatalf("files differing only in authors should tie without author weighting, got %v", plain)
+	}
+
+	weighted := scores(usecase.AnalyzeProjectRequest{HotspotModel: usecase.HotspotModelAuthors})
+	wantRatio := (1 + math.Log1p(3)) / (1 + math.Log1p(1))
+	if got := weighted["team.go"] / weighted["solo.go"]; math.Abs(got-wantRatio) > 1e-9 {
+		t.Fatalf("expected team.go to outrank solo.go by %.4f, got %.4f (%v)", wantRatio, got, weighted)
+	}
+	if math.Abs(weighted["solo.go"]-plain["solo.go"]*(1+math.Log1p(1))) > 1e-9 {
+		t.Fatalf("expected the author factor 1+log1p(authors) on top of the default score, got %v vs %v", weighted, plain)
+	}
+
+	uc := usecase.NewAnalyzeProjectUseCase(infrastructure.NewFSScanner(), infrastructure.NewFSScanner(), nil, noGitClient{}, &memStorage{}, 1)
+	if _, err := uc.Execute(context.Background(), usecase.AnalyzeProjectRequest{RootPath: root, HotspotModel: "bus-factor"}); err == nil {
+		t.Fatalf("expected an unknown hotspot model to be rejected")
+	}
+	if _, err := uc.Execute(context.Background(), usecase.AnalyzeProjectRequest{
+		RootPath: root, HotspotModel: usecase.HotspotModelAuthors, HotspotFormula: "ccn",
+	}); err == nil {
+		t.Fatalf("expected --hotspot-model and a custom formula to be mutually exclusive")
+	}
+}
@@ -0,0 +1,932 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	outputadapter "github.com/rafaelvolkmer/codeaudit/internal/adapter/output"
+	parser "github.com/rafaelvolkmer/codeaudit/internal/adapter/parser"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+	"github.com/rafaelvolkmer/codeaudit/internal/infrastructure"
+	"github.com/rafaelvolkmer/codeaudit/internal/usecase"
+)
+
+func parseGo(t *testing.T, p *parser.GoParser, src string) *model.FileMetrics {
+	t.Helper()
+	fm, err := p.ParseFile("fixture.go", []byte(src))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return fm
+}
+
+func findFunction(t *testing.T, fm *model.FileMetrics, name string) model.FunctionMetrics {
+	t.Helper()
+	for _, fn := range fm.Functions {
+		if fn.Name == name {
+			return fn
+		}
+	}
+	t.Fatalf("function %q not found in %s", name, fm.Path)
+	return model.FunctionMetrics{}
+}
+
+func smellsOfKind(fm *model.FileMetrics, kind model.CodeSmellKind) []model.CodeSmell {
+	var out []model.CodeSmell
+	for _, s := range fm.Smells {
+		if s.Kind == kind {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+const shadowFixture = `package fixture
+
+import "errors"
+
+func Shadowing(x int) (int, error) {
+	total := 0
+	if x > 0 {
+		total := x * 2
+		_ = total
+	}
+	for i := 0; i < 3; i++ {
+		x := i
+		_ = x
+	}
+	err := errors.New("a")
+	if true {
+		_, err := Clean(1)
+		_ = err
+	}
+	return total, err
+}
+
+func Clean(n int) (int, error) {
+	v, err := n+1, error(nil)
+	if err != nil {
+		return 0, err
+	}
+	v, err = v+1, nil
+	return v, err
+}
+`
+
+func TestGoShadowedVariables(t *testing.T) {
+	fm := parseGo(t, parser.NewGoParser(), shadowFixture)
+
+	if got := findFunction(t, fm, "Shadowing").ShadowedVariables; got != 2 {
+		t.Fatalf("expected 2 shadow events ignoring err, got %d", got)
+	}
+	if got := findFunction(t, fm, "Clean").ShadowedVariables; got != 0 {
+		t.Fatalf("re-assignment is not shadowing, got %d events", got)
+	}
+
+	smells := smellsOfKind(fm, model.SmellShadowedVariable)
+	if len(smells) != 2 || smells[0].Line != 8 || smells[1].Line != 12 {
+		t.Fatalf("unexpected shadow smells: %+v", smells)
+	}
+
+	fm = parseGo(t, parser.NewGoParserWithOptions(parser.GoParserOptions{ReportErrShadowing: true}), shadowFixture)
+	if got := findFunction(t, fm, "Shadowing").ShadowedVariables; got != 3 {
+		t.Fatalf("expected err shadowing to be reported when enabled, got %d", got)
+	}
+}
+
+func TestGoBodylessFunctionsCountTowardPublicAPI(t *testing.T) {
+	src := `package fixture
+
+// Store persists values.
+type Store interface {
+	Get(key string) string
+	Put(key, value string)
+}
+
+// Add is implemented in assembly.
+func Add(a, b int) int
+
+func Sub(a, b int) int
+
+func mul(a, b int) int
+
+// Local has a body.
+func Local() int { return 1 }
+`
+	fm := parseGo(t, parser.NewGoParser(), src)
+
+	if len(fm.Functions) != 1 || fm.Summary.FunctionsCount != 1 {
+		t.Fatalf("expected only Local in the function metrics, got %+v", fm.Functions)
+	}
+	names := make(map[string]model.FunctionMetrics)
+	for _, fn := range fm.ExternalFunctions {
+		names[fn.Name] = fn
+	}
+	if _, ok := names["Get"]; ok {
+		t.Fatalf("interface methods must not be recorded as functions")
+	}
+	if _, ok := names["mul"]; ok {
+		t.Fatalf("unexported body-less functions should be skipped")
+	}
+
+	add, ok := names["Add"]
+	if !ok || !add.IsExternal || add.CCN != 0 || !add.IsDocumented || add.Parameters != 2 {
+		t.Fatalf("unexpected entry for Add: %+v", add)
+	}
+	if sub := names["Sub"]; !sub.IsExternal || sub.IsDocumented {
+		t.Fatalf("unexpected entry for Sub: %+v", sub)
+	}
+
+	if fm.Comments.PublicSymbols != 4 || fm.Comments.PublicDocumented != 3 {
+		t.Fatalf("expected 4 public symbols / 3 documented, got %d / %d",
+			fm.Comments.PublicSymbols, fm.Comments.PublicDocumented)
+	}
+
+	report, err := usecase.AnalyzeSources(context.Background(), map[string][]byte{"fixture.go": []byte(src)},
+		[]ports.CodeParser{parser.NewGoParser()}, usecase.AnalyzeProjectRequest{IncludeExt: []string{".go"}})
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if p := report.Project; p.TotalFunctions != 1 || p.AvgCCNPerFunction != 1 {
+		t.Fatalf("external functions must not dilute project metrics, got %d functions / avg CCN %.2f", p.TotalFunctions, p.AvgCCNPerFunction)
+	}
+}
+
+func TestCognitiveModelsOnSameSnippet(t *testing.T) {
+	src := `package p
+
+func Sum(items []int, limit int) int {
+	total := 0
+	for _, it := range items {
+		if it > limit && limit > 0 {
+			continue
+		} else if it < 0 {
+			total -= it
+		} else {
+			total += it
+		}
+	}
+	return total
+}
+`
+	line := findFunction(t, parseGo(t, parser.NewGoParser(), src), "Sum")
+	explicitLine := findFunction(t, parseGo(t, parser.NewGoParserWithOptions(parser.GoParserOptions{
+		CognitiveModel: parser.CognitiveModelLine,
+	}), src), "Sum")
+	sonar := findFunction(t, parseGo(t, parser.NewGoParserWithOptions(parser.GoParserOptions{
+		CognitiveModel: parser.CognitiveModelSonar,
+	}), src), "Sum")
+
+	if line.CognitiveComplexity != explicitLine.CognitiveComplexity {
+		t.Fatalf("line model should be the default: %d vs %d", line.CognitiveComplexity, explicitLine.CognitiveComplexity)
+	}
+	if sonar.CognitiveComplexity != 6 {
+		t.Fatalf("expected sonar cognitive 6, got %d", sonar.CognitiveComplexity)
+	}
+	if line.CognitiveComplexity == sonar.CognitiveComplexity {
+		t.Fatalf("models should disagree on this snippet, both gave %d", line.CognitiveComplexity)
+	}
+	if line.CCN != sonar.CCN {
+		t.Fatalf("cognitive model must not change CCN: %d vs %d", line.CCN, sonar.CCN)
+	}
+}
+
+func TestUnknownCognitiveModelRejected(t *testing.T) {
+	if err := parser.ValidateCognitiveModel("halstead"); err == nil {
+		t.Fatalf("expected error for unknown model")
+	}
+}
+
+func TestCognitiveLineCapAndGeneratedCode(t *testing.T) {
+	body := `
+func Marshal(v map[string][]int) int {
+	n := 0
+	for k, list := range v {
+		if k != "" {
+			for _, x := range list {
+				if x > 0 {
+					switch x {
+					case 1:
+						n++
+					}
+				}
+			}
+		}
+	}
+	return n
+}
+`
+	src := "package p\n" + body
+	const limit = 3
+
+	uncapped := findFunction(t, parseGo(t, parser.NewGoParser(), src), "Marshal")
+	capped := findFunction(t, parseGo(t, parser.NewGoParserWithOptions(parser.GoParserOptions{
+		CognitiveLineCap: limit,
+	}), src), "Marshal")
+
+	exp, err := parser.NewGoParser().ExplainFunction("fixture.go", []byte(src), "Marshal")
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	want := 0
+	for _, l := range exp.Lines {
+		want += min(l.Cognitive, limit)
+	}
+	if capped.CognitiveComplexity != want || capped.CognitiveComplexity >= uncapped.CognitiveComplexity {
+		t.Fatalf("expected capped cognitive %d (< uncapped %d), got %d", want, uncapped.CognitiveComplexity, capped.CognitiveComplexity)
+	}
+	if capped.CCN != uncapped.CCN {
+		t.Fatalf("the cap must not change CCN: %d vs %d", capped.CCN, uncapped.CCN)
+	}
+
+	generated := "// Code generated by marshalgen. DO NOT EDIT.\n\npackage p\n" + body
+	skip := parser.NewGoParserWithOptions(parser.GoParserOptions{SkipGeneratedCognitive: true})
+	if fn := findFunction(t, parseGo(t, skip, generated), "Marshal"); fn.CognitiveComplexity != 0 || fn.CCN != uncapped.CCN {
+		t.Fatalf("generated file: expected cognitive 0 and CCN kept, got %d / %d", fn.CognitiveComplexity, fn.CCN)
+	}
+	if fn := findFunction(t, parseGo(t, skip, src), "Marshal"); fn.CognitiveComplexity != uncapped.CognitiveComplexity {
+		t.Fatalf("hand-written file must keep its cognitive score, got %d", fn.CognitiveComplexity)
+	}
+
+	cSrc := "int walk(int *v, int n) {\n\tfor (int i = 0; i < n; i++) {\n\t\tif (v[i]) {\n\t\t\tif (v[i] > 1 && v[i] < 9) {\n\t\t\t\treturn i;\n\t\t\t}\n\t\t}\n\t}\n\treturn -1;\n}\n"
+	cUncapped := parseC(t, "walk.c", cSrc).Functions[0]
+	cCapped, err := parser.NewCParserWithOptions(parser.CParserOptions{CognitiveLineCap: 1}).ParseFile("walk.c", []byte(cSrc))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	cExp, err := parser.NewCParser().ExplainFunction("walk.c", []byte(cSrc), "walk")
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	if got := cCapped.Functions[0].CognitiveComplexity; got >= cUncapped.CognitiveComplexity || got != len(cExp.Lines) {
+		t.Fatalf("C cap of 1 should score one point per contributing line: uncapped %d, capped %d, lines %d",
+			cUncapped.CognitiveComplexity, got, len(cExp.Lines))
+	}
+}
+
+func TestLongestFunctionPerFile(t *testing.T) {
+	src := `package p
+
+func Short() {}
+
+func First() int {
+	a := 1
+	b := 2
+	return a + b
+}
+
+func Second() int {
+	c := 3
+	d := 4
+	return c + d
+}
+`
+	fm := parseGo(t, parser.NewGoParser(), src)
+	if fm.Summary.LongestFunctionName != "First" {
+		t.Fatalf("expected the earlier of two equal-length functions, got %q", fm.Summary.LongestFunctionName)
+	}
+	if fm.Summary.LongestFunctionNLOC != findFunction(t, fm, "First").NLOC {
+		t.Fatalf("longest NLOC %d does not match First", fm.Summary.LongestFunctionNLOC)
+	}
+
+	c, err := parser.NewCParser().ParseFile("x.c", []byte("int a(void) {\n\treturn 0;\n}\n\nint b(int x) {\n\tx++;\n\tx++;\n\treturn x;\n}\n"))
+	if err != nil {
+		t.Fatalf("parse c: %v", err)
+	}
+	if c.Summary.LongestFunctionName != "b" {
+		t.Fatalf("expected b to be the longest C function, got %q", c.Summary.LongestFunctionName)
+	}
+
+	out, err := outputadapter.NewTextRenderer().Render(&model.ProjectReport{Files: []model.FileMetrics{*fm}})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(stripANSI(out), fmt.Sprintf("longest=First (%d)", fm.Summary.LongestFunctionNLOC)) {
+		t.Fatalf("longest function missing from text output:\n%s", stripANSI(out))
+	}
+}
+
+func TestGoFunctionCommentDensityUsesASTComments(t *testing.T) {
+	src := `package p
+
+// Sum adds numbers.
+// It is documented.
+func Sum(a, b int) int {
+	// add them
+	return a + b
+}
+
+func Bare() int {
+	return 1
+}
+`
+	fm := parseGo(t, parser.NewGoParser(), src)
+
+	sum := findFunction(t, fm, "Sum")
+	lineBased := 1.0 / float64(sum.NLOC+1)
+	astBased := 3.0 / float64(sum.NLOC+3)
+	if sum.CommentDensity != astBased {
+		t.Fatalf("expected doc and inline comments to count (%.3f), got %.3f (line-based would be %.3f)",
+			astBased, sum.CommentDensity, lineBased)
+	}
+	if bare := findFunction(t, fm, "Bare"); bare.CommentDensity != 0 {
+		t.Fatalf("comments of Sum leaked into Bare: %.3f", bare.CommentDensity)
+	}
+}
+
+func TestGoPackageNameCaptured(t *testing.T) {
+	fm := parseGo(t, parser.NewGoParser(), "package widgets\n\nfunc New() {}\n")
+	if fm.Package != "widgets" {
+		t.Fatalf("expected package widgets, got %q", fm.Package)
+	}
+
+	out, err := outputadapter.NewJSONRenderer().Render(&model.ProjectReport{Files: []model.FileMetrics{*fm}})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(out, `"package": "widgets"`) {
+		t.Fatalf("package not serialized:\n%s", out)
+	}
+}
+
+func TestGoSwitchWithoutDefault(t *testing.T) {
+	src := `package fixture
+
+func Kinds(x int, v any) string {
+	switch x {
+	case 1:
+		return "one"
+	}
+	switch v.(type) {
+	case string:
+		return "str"
+	default:
+	}
+	switch t := v.(type) {
+	case int:
+		_ = t
+	}
+	switch {
+	default:
+		return "other"
+	case x > 2:
+		return "big"
+	}
+}
+`
+	fm := parseGo(t, parser.NewGoParser(), src)
+	if n := len(smellsOfKind(fm, model.SmellMissingDefault)); n != 0 {
+		t.Fatalf("missing-default check must be opt-in, got %d smells", n)
+	}
+
+	fm = parseGo(t, parser.NewGoParserWithOptions(parser.GoParserOptions{MissingDefault: true}), src)
+	smells := smellsOfKind(fm, model.SmellMissingDefault)
+	if len(smells) != 2 || smells[0].Line != 4 || smells[1].Line != 13 {
+		t.Fatalf("expected smells on lines 4 and 13, got %+v", smells)
+	}
+	if smells[0].Function != "Kinds" || !strings.HasPrefix(smells[1].Description, "type switch") {
+		t.Fatalf("unexpected smell details: %+v", smells)
+	}
+}
+
+func TestGoGlobalMutationSeparatesReadsFromWrites(t *testing.T) {
+	src := `package fixture
+
+var counter int
+
+var registry = map[string]int{}
+
+func Increment() {
+	counter++
+}
+
+func Register(name string) {
+	registry[name] = counter
+}
+
+func Read() int {
+	return counter + len(registry)
+}
+
+func Local() int {
+	counter := 1
+	counter++
+	return counter
+}
+
+func Lock() {
+	mu.Lock()
+	defer mu.Unlock()
+}
+
+var mu sync.Mutex
+`
+	fm := parseGo(t, parser.NewGoParser(), src)
+
+	for _, tc := range []struct {
+		name          string
+		reads, writes int
+	}{
+		{"Increment", 0, 1},
+		{"Register", 1, 1},
+		{"Read", 2, 0},
+		{"Local", 0, 0},
+		{"Lock", 2, 0},
+	} {
+		fn := findFunction(t, fm, tc.name)
+		if fn.GlobalReads != tc.reads || fn.GlobalWrites != tc.writes {
+			t.Fatalf("%s: expected %d reads / %d writes, got %d / %d",
+				tc.name, tc.reads, tc.writes, fn.GlobalReads, fn.GlobalWrites)
+		}
+	}
+
+	smells := smellsOfKind(fm, model.SmellGlobalMutation)
+	if len(smells) != 2 || smells[0].Function != "Increment" || smells[0].Line != 8 ||
+		smells[1].Function != "Register" || !strings.Contains(smells[1].Description, "registry") {
+		t.Fatalf("unexpected global mutation smells: %+v", smells)
+	}
+}
+
+func TestGoGlobalMutationAcrossPackageFiles(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"state/b.go": "package state\n\nvar counter int\n",
+		"state/a.go": `package state
+
+type S struct{ counter int }
+
+func Inc() {
+	counter++
+}
+
+func Get() int {
+	return counter
+}
+
+func (s *S) Set() {
+	s.counter = 1
+}
+`,
+		"other/c.go": "package other\n\nfunc Bump() {\n\tcounter++\n}\n",
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	var smells []model.CodeSmell
+	fns := map[string]model.FunctionMetrics{}
+	for _, f := range report.Files {
+		smells = append(smells, smellsOfKind(&f, model.SmellGlobalMutation)...)
+		for _, fn := range f.Functions {
+			fns[fn.Name] = fn
+		}
+	}
+	if len(smells) != 1 || smells[0].Function != "Inc" || smells[0].Line != 6 || !strings.Contains(smells[0].Description, "counter") {
+		t.Fatalf("expected Inc to mutate counter declared in b.go, got %+v", smells)
+	}
+	set, ok := fns["Set"]
+	if !ok || fns["Inc"].GlobalWrites != 1 || fns["Get"].GlobalReads != 1 || set.GlobalWrites != 0 {
+		t.Fatalf("unexpected global access counts: Inc %+v, Get %+v, Set %+v", fns["Inc"], fns["Get"], set)
+	}
+}
+
+func TestGoNolintDirectivesSuppressSmells(t *testing.T) {
+	src := `package fixture
+
+var state int
+
+// Deep is intentionally nested.
+//
+//nolint:deep_nesting // tracked elsewhere
+func Deep(alpha, beta, gamma, delta, eps int) int {
+	state++
+	if alpha > 0 {
+		if beta > 0 {
+			if gamma > 0 {
+				if delta > 0 {
+					return eps
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func All(alpha, beta, gamma, delta, eps int) { //nolint:codeaudit
+	state++
+}
+
+//nolint:gocyclo
+func Other(alpha, beta, gamma, delta, eps int) {}
+
+//lint:ignore many_parameters
+func Custom(alpha, beta, gamma, delta, eps int) {}
+`
+	kinds := func(fm *model.FileMetrics, fn string) []string {
+		var out []string
+		for _, s := range fm.Smells {
+			if s.Function == fn {
+				out = append(out, string(s.Kind))
+			}
+		}
+		return out
+	}
+
+	fm := parseGo(t, parser.NewGoParser(), src)
+	if got := kinds(fm, "Deep"); strings.Join(got, ",") != "many_parameters,global_mutation" {
+		t.Fatalf("Deep: only deep_nesting should be suppressed, got %v", got)
+	}
+	if got := kinds(fm, "All"); len(got) != 0 {
+		t.Fatalf("All: //nolint:codeaudit should suppress everything, got %v", got)
+	}
+	if got := kinds(fm, "Other"); len(got) != 1 || got[0] != "many_parameters" {
+		t.Fatalf("Other: unrelated linters must not suppress smells, got %v", got)
+	}
+	if got := kinds(fm, "Custom"); len(got) != 1 {
+		t.Fatalf("Custom: directive with another prefix is ignored by default, got %v", got)
+	}
+
+	fm = parseGo(t, parser.NewGoParserWithOptions(parser.GoParserOptions{NolintPrefix: "lint:ignore"}), src)
+	if got := kinds(fm, "Custom"); len(got) != 0 {
+		t.Fatalf("Custom: configured prefix should suppress, got %v", got)
+	}
+	if got := kinds(fm, "All"); len(got) == 0 {
+		t.Fatalf("All: default prefix no longer applies once reconfigured")
+	}
+}
+
+func TestGoNolintIsScopedToTheAnnotatedMethod(t *testing.T) {
+	src := `package fixture
+
+type A struct{}
+type B struct{}
+
+//nolint:many_parameters
+func (A) Close(alpha, beta, gamma, delta, eps int) {}
+
+func (B) Close(alpha, beta, gamma, delta, eps int) {}
+`
+	fm := parseGo(t, parser.NewGoParser(), src)
+	smells := smellsOfKind(fm, model.SmellManyParameters)
+	if len(smells) != 1 || smells[0].Line != 9 {
+		t.Fatalf("expected only B.Close (line 9) to keep its smell, got %+v", smells)
+	}
+}
+
+func TestGoNolintSuppressesUseCaseSmells(t *testing.T) {
+	body := strings.Repeat("\tx++\n", 30)
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"long.go": "package long\n\n" +
+		"//nolint:long_function\nfunc Quiet(x int) int {\n" + body + "\treturn x\n}\n\n" +
+		"func Silent(x int) int { //nolint:codeaudit\n" + body + "\treturn x\n}\n\n" +
+		"//nolint:deep_nesting\nfunc Loud(x int) int {\n" + body + "\treturn x\n}\n",
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{
+		RootPath: root,
+		Smells:   usecase.SmellConfig{MaxFunctionNLOC: 20, MaxSurfaceArea: 1},
+	})
+	byFunction := map[string][]string{}
+	for _, s := range report.Files[0].Smells {
+		byFunction[s.Function] = append(byFunction[s.Function], string(s.Kind))
+	}
+	if got := strings.Join(byFunction["Quiet"], ","); got != "large_surface_area" {
+		t.Fatalf("Quiet: expected only long_function suppressed, got %v", got)
+	}
+	if got := byFunction["Silent"]; len(got) != 0 {
+		t.Fatalf("Silent: //nolint:codeaudit should suppress use-case smells too, got %v", got)
+	}
+	if got := strings.Join(byFunction["Loud"], ","); got != "long_function,large_surface_area" {
+		t.Fatalf("Loud: an unrelated directive must not suppress, got %v", got)
+	}
+}
+
+func TestExplainBreakdownSumsToReportedCCN(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.go": `package a
+
+func F(a, b int) int {
+	if a > 0 && b > 0 {
+		for i := 0; i < a; i++ {
+			switch i {
+			case 1, 2:
+				return 1
+			default:
+			}
+		}
+	} else if b < 0 {
+		return -1
+	}
+	go func() {
+		if a > 1 {
+			return
+		}
+	}()
+	return 0
+}
+`,
+		"b.c":   "int f(int a) {\n\tif (a && a > 1) {\n\t\treturn a ? 1 : 2;\n\t}\n\twhile (a--) {\n\t}\n\treturn 0;\n}\n",
+		"c.cpp": "int Box::open(int a) {\n\ttry {\n\t\tif (a > 0 || a < -9) {\n\t\t\treturn 1;\n\t\t}\n\t} catch (...) {\n\t}\n\tauto f = [](int x) { return x ? 1 : 0; };\n\treturn f(a);\n}\n",
+	})
+
+	parsers := []ports.CodeParser{parser.NewGoParser(), parser.NewCppParser(), parser.NewCParser()}
+	uc := usecase.NewExplainFunctionUseCase(infrastructure.NewFSScanner(), parsers)
+
+	for _, tc := range []struct{ file, function string }{{"a.go", "F"}, {"b.c", "f"}, {"c.cpp", "Box::open"}} {
+		path := filepath.Join(root, tc.file)
+		req, err := usecase.ParseExplainTarget(path + ":" + tc.function)
+		if err != nil {
+			t.Fatalf("parse target: %v", err)
+		}
+		exp, err := uc.Execute(context.Background(), req)
+		if err != nil {
+			t.Fatalf("explain %s: %v", tc.file, err)
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fm *model.FileMetrics
+		for _, p := range parsers {
+			if p.SupportsFile(path) {
+				fm, err = p.ParseFile(path, src)
+				if err != nil {
+					t.Fatal(err)
+				}
+				break
+			}
+		}
+		fn := findFunction(t, fm, tc.function)
+
+		sumCCN, sumCog := exp.BaseCCN, 0
+		for _, l := range exp.Lines {
+			sumCCN += l.CCN
+			sumCog += l.Cognitive
+		}
+		if sumCCN != fn.CCN || exp.CCN != fn.CCN {
+			t.Fatalf("%s: breakdown sums to CCN %d (explained %d), reported %d: %+v", tc.file, sumCCN, exp.CCN, fn.CCN, exp.Lines)
+		}
+		if sumCog != fn.CognitiveComplexity {
+			t.Fatalf("%s: breakdown sums to cognitive %d, reported %d", tc.file, sumCog, fn.CognitiveComplexity)
+		}
+	}
+
+	overridden, err := parser.Configure(parsers, parser.Config{Extensions: map[string][]string{"go": {".go"}}})
+	if err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	exp, err := usecase.NewExplainFunctionUseCase(infrastructure.NewFSScanner(), overridden).
+		Execute(context.Background(), usecase.ExplainFunctionRequest{Path: filepath.Join(root, "a.go"), Function: "F"})
+	if err != nil || exp.CCN == 0 {
+		t.Fatalf("expected --parser-ext to keep the parser explainable, got %+v (%v)", exp, err)
+	}
+
+	if _, err := usecase.ParseExplainTarget("no-function"); err == nil {
+		t.Fatalf("expected an error for a target without :function")
+	}
+}
+
+func TestGoIdentifierLengthAndCrypticNames(t *testing.T) {
+	src := `package a
+
+type Store struct{}
+
+func (s *Store) Verbose(customerID string, orderTotal int) int {
+	discountRate := 3
+	for i := 0; i < orderTotal; i++ {
+		discountRate += len(customerID)
+	}
+	return discountRate
+}
+
+func Terse(a, b int) int {
+	x := a * b
+	for i, v := range []int{a, b} {
+		x += i * v
+	}
+	const q = 2
+	return x / q
+}
+`
+	fm := parseGo(t, parser.NewGoParser(), src)
+
+	verbose := findFunction(t, fm, "Verbose")
+	if verbose.ShortIdentifiers != 0 || verbose.AvgIdentifierLength < 8 {
+		t.Fatalf("Verbose: expected long names and no short ones (receiver and i excluded), got avg %.2f short %d",
+			verbose.AvgIdentifierLength, verbose.ShortIdentifiers)
+	}
+
+	terse := findFunction(t, fm, "Terse")
+	if terse.ShortIdentifiers != 5 || terse.AvgIdentifierLength != 1 {
+		t.Fatalf("Terse: expected 5 short names averaging 1 char, got avg %.2f short %d",
+			terse.AvgIdentifierLength, terse.ShortIdentifiers)
+	}
+
+	cryptic := smellsOfKind(fm, model.SmellCrypticNames)
+	if len(cryptic) != 1 || cryptic[0].Function != "Terse" {
+		t.Fatalf("expected only Terse flagged for cryptic names, got %+v", cryptic)
+	}
+	if model.SmellSeverity(model.SmellCrypticNames) != model.SeverityInfo {
+		t.Fatalf("cryptic names should be an info-level note")
+	}
+}
+
+func TestGoManyReturnValues(t *testing.T) {
+	src := `package p
+
+func Pair() (int, string) { return 0, "" }
+
+func FourPlusErr() (a, b int, c string, d bool, err error) {
+	return
+}
+
+func ThreePlusErr() (int, int, int, error) { return 0, 0, 0, nil }
+
+func Five() (
+	int, int, int, int, int,
+) {
+	return 1, 2, 3, 4, 5
+}
+`
+	fm := parseGo(t, parser.NewGoParser(), src)
+
+	for name, want := range map[string]int{"Pair": 2, "FourPlusErr": 5, "ThreePlusErr": 4, "Five": 5} {
+		if got := findFunction(t, fm, name).ReturnValueCount; got != want {
+			t.Fatalf("%s: expected %d return values, got %d", name, want, got)
+		}
+	}
+
+	flagged := map[string]model.CodeSmell{}
+	for _, s := range smellsOfKind(fm, model.SmellManyReturns) {
+		flagged[s.Function] = s
+	}
+	if len(flagged) != 2 || flagged["Five"].Line != 11 || flagged["FourPlusErr"].Line != 5 {
+		t.Fatalf("expected Five (line 11) and FourPlusErr (line 5) flagged, got %+v", flagged)
+	}
+	if !strings.Contains(flagged["FourPlusErr"].Description, "plus an error") {
+		t.Fatalf("description should mention the trailing error: %q", flagged["FourPlusErr"].Description)
+	}
+
+	lenient := parseGo(t, parser.NewGoParserWithOptions(parser.GoParserOptions{MaxReturnValues: 5}), src)
+	if got := smellsOfKind(lenient, model.SmellManyReturns); len(got) != 0 {
+		t.Fatalf("with a limit of 5 nothing should be flagged, got %+v", got)
+	}
+}
+
+func TestGoFunctionRoles(t *testing.T) {
+	src := `package main
+
+import "testing"
+
+func init() {}
+
+func main() {}
+
+func helper() {}
+
+type suite struct{}
+
+func (suite) TestMethod(t *testing.T) {}
+
+func TestMain(m *testing.M) {}
+
+func TestParse(t *testing.T) {
+	check := func() {}
+	check()
+}
+
+func Testify(t *testing.T) {}
+
+func BenchmarkParse(b *testing.B) {}
+
+func FuzzParse(f *testing.F) {}
+
+func ExampleParse() {}
+
+func Example() {}
+
+func TestWrongSignature(x int) {}
+`
+	fm, err := parser.NewGoParser().ParseFile("roles_test.go", []byte(src))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	want := map[string]model.FunctionRole{
+		"init":               model.RoleInit,
+		"main":               model.RoleMain,
+		"helper":             model.RoleRegular,
+		"TestMethod":         model.RoleRegular,
+		"TestMain":           model.RoleTest,
+		"TestParse":          model.RoleTest,
+		"Testify":            model.RoleRegular,
+		"BenchmarkParse":     model.RoleBenchmark,
+		"FuzzParse":          model.RoleFuzz,
+		"ExampleParse":       model.RoleExample,
+		"Example":            model.RoleExample,
+		"TestWrongSignature": model.RoleRegular,
+	}
+	for name, role := range want {
+		if got := findFunction(t, fm, name).Role; got != role {
+			t.Fatalf("%s: expected role %q, got %q", name, role, got)
+		}
+	}
+	closures := 0
+	for _, fn := range fm.Functions {
+		if strings.HasPrefix(fn.Name, "@") {
+			closures++
+			if fn.Role != model.RoleTest {
+				t.Fatalf("closure %s in TestParse should inherit the test role, got %q", fn.Name, fn.Role)
+			}
+		}
+	}
+	if closures != 1 {
+		t.Fatalf("expected the closure in TestParse to be reported, got %d", closures)
+	}
+
+	plain, err := parser.NewGoParser().ParseFile("roles.go", []byte(src))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := findFunction(t, plain, "TestParse").Role; got != model.RoleRegular {
+		t.Fatalf("outside _test.go files TestParse is a regular function, got %q", got)
+	}
+}
+
+func TestGoErrorNotLastResult(t *testing.T) {
+	src := `package p
+
+func Bad() (error, int) { return nil, 0 }
+
+func BadNamed() (err error, n int) { return }
+
+func Good() (int, error) { return 0, nil }
+
+func OnlyErr() error { return nil }
+
+func internal() (error, int) { return nil, 0 }
+
+type T struct{}
+
+func (T) Method() (error, string) { return nil, "" }
+`
+	fm := parseGo(t, parser.NewGoParser(), src)
+
+	flagged := map[string]int{}
+	for _, s := range smellsOfKind(fm, model.SmellErrorNotLast) {
+		flagged[s.Function] = s.Line
+	}
+	if len(flagged) != 3 || flagged["Bad"] != 3 || flagged["BadNamed"] != 5 || flagged["Method"] != 15 {
+		t.Fatalf("expected Bad, BadNamed and Method flagged on their result lines, got %v", flagged)
+	}
+}
+
+func TestGoQualifiedClosureNames(t *testing.T) {
+	src := `package p
+
+type Runner struct{}
+
+func (r *Runner) Execute(items []int) {
+	each := func(i int) {
+		defer func() {
+			recover()
+		}()
+	}
+	for _, it := range items {
+		each(it)
+	}
+	go func() {}()
+}
+
+func (Runner) Name() string {
+	label := func() string { return "runner" }
+	return label()
+}
+
+func Plain() {
+	f := func() {}
+	f()
+}
+`
+	fm := parseGo(t, parser.NewGoParserWithOptions(parser.GoParserOptions{QualifiedClosureNames: true}), src)
+	var names []string
+	for _, fn := range fm.Functions {
+		names = append(names, fn.Name)
+	}
+	want := "[Execute (*Runner).Execute.func1 (*Runner).Execute.func1.1 (*Runner).Execute.func2 " +
+		"Name Runner.Name.func1 Plain Plain.func1]"
+	if fmt.Sprint(names) != want {
+		t.Fatalf("unexpected closure names:\n got %v\nwant %s", names, want)
+	}
+
+	fm = parseGo(t, parser.NewGoParser(), src)
+	findFunction(t, fm, "@6-10")
+}
@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	gitadapter "github.com/rafaelvolkmer/codeaudit/internal/adapter/git"
+	parser "github.com/rafaelvolkmer/codeaudit/internal/adapter/parser"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+	"github.com/rafaelvolkmer/codeaudit/internal/infrastructure"
+	"github.com/rafaelvolkmer/codeaudit/internal/usecase"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", rel, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+}
+
+func relPaths(t *testing.T, root string, paths []string) map[string]bool {
+	t.Helper()
+	out := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			t.Fatalf("rel %s: %v", p, err)
+		}
+		out[filepath.ToSlash(rel)] = true
+	}
+	return out
+}
+
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func stripANSI(s string) string {
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+func analyzeTree(t *testing.T, req usecase.AnalyzeProjectRequest) *model.ProjectReport {
+	t.Helper()
+
+	scanner := infrastructure.NewFSScanner()
+	uc := usecase.NewAnalyzeProjectUseCase(
+		scanner,
+		scanner,
+		[]ports.CodeParser{parser.NewGoParser(), parser.NewCParser()},
+		gitadapter.NewGitCLI(),
+		infrastructure.NewFileStorage(),
+		2,
+	)
+
+	if req.IncludeExt == nil {
+		req.IncludeExt = []string{".go", ".c", ".h"}
+	}
+	report, err := uc.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("analyze %s: %v", req.RootPath, err)
+	}
+	return report
+}
+
+type memStorage struct {
+	report *model.ProjectReport
+}
+
+func (m *memStorage) Save(ctx context.Context, root string, report *model.ProjectReport) error {
+	m.report = report
+	return nil
+}
+
+func (m *memStorage) Load(ctx context.Context, root string) (*model.ProjectReport, error) {
+	return m.report, nil
+}
+
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	runGit(t, root, "init", "-q")
+	return root
+}
+
+func commitFiles(t *testing.T, root, author string, files map[string]string, message string) {
+	t.Helper()
+	writeTree(t, root, files)
+	runGit(t, root, "add", "-A")
+	runGit(t, root,
+		"-c", "user.name="+author,
+		"-c", "user.email="+author+"@example.com",
+		"-c", "commit.gpgsign=false",
+		"commit", "-q", "-m", message)
+}
+
+func runGit(t *testing.T, root string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return string(out)
+}
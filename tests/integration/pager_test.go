@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/infrastructure"
+)
+
+func TestPagerDisabledPrintsDirectly(t *testing.T) {
+	var buf bytes.Buffer
+	pager := infrastructure.NewPagerWithOptions(infrastructure.PagerOptions{
+		Mode:     infrastructure.PagerNever,
+		Command:  "false",
+		Out:      &buf,
+		Terminal: true,
+		Height:   2,
+	})
+
+	text := strings.Repeat("line\n", 10) + "end"
+	if err := pager.Print(text); err != nil {
+		t.Fatalf("print: %v", err)
+	}
+	if buf.String() != text+"\n" {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+}
+
+func TestPagerFallsBackWhenBinaryMissing(t *testing.T) {
+	var buf bytes.Buffer
+	pager := infrastructure.NewPagerWithOptions(infrastructure.PagerOptions{
+		Mode:     infrastructure.PagerAlways,
+		Command:  "codeaudit-no-such-pager --flag",
+		Out:      &buf,
+		Terminal: true,
+	})
+
+	if err := pager.Print("report"); err != nil {
+		t.Fatalf("print: %v", err)
+	}
+	if buf.String() != "report\n" {
+		t.Fatalf("expected direct output, got %q", buf.String())
+	}
+}
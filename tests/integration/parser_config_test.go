@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	gitadapter "github.com/rafaelvolkmer/codeaudit/internal/adapter/git"
+	parser "github.com/rafaelvolkmer/codeaudit/internal/adapter/parser"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+	"github.com/rafaelvolkmer/codeaudit/internal/infrastructure"
+	"github.com/rafaelvolkmer/codeaudit/internal/usecase"
+)
+
+func analyzeWithParsers(t *testing.T, parsers []ports.CodeParser, req usecase.AnalyzeProjectRequest) *model.ProjectReport {
+	t.Helper()
+	scanner := infrastructure.NewFSScanner()
+	uc := usecase.NewAnalyzeProjectUseCase(scanner, scanner, parsers, gitadapter.NewGitCLI(), &memStorage{}, 1)
+	report, err := uc.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	return report
+}
+
+func TestConfigureParsersToggleAndExtensions(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"main.go":  "package main\n\nfunc main() {}\n",
+		"lib.c":    "int f(int a) {\n    return a;\n}\n",
+		"impl.cxx": "int g(int a) {\n    return a;\n}\n",
+	})
+	all := []ports.CodeParser{parser.NewGoParser(), parser.NewCParser()}
+	exts := []string{".go", ".c", ".cxx"}
+
+	parsers, err := parser.Configure(all, parser.Config{Disabled: []string{"c/c++"}})
+	if err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	report := analyzeWithParsers(t, parsers, usecase.AnalyzeProjectRequest{RootPath: root, IncludeExt: exts})
+	if len(report.Files) != 1 || report.Files[0].Language != model.LanguageGo {
+		t.Fatalf("expected only the Go file with the C parser disabled, got %d files", len(report.Files))
+	}
+	var warned bool
+	for _, w := range report.Warnings {
+		if strings.Contains(w, "no enabled parser handles .c files") {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Fatalf("expected a coverage warning for .c, got %v", report.Warnings)
+	}
+
+	parsers, err = parser.Configure(all, parser.Config{
+		Extensions: map[string][]string{"c/c++": {".c", ".cxx"}},
+	})
+	if err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	report = analyzeWithParsers(t, parsers, usecase.AnalyzeProjectRequest{RootPath: root, IncludeExt: exts})
+	if len(report.Files) != 3 || len(report.Warnings) != 0 {
+		t.Fatalf("expected .cxx to be handled by the C parser, got %d files, warnings %v", len(report.Files), report.Warnings)
+	}
+
+	mapped := parser.WithMappedExtensions([]string{".go", ".c"}, parser.Config{Extensions: map[string][]string{"c/c++": {".C", ".cxx"}}})
+	if strings.Join(mapped, ",") != ".go,.c,.cxx" {
+		t.Fatalf("expected --parser-ext extensions merged into --ext, got %v", mapped)
+	}
+
+	if _, err := parser.Configure(all, parser.Config{Disabled: []string{"rust"}}); err == nil {
+		t.Fatalf("expected an error for an unknown parser name")
+	}
+}
+
+func TestParserPriorityAndCppHeaderSniffing(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"shape.h": `#pragma once
+
+namespace geo {
+
+class Shape {
+public:
+    int area(int w, int h) {
+        return w * h;
+    }
+};
+
+}
+`,
+		"plain.h": "/* a class of helpers */\nint twice(int x) {\n    return x * 2;\n}\n",
+	})
+
+	parsers, err := parser.Configure(
+		[]ports.CodeParser{parser.NewGoParser(), parser.NewCParser()},
+		parser.Config{Priority: []string{"c/c++"}},
+	)
+	if err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	if parsers[0].Name() != "c/c++" || parsers[1].Name() != "go" {
+		t.Fatalf("expected c/c++ to be tried first, got %s, %s", parsers[0].Name(), parsers[1].Name())
+	}
+
+	report := analyzeWithParsers(t, parsers, usecase.AnalyzeProjectRequest{RootPath: root, IncludeExt: []string{".h"}})
+	langs := map[string]model.Language{}
+	for _, f := range report.Files {
+		langs[filepath.Base(f.Path)] = f.Language
+		for _, fn := range f.Functions {
+			if fn.Language != f.Language {
+				t.Fatalf("function %s language %s differs from file %s", fn.Name, fn.Language, f.Language)
+			}
+		}
+	}
+	if langs["shape.h"] != model.LanguageCpp || langs["plain.h"] != model.LanguageC {
+		t.Fatalf("expected shape.h as cpp and plain.h as c, got %v", langs)
+	}
+
+	if _, err := parser.Configure(parsers, parser.Config{Priority: []string{"rust"}}); err == nil {
+		t.Fatalf("expected an error for an unknown parser name in the priority list")
+	}
+}
+
+type stubParser struct {
+	name string
+	exts []string
+}
+
+func (p *stubParser) Name() string         { return p.name }
+func (p *stubParser) Extensions() []string { return p.exts }
+
+func (p *stubParser) SupportsFile(path string) bool {
+	for _, ext := range p.exts {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *stubParser) ParseFile(path string, src []byte) (*model.FileMetrics, error) {
+	return &model.FileMetrics{Path: path, Language: model.Language(p.name)}, nil
+}
+
+func TestOverlappingHeaderParsersResolveDeterministically(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"api.h":       "int twice(int x);\n",
+		"api.proto.h": "int encode(int x);\n",
+	})
+	exts := []string{".h"}
+	c := parser.NewCParser()
+	headers := &stubParser{name: "headers", exts: []string{".h", ".hpp"}}
+	proto := &stubParser{name: "proto", exts: []string{".proto.h"}}
+
+	langs := func(parsers []ports.CodeParser) map[string]model.Language {
+		t.Helper()
+		report := analyzeWithParsers(t, parsers, usecase.AnalyzeProjectRequest{RootPath: root, IncludeExt: exts})
+		out := map[string]model.Language{}
+		for _, f := range report.Files {
+			out[filepath.Base(f.Path)] = f.Language
+		}
+		return out
+	}
+
+	for _, order := range [][]ports.CodeParser{{c, proto}, {proto, c}} {
+		got := langs(order)
+		if got["api.proto.h"] != "proto" || got["api.h"] != model.LanguageC {
+			t.Fatalf("expected the most specific extension to win regardless of order (%s first), got %v", order[0].Name(), got)
+		}
+	}
+
+	for _, tc := range []struct {
+		priority []string
+		want     model.Language
+	}{
+		{[]string{"headers"}, "headers"},
+		{[]string{"c/c++"}, model.LanguageC},
+	} {
+		parsers, err := parser.Configure([]ports.CodeParser{c, headers}, parser.Config{Priority: tc.priority})
+		if err != nil {
+			t.Fatalf("configure: %v", err)
+		}
+		if got := langs(parsers)["api.h"]; got != tc.want {
+			t.Fatalf("priority %v: expected api.h to go to %s, got %s", tc.priority, tc.want, got)
+		}
+	}
+}
+
+func TestDefaultExtensionsCoverEnabledParsers(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"engine.cc": "int spin(int n) {\n\treturn n ? n : 1;\n}\n"})
+
+	parsers, err := parser.Configure([]ports.CodeParser{parser.NewGoParser(), parser.NewCppParser(), parser.NewCSharpParser(), parser.NewCParser()}, parser.Config{})
+	if err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	exts := parser.Extensions(parsers)
+	for _, want := range []string{".go", ".cc", ".cxx", ".hh", ".hxx", ".cs", ".c", ".h"} {
+		if !slices.Contains(exts, want) {
+			t.Fatalf("expected default extensions to include %s, got %v", want, exts)
+		}
+	}
+
+	report := analyzeWithParsers(t, parsers, usecase.AnalyzeProjectRequest{RootPath: root, IncludeExt: exts})
+	if len(report.Files) != 1 || report.Files[0].Language != model.LanguageCpp {
+		t.Fatalf("expected the .cc file to be analyzed as C++, got %+v", report.Files)
+	}
+}
+
+func TestCParserHandlesCxxWithoutTheCppParser(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"engine.cxx": "int spin(int n) {\n\treturn n ? n : 1;\n}\n",
+		"engine.hxx": "namespace eng {\nclass Engine {\npublic:\n    int run() {\n        return 1;\n    }\n};\n}\n",
+	})
+
+	parsers, err := parser.Configure([]ports.CodeParser{parser.NewGoParser(), parser.NewCppParser(), parser.NewCParser()},
+		parser.Config{Disabled: []string{"c++"}})
+	if err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	report := analyzeWithParsers(t, parsers, usecase.AnalyzeProjectRequest{RootPath: root, IncludeExt: parser.Extensions(parsers)})
+	if len(report.Files) != 2 {
+		t.Fatalf("expected .cxx and .hxx to be analyzed by the C parser, got %+v", report.Files)
+	}
+	for _, f := range report.Files {
+		if f.Language != model.LanguageCpp || len(f.Functions) != 1 {
+			t.Fatalf("expected %s reported as C++ with one function, got %s / %d", f.Path, f.Language, len(f.Functions))
+		}
+	}
+}
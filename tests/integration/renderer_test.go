@@ -0,0 +1,496 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	outputadapter "github.com/rafaelvolkmer/codeaudit/internal/adapter/output"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/infrastructure"
+	"github.com/rafaelvolkmer/codeaudit/internal/usecase"
+)
+
+func twoFileReport() *model.ProjectReport {
+	return &model.ProjectReport{
+		RootPath: "/repo",
+		Files: []model.FileMetrics{
+			{
+				Path:    "pkg/b.go",
+				Summary: model.FileSummaryMetrics{NLOC: 12, CCNTotal: 4, FunctionsCount: 1},
+				Functions: []model.FunctionMetrics{
+					{Name: "Beta", StartLine: 3, CCN: 4, NLOC: 12},
+				},
+			},
+			{
+				Path:    "pkg/a.go",
+				Summary: model.FileSummaryMetrics{NLOC: 9, CCNTotal: 3, FunctionsCount: 2},
+				Functions: []model.FunctionMetrics{
+					{Name: "Second", StartLine: 10, CCN: 2, NLOC: 5},
+					{Name: "First", StartLine: 1, CCN: 1, NLOC: 4},
+				},
+			},
+		},
+	}
+}
+
+func TestTextRendererTreeLayout(t *testing.T) {
+	r := outputadapter.NewTextRendererWithOptions(outputadapter.TextRendererOptions{Tree: true})
+	out, err := r.Render(twoFileReport())
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	plain := stripANSI(out)
+
+	idx := strings.Index(plain, "== Functions by file ==")
+	if idx < 0 {
+		t.Fatalf("tree section missing:\n%s", plain)
+	}
+	lines := strings.Split(strings.TrimSpace(plain[idx:]), "\n")
+	want := []string{
+		"== Functions by file ==",
+		"pkg/a.go (funcs=2, CCN=3, NLOC=9)",
+		"├── First",
+		"└── Second",
+		"pkg/b.go (funcs=1, CCN=4, NLOC=12)",
+		"└── Beta",
+	}
+	if len(lines) < len(want) {
+		t.Fatalf("expected at least %d lines, got:\n%s", len(want), plain[idx:])
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Fatalf("line %d = %q, want prefix %q", i, lines[i], prefix)
+		}
+	}
+	if !strings.Contains(lines[2], "CCN=1 NLOC=4") {
+		t.Fatalf("function line missing metrics: %q", lines[2])
+	}
+	if strings.Contains(plain, "== Function metrics (per function) ==") {
+		t.Fatalf("tree layout should replace the flat function table")
+	}
+}
+
+func TestTextRendererGroupByPackage(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"main.go": "package main\n\nfunc main() {}\n",
+		"store/store.go": `package store
+
+func Get(k string) string {
+	if k == "" {
+		return "none"
+	}
+	return k
+}
+`,
+		"store/put.go": `package store
+
+func Put(k string) bool {
+	if k == "" {
+		return false
+	}
+	return true
+}
+`,
+		"cmd/tool/main.go": "package main\n\nfunc main() {}\n",
+	})
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+
+	r := outputadapter.NewTextRendererWithOptions(outputadapter.TextRendererOptions{GroupByPackage: true})
+	out, err := r.Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	plain := stripANSI(out)
+
+	idx := strings.Index(plain, "== Files by Go package ==")
+	if idx < 0 {
+		t.Fatalf("package section missing:\n%s", plain)
+	}
+	lines := strings.Split(strings.TrimSpace(plain[idx:]), "\n")
+	want := []string{
+		"== Files by Go package ==",
+		"store store (files=2, funcs=2, CCN=4)",
+		"    put.go",
+		"    store.go",
+		"main . (files=1, funcs=1, CCN=1)",
+		"    main.go",
+		"main cmd/tool (files=1, funcs=1, CCN=1)",
+		"    main.go",
+	}
+	if len(lines) < len(want) {
+		t.Fatalf("expected at least %d lines, got:\n%s", len(want), plain[idx:])
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Fatalf("line %d = %q, want prefix %q", i, lines[i], prefix)
+		}
+	}
+
+	plainOut, err := outputadapter.NewTextRenderer().Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if strings.Contains(stripANSI(plainOut), "== Files by Go package ==") {
+		t.Fatalf("package grouping should be opt-in")
+	}
+}
+
+func TestCommentDensityColorBands(t *testing.T) {
+	const (
+		good   = "\033[38;5;108m"
+		warn   = "\033[38;5;214m"
+		danger = "\033[38;5;167m"
+	)
+	report := &model.ProjectReport{
+		Files: []model.FileMetrics{
+			{Path: "documented.c", Comments: model.CommentMetrics{CommentDensity: 0.25}, Summary: model.FileSummaryMetrics{CCNTotal: 3}},
+			{Path: "sparse.c", Comments: model.CommentMetrics{CommentDensity: 0.12}, Summary: model.FileSummaryMetrics{CCNTotal: 2}},
+			{Path: "bare.c", Comments: model.CommentMetrics{CommentDensity: 0.02}, Summary: model.FileSummaryMetrics{CCNTotal: 1}},
+		},
+	}
+
+	bands := func(opts outputadapter.TextRendererOptions) map[string]string {
+		out, err := outputadapter.NewTextRendererWithOptions(opts).Render(report)
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		got := make(map[string]string)
+		for _, line := range strings.Split(out, "\n") {
+			plain := stripANSI(line)
+			idx := strings.Index(line, "cmt=")
+			if idx < 0 {
+				continue
+			}
+			name := strings.Fields(plain)[1]
+			for color, band := range map[string]string{good: "good", warn: "warn", danger: "danger"} {
+				if strings.HasPrefix(line[idx+len("cmt="):], color) {
+					got[name] = band
+				}
+			}
+		}
+		return got
+	}
+
+	got := bands(outputadapter.TextRendererOptions{})
+	if got["documented.c"] != "good" || got["sparse.c"] != "good" || got["bare.c"] != "danger" {
+		t.Fatalf("unexpected default bands: %v", got)
+	}
+
+	got = bands(outputadapter.TextRendererOptions{CommentWarnBelow: 0.20, CommentDangerBelow: 0.10})
+	if got["documented.c"] != "good" || got["sparse.c"] != "warn" || got["bare.c"] != "danger" {
+		t.Fatalf("unexpected configured bands: %v", got)
+	}
+}
+
+func TestTemplateRenderer(t *testing.T) {
+	src := `{{range .Files}}{{.Path}} funcs={{.Summary.FunctionsCount}} ccn={{.Summary.CCNTotal}}
+{{end}}gt10={{pct .Project.FunctionsCCNGt10Pct}}`
+	r, err := outputadapter.NewTemplateRenderer("summary.tmpl", src)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if r.Format() != "template" {
+		t.Fatalf("unexpected format %q", r.Format())
+	}
+
+	report := twoFileReport()
+	report.Project.FunctionsCCNGt10Pct = 0.25
+	out, err := r.Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := "pkg/b.go funcs=1 ccn=4\npkg/a.go funcs=2 ccn=3\ngt10=25.0%"
+	if out != want {
+		t.Fatalf("got %q, want %q", out, want)
+	}
+
+	if _, err := outputadapter.NewTemplateRenderer("broken.tmpl", "{{range .Files}"); err == nil ||
+		!strings.Contains(err.Error(), "broken.tmpl") {
+		t.Fatalf("expected a parse error naming the template, got %v", err)
+	}
+}
+
+type fileCountRenderer struct{}
+
+func (fileCountRenderer) Format() string { return "Count" }
+
+func (fileCountRenderer) Render(report *model.ProjectReport) (string, error) {
+	return fmt.Sprintf("%d files", len(report.Files)), nil
+}
+
+func TestRegisterRendererAtRuntime(t *testing.T) {
+	registry := outputadapter.NewRendererRegistry(outputadapter.NewTextRenderer(), outputadapter.NewJSONRenderer())
+	if err := registry.Register(fileCountRenderer{}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	var formats []string
+	for _, r := range registry.List() {
+		formats = append(formats, r.Format())
+	}
+	if strings.Join(formats, ",") != "Count,json,text" {
+		t.Fatalf("unexpected renderers after register: %v", formats)
+	}
+
+	storage := &memStorage{report: twoFileReport()}
+	out, err := usecase.NewGenerateReportUseCase(storage, registry).Execute(context.Background(), usecase.GenerateReportRequest{
+		RootPath: "/repo",
+		Format:   "count",
+	})
+	if err != nil || out != "2 files" {
+		t.Fatalf("render through custom renderer: %q (%v)", out, err)
+	}
+
+	if err := registry.Register(outputadapter.NewJSONRenderer()); err == nil {
+		t.Fatalf("expected duplicate format to be rejected")
+	}
+	if err := registry.Register(nil); err == nil {
+		t.Fatalf("expected nil renderer to be rejected")
+	}
+}
+
+func TestPublishSummaryAndSavedOutput(t *testing.T) {
+	registry := outputadapter.NewRendererRegistry(outputadapter.NewTextRenderer(), outputadapter.NewJSONRenderer())
+	written := map[string]string{}
+	publish := usecase.NewPublishReportUseCase(registry, func(path string, data []byte) error {
+		written[path] = string(data)
+		return nil
+	})
+
+	stdout, err := publish.Execute(twoFileReport(), usecase.PublishReportRequest{
+		Format:        "json",
+		OutputPath:    "out/report.json",
+		SummaryFormat: "text",
+	})
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if !strings.Contains(stripANSI(stdout), "CodeAudit Report") {
+		t.Fatalf("expected a text summary on stdout, got %q", stdout)
+	}
+	var saved model.ProjectReport
+	if err := json.Unmarshal([]byte(written["out/report.json"]), &saved); err != nil || len(saved.Files) != 2 {
+		t.Fatalf("expected the JSON report in --output, got %v (%v)", written, err)
+	}
+
+	stdout, err = publish.Execute(twoFileReport(), usecase.PublishReportRequest{Format: "json", SummaryFormat: "text"})
+	if err != nil || strings.HasPrefix(stdout, "{") {
+		t.Fatalf("with a summary and no --output, only the summary goes to stdout: %q (%v)", stdout, err)
+	}
+
+	stdout, err = publish.Execute(twoFileReport(), usecase.PublishReportRequest{Format: "json"})
+	if err != nil || !strings.HasPrefix(stdout, "{") {
+		t.Fatalf("without a summary the main format goes to stdout: %q (%v)", stdout, err)
+	}
+
+	if _, err := publish.Execute(twoFileReport(), usecase.PublishReportRequest{Format: "json", SummaryFormat: "yaml"}); err == nil {
+		t.Fatalf("expected an unknown summary format to fail")
+	}
+}
+
+func TestConfigurableJSONIndent(t *testing.T) {
+	report := twoFileReport()
+
+	out, err := outputadapter.NewJSONRendererWithIndent("    ").Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(out, "\n    \"rootPath\"") {
+		t.Fatalf("expected four-space indentation, got:\n%s", out[:80])
+	}
+
+	out, err = outputadapter.NewJSONRendererWithIndent("").Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if strings.Contains(out, "\n") {
+		t.Fatalf("expected compact JSON without newlines")
+	}
+
+	root := t.TempDir()
+	storage := infrastructure.NewFileStorageWithIndent("\t")
+	if err := storage.Save(context.Background(), root, report); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, ".codeaudit", "report.json"))
+	if err != nil {
+		t.Fatalf("read saved report: %v", err)
+	}
+	if !strings.Contains(string(data), "\n\t\"rootPath\"") {
+		t.Fatalf("expected tab indentation in saved report")
+	}
+}
+
+func TestJSONFieldProjection(t *testing.T) {
+	r := outputadapter.NewJSONRendererWithOptions(outputadapter.JSONRendererOptions{
+		Fields: []string{"files.path", "files.functions.ccn", "project.totalFiles"},
+	})
+	out, err := r.Render(twoFileReport())
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := `{"files":[{"functions":[{"ccn":4}],"path":"pkg/b.go"},` +
+		`{"functions":[{"ccn":2},{"ccn":1}],"path":"pkg/a.go"}],"project":{"totalFiles":0}}`
+	if out != want {
+		t.Fatalf("unexpected projection:\n got %s\nwant %s", out, want)
+	}
+
+	r = outputadapter.NewJSONRendererWithOptions(outputadapter.JSONRendererOptions{
+		Fields: []string{"rootPath", "files.functions", "files.functions.name"},
+	})
+	out, err = r.Render(twoFileReport())
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(out, `"nloc":12`) || strings.Contains(out, `"summary"`) || !strings.Contains(out, `"rootPath":"/repo"`) {
+		t.Fatalf("a whole-section field should win over narrower paths: %s", out)
+	}
+}
+
+func TestJSONFieldValidation(t *testing.T) {
+	for _, fields := range [][]string{
+		{"files.functions.nope"},
+		{"project.totalFiles.deeper"},
+		{" "},
+	} {
+		if err := outputadapter.ValidateJSONFields(fields); err == nil {
+			t.Errorf("expected %v to be rejected", fields)
+		}
+	}
+	if err := outputadapter.ValidateJSONFields([]string{"project.smellCountsByKind.deep_nesting", "hotspots"}); err != nil {
+		t.Fatalf("valid fields rejected: %v", err)
+	}
+}
+
+func TestScatterRendererOneTuplePerFileWithGit(t *testing.T) {
+	report := &model.ProjectReport{Files: []model.FileMetrics{
+		{
+			Path:    "a.go",
+			Summary: model.FileSummaryMetrics{CCNTotal: 12},
+			Git:     &model.GitFileMetrics{LinesAdded: 30, LinesDeleted: 10},
+			Smells:  []model.CodeSmell{{Kind: model.SmellDeepNesting}, {Kind: model.SmellLongFunction}},
+		},
+		{
+			Path:    "b.go",
+			Summary: model.FileSummaryMetrics{CCNTotal: 2},
+			Git:     &model.GitFileMetrics{LinesAdded: 4},
+		},
+		{
+			Path:    "c.go",
+			Summary: model.FileSummaryMetrics{CCNTotal: 7},
+			Git:     &model.GitFileMetrics{LinesAdded: 1, LinesDeleted: 1},
+		},
+		{Path: "untracked.go", Summary: model.FileSummaryMetrics{CCNTotal: 50}},
+	}}
+
+	out, err := outputadapter.NewScatterRenderer().Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	var data outputadapter.ScatterData
+	if err := json.Unmarshal([]byte(out), &data); err != nil {
+		t.Fatalf("decode scatter output: %v\n%s", err, out)
+	}
+
+	if len(data.Points) != 3 {
+		t.Fatalf("expected one point per file with git data, got %+v", data.Points)
+	}
+	a := data.Points[0]
+	if a.Path != "a.go" || a.Complexity != 12 || a.Churn != 40 || a.Smells != 2 {
+		t.Fatalf("unexpected point for a.go: %+v", a)
+	}
+	if data.MedianComplexity != 7 || data.MedianChurn != 4 {
+		t.Fatalf("expected medians 7/4, got %v/%v", data.MedianComplexity, data.MedianChurn)
+	}
+}
+
+func TestGitLabRendererGolden(t *testing.T) {
+	report := &model.ProjectReport{
+		RootPath: "/repo",
+		Files: []model.FileMetrics{
+			{
+				Path: "/repo/pkg/engine.go",
+				Smells: []model.CodeSmell{
+					{Kind: model.SmellGodFunction, Description: "function is too large", FilePath: "/repo/pkg/engine.go", Function: "Run", Line: 12},
+					{Kind: model.SmellDeepNesting, Description: "nesting depth 5", FilePath: "/repo/pkg/engine.go", Function: "Run", Line: 30},
+					{Kind: model.SmellDeepNesting, Description: "nesting depth 6", FilePath: "/repo/pkg/engine.go", Function: "Run", Line: 44},
+				},
+			},
+			{
+				Path: "/repo/native/io.c",
+				Smells: []model.CodeSmell{
+					{Kind: model.SmellMixedIndentation, Description: "file mixes tab and space indentation", FilePath: "/repo/native/io.c"},
+				},
+			},
+		},
+	}
+
+	out, err := outputadapter.NewGitLabRenderer().Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	golden, err := os.ReadFile(filepath.Join("testdata", "gitlab.golden.json"))
+	if err != nil {
+		t.Fatalf("read golden file: %v", err)
+	}
+	if out+"\n" != string(golden) {
+		t.Fatalf("gitlab output differs from testdata/gitlab.golden.json:\n%s", out)
+	}
+
+	again, _ := outputadapter.NewGitLabRenderer().Render(report)
+	if again != out {
+		t.Fatalf("fingerprints must be stable across renders")
+	}
+}
+
+func TestStableCSVIdenticalAcrossRuns(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"b/z.go": "package b\n\nfunc Zeta() {}\n\nfunc Alpha(x int) int {\n\tif x > 0 {\n\t\treturn x\n\t}\n\treturn 0\n}\n",
+		"a.go":   "package a\n\nfunc Main() { Helper() }\n\nfunc Helper() {}\n",
+		"lib.c":  "int twice(int x) {\n    return x * 2;\n}\n",
+	})
+
+	render := func() string {
+		t.Helper()
+		report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, NoSave: true})
+		out, err := outputadapter.NewStableCSVRenderer().Render(report)
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		return out
+	}
+
+	first := render()
+	second := render()
+	if first != second {
+		t.Fatalf("csv-stable output differs across runs:\n%s\n---\n%s", first, second)
+	}
+
+	want := strings.Join([]string{
+		"path,function,language,nloc,ccn,cognitive,max_nesting,parameters,fan_in,fan_out",
+		"a.go,Helper,",
+		"a.go,Main,",
+		"b/z.go,Alpha,",
+		"b/z.go,Zeta,",
+		"lib.c,twice,",
+	}, "\n")
+	lines := strings.Split(strings.TrimSuffix(first, "\n"), "\n")
+	for i, prefix := range strings.Split(want, "\n") {
+		if i >= len(lines) || !strings.HasPrefix(lines[i], prefix) {
+			t.Fatalf("expected line %d to start with %q, got:\n%s", i+1, prefix, first)
+		}
+	}
+	if len(lines) != 6 {
+		t.Fatalf("expected a header and 5 rows, got %d lines", len(lines))
+	}
+}
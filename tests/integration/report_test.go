@@ -0,0 +1,1798 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gitadapter "github.com/rafaelvolkmer/codeaudit/internal/adapter/git"
+	outputadapter "github.com/rafaelvolkmer/codeaudit/internal/adapter/output"
+	parser "github.com/rafaelvolkmer/codeaudit/internal/adapter/parser"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+	"github.com/rafaelvolkmer/codeaudit/internal/infrastructure"
+	"github.com/rafaelvolkmer/codeaudit/internal/usecase"
+)
+
+func TestDirectoryMetricsNested(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"main.go": "package main\n\nfunc main() {\n\trun()\n}\n",
+		"pkg/util/util.go": `package util
+
+func A(x int) int {
+	if x > 0 {
+		return 1
+	}
+	return 0
+}
+
+func B(x int) int {
+	if x > 0 {
+		if x > 10 {
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+`,
+		"pkg/util/deep/deep.c": "int d(int x) {\n    if (x) {\n        return 1;\n    }\n    return 0;\n}\n",
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+
+	byPath := make(map[string]model.DirectoryMetrics)
+	for _, d := range report.Directories {
+		byPath[d.Path] = d
+	}
+	if len(byPath) != 3 {
+		t.Fatalf("expected 3 directories, got %+v", report.Directories)
+	}
+
+	util := byPath["pkg/util"]
+	if util.Files != 1 || util.Functions != 2 {
+		t.Fatalf("pkg/util: unexpected counts %+v", util)
+	}
+	if util.CCNTotal != 5 {
+		t.Fatalf("pkg/util: expected CCN total 5, got %d", util.CCNTotal)
+	}
+	if deep := byPath["pkg/util/deep"]; deep.Files != 1 || deep.Functions != 1 {
+		t.Fatalf("pkg/util/deep should be its own rollup, got %+v", deep)
+	}
+	if top := byPath["."]; top.Functions != 1 {
+		t.Fatalf("root directory rollup missing, got %+v", top)
+	}
+
+	for i := 1; i < len(report.Directories); i++ {
+		if report.Directories[i-1].CCNTotal < report.Directories[i].CCNTotal {
+			t.Fatalf("directories not sorted by total CCN: %+v", report.Directories)
+		}
+	}
+}
+
+func TestWorstFilesFailsAboveThreshold(t *testing.T) {
+	report := &model.ProjectReport{
+		Files: []model.FileMetrics{
+			{Path: "low.go", Summary: model.FileSummaryMetrics{CCNTotal: 3, CCNMaxFunction: 2}},
+			{Path: "high.go", Summary: model.FileSummaryMetrics{CCNTotal: 40, CCNMaxFunction: 25}},
+			{Path: "mid.go", Summary: model.FileSummaryMetrics{CCNTotal: 15, CCNMaxFunction: 9}},
+		},
+	}
+	uc := usecase.NewGenerateReportUseCase(
+		&memStorage{report: report},
+		outputadapter.NewRendererRegistry(outputadapter.NewJSONRenderer()),
+	)
+
+	res, err := uc.ExecuteWorst(context.Background(), usecase.WorstFilesRequest{
+		Format:  "json",
+		Count:   2,
+		FailCCN: 20,
+	})
+	if err != nil {
+		t.Fatalf("worst: %v", err)
+	}
+	if len(res.Files) != 2 || res.Files[0].Path != "high.go" || res.Files[1].Path != "mid.go" {
+		t.Fatalf("unexpected worst files: %+v", res.Files)
+	}
+	if len(res.Failing) != 1 || res.Failing[0] != "high.go" {
+		t.Fatalf("expected high.go to fail the gate, got %v", res.Failing)
+	}
+	if strings.Contains(res.Output, "low.go") {
+		t.Fatalf("rendered output should only include the worst files")
+	}
+
+	res, err = uc.ExecuteWorst(context.Background(), usecase.WorstFilesRequest{
+		Format:  "json",
+		Count:   5,
+		FailCCN: 30,
+	})
+	if err != nil {
+		t.Fatalf("worst: %v", err)
+	}
+	if len(res.Files) != 3 || len(res.Failing) != 0 {
+		t.Fatalf("expected 3 files and no failures, got %d files, failing %v", len(res.Files), res.Failing)
+	}
+}
+
+func TestBinaryFilesAreSkippedWithWarning(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"blob.go":  "package blob\x00\x01\x02\xff\xfe",
+		"latin.go": "package latin\n// caf\xe9\n",
+		"ok.go":    "package ok\n// " + strings.Repeat("a", 1024-len("package ok\n// ")-1) + "é\nfunc F() {}\n",
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+
+	if len(report.Files) != 1 || !strings.HasSuffix(report.Files[0].Path, "ok.go") {
+		t.Fatalf("expected only ok.go to be analyzed, got %d files", len(report.Files))
+	}
+
+	var binaryWarn, utf8Warn bool
+	for _, w := range report.Warnings {
+		if strings.Contains(w, "blob.go") && strings.Contains(w, "binary") {
+			binaryWarn = true
+		}
+		if strings.Contains(w, "latin.go") && strings.Contains(w, "UTF-8") {
+			utf8Warn = true
+		}
+	}
+	if !binaryWarn || !utf8Warn {
+		t.Fatalf("expected warnings for binary and non-UTF-8 files, got %v", report.Warnings)
+	}
+}
+
+func TestMinifiedFilesAreRecordedAsSkipped(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"bundle.c": "int a[] = {" + strings.Repeat("1,", 25000) + "};",
+		"normal.c": "int f(void) {\n\treturn 1;\n}\n",
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, MaxLineLength: 1000})
+
+	if len(report.Files) != 1 || !strings.HasSuffix(report.Files[0].Path, "normal.c") {
+		t.Fatalf("expected only normal.c to be analyzed, got %d files", len(report.Files))
+	}
+	if len(report.SkippedFiles) != 1 {
+		t.Fatalf("expected bundle.c recorded as skipped, got %+v", report.SkippedFiles)
+	}
+	if sf := report.SkippedFiles[0]; !strings.HasSuffix(sf.Path, "bundle.c") || !strings.Contains(sf.Reason, "line 1 is 50013 chars") {
+		t.Fatalf("expected the skipped entry to carry the path and reason, got %+v", sf)
+	}
+	out, err := outputadapter.NewTextRenderer().Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(stripANSI(out), "== Skipped files ==") {
+		t.Fatalf("expected the text output to list skipped files:\n%s", stripANSI(out))
+	}
+
+	report = analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	if len(report.Files) != 2 {
+		t.Fatalf("the check is disabled at 0, expected both files, got %d", len(report.Files))
+	}
+}
+
+func TestCommentDensityWeightedByLines(t *testing.T) {
+	root := t.TempDir()
+	big := "package big\n\nfunc Big() int {\n" + strings.Repeat("\tx := 1\n\t_ = x\n", 47) + "\treturn 0\n}\n"
+	writeTree(t, root, map[string]string{
+		"small.go": "// a\n// b\npackage small\n",
+		"big.go":   big,
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+
+	var totalLines, commentLines int
+	var sumDensity float64
+	for _, f := range report.Files {
+		totalLines += f.Comments.TotalLines
+		commentLines += f.Comments.CommentLines
+		sumDensity += f.Comments.CommentDensity
+	}
+
+	wantWeighted := float64(commentLines) / float64(totalLines)
+	wantAvg := sumDensity / float64(len(report.Files))
+	if diff := report.Project.CommentDensityWeighted - wantWeighted; diff > 1e-9 || diff < -1e-9 {
+		t.Fatalf("weighted density = %f, want %f", report.Project.CommentDensityWeighted, wantWeighted)
+	}
+	if diff := report.Project.CommentDensityAvg - wantAvg; diff > 1e-9 || diff < -1e-9 {
+		t.Fatalf("simple average = %f, want %f", report.Project.CommentDensityAvg, wantAvg)
+	}
+	if report.Project.CommentDensityWeighted >= report.Project.CommentDensityAvg/5 {
+		t.Fatalf("a tiny comment-heavy file should not dominate the weighted density: weighted=%f avg=%f",
+			report.Project.CommentDensityWeighted, report.Project.CommentDensityAvg)
+	}
+}
+
+func TestStrictModeFailsOnParseError(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"ok.go":     "package ok\n\nfunc F() {}\n",
+		"broken.go": "package broken\n\nfunc F( {\n",
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	if len(report.Files) != 1 || len(report.Warnings) == 0 {
+		t.Fatalf("lenient mode should keep going with a warning, got %d files, warnings %v",
+			len(report.Files), report.Warnings)
+	}
+
+	scanner := infrastructure.NewFSScanner()
+	uc := usecase.NewAnalyzeProjectUseCase(
+		scanner,
+		scanner,
+		[]ports.CodeParser{parser.NewGoParser()},
+		gitadapter.NewGitCLI(),
+		&memStorage{},
+		1,
+	)
+	_, err := uc.Execute(context.Background(), usecase.AnalyzeProjectRequest{
+		RootPath:   root,
+		IncludeExt: []string{".go"},
+		Strict:     true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "parse") || !strings.Contains(err.Error(), "broken.go") {
+		t.Fatalf("expected strict mode to return the parse error, got %v", err)
+	}
+}
+
+func TestInternalAndExternalFanOut(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.go": `package a
+
+func helper() int { return 1 }
+
+func Caller() int {
+	n := helper()
+	return n + unknownFn() + len("x")
+}
+`,
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+
+	var caller model.FunctionMetrics
+	for _, fn := range report.Files[0].Functions {
+		if fn.Name == "Caller" {
+			caller = fn
+		}
+	}
+	if caller.FanOut != 3 {
+		t.Fatalf("expected total fan-out 3, got %d (%v)", caller.FanOut, caller.Callees)
+	}
+	if caller.InternalFanOut != 1 || caller.ExternalFanOut != 2 {
+		t.Fatalf("expected internal=1 external=2, got internal=%d external=%d",
+			caller.InternalFanOut, caller.ExternalFanOut)
+	}
+}
+
+func TestSmellCountsByKindAggregatesAcrossFiles(t *testing.T) {
+	root := t.TempDir()
+	manyParams := "func P%d(a, b, c, d, e int) int { return a + b + c + d + e }\n"
+	writeTree(t, root, map[string]string{
+		"a.go": "package a\n\n" + fmt.Sprintf(manyParams, 1) + fmt.Sprintf(manyParams, 2),
+		"b.go": "package a\n\n" + fmt.Sprintf(manyParams, 3) + `
+func Deep(x int) int {
+	if x > 0 {
+		if x > 1 {
+			if x > 2 {
+				if x > 3 {
+					return 4
+				}
+			}
+		}
+	}
+	return 0
+}
+`,
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+
+	counts := report.Project.SmellCountsByKind
+	if counts[model.SmellManyParameters] != 3 {
+		t.Fatalf("expected 3 many_parameters smells across files, got %v", counts)
+	}
+	if counts[model.SmellDeepNesting] != 1 {
+		t.Fatalf("expected 1 deep_nesting smell, got %v", counts)
+	}
+
+	out, err := outputadapter.NewTextRenderer().Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(stripANSI(out), "many_parameters:") {
+		t.Fatalf("text output missing smells-by-kind section")
+	}
+}
+
+func TestAllowEmptyProducesEmptyReport(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"README.md": "# nothing to analyze\n"})
+
+	storage := &memStorage{}
+	scanner := infrastructure.NewFSScanner()
+	uc := usecase.NewAnalyzeProjectUseCase(
+		scanner,
+		scanner,
+		[]ports.CodeParser{parser.NewGoParser()},
+		gitadapter.NewGitCLI(),
+		storage,
+		1,
+	)
+
+	ctx := context.Background()
+	if _, err := uc.Execute(ctx, usecase.AnalyzeProjectRequest{
+		RootPath:   root,
+		IncludeExt: []string{".go"},
+	}); err == nil || !strings.Contains(err.Error(), "no source files") {
+		t.Fatalf("expected default run to fail on an empty tree, got %v", err)
+	}
+	if storage.report != nil {
+		t.Fatalf("failed run must not save a report")
+	}
+
+	report, err := uc.Execute(ctx, usecase.AnalyzeProjectRequest{
+		RootPath:   root,
+		IncludeExt: []string{".go"},
+		AllowEmpty: true,
+	})
+	if err != nil {
+		t.Fatalf("allow-empty run failed: %v", err)
+	}
+	if len(report.Files) != 0 || report.Project.TotalFiles != 0 || report.Project.TotalFunctions != 0 {
+		t.Fatalf("expected zeroed metrics, got %+v", report.Project)
+	}
+	if storage.report != report {
+		t.Fatalf("empty report should be saved normally")
+	}
+}
+
+func TestCompareToAverageMarkers(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.go": `package a
+
+func Below() int {
+	return 0
+}
+
+func At(x int) int {
+	if x > 0 {
+		return 1
+	}
+	return 0
+}
+
+func Above(x int) int {
+	if x > 0 {
+		return 1
+	}
+	if x < 0 {
+		return -1
+	}
+	return 0
+}
+`,
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	fns := make(map[string]model.FunctionMetrics)
+	for _, fn := range report.Files[0].Functions {
+		fns[fn.Name] = fn
+	}
+
+	if report.Project.AvgCCNPerFunction != 2 {
+		t.Fatalf("fixture expects mean CCN 2, got %f", report.Project.AvgCCNPerFunction)
+	}
+	if r := fns["Below"].CCNVsMean; r != 0.5 {
+		t.Fatalf("Below: CCN ratio = %f, want 0.5", r)
+	}
+	if r := fns["At"].CCNVsMean; r != 1 {
+		t.Fatalf("At: CCN ratio = %f, want 1", r)
+	}
+	if r := fns["Above"].CCNVsMean; r != 1.5 {
+		t.Fatalf("Above: CCN ratio = %f, want 1.5", r)
+	}
+	if fns["Below"].NLOCVsMean >= 1 || fns["Above"].NLOCVsMean <= 1 {
+		t.Fatalf("unexpected size ratios: below=%f above=%f", fns["Below"].NLOCVsMean, fns["Above"].NLOCVsMean)
+	}
+
+	r := outputadapter.NewTextRendererWithOptions(outputadapter.TextRendererOptions{CompareToAverage: true})
+	out, err := r.Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	markers := map[string]string{"Below": "↓ ↓", "At": "= =", "Above": "↑ ↑"}
+	for _, line := range strings.Split(stripANSI(out), "\n") {
+		for name, marker := range markers {
+			if strings.Contains(line, " "+name+" ") && strings.HasSuffix(line, marker) {
+				delete(markers, name)
+			}
+		}
+	}
+	if len(markers) != 0 {
+		t.Fatalf("missing deviation markers for %v in:\n%s", markers, stripANSI(out))
+	}
+}
+
+func TestPublicAPISurfaceSummary(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"api.go": `package api
+
+// Client talks to the server.
+type Client struct{}
+
+type Options struct{}
+
+type internal struct{}
+
+// Do performs a request.
+func (c *Client) Do() {}
+
+func New() *Client { return &Client{} }
+
+func helper() {}
+`,
+		"more.go": `package api
+
+// Version reports the API version.
+func Version() string { return "1" }
+`,
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	p := report.Project
+	if p.PublicAPISymbols != 5 || p.PublicAPIDocumented != 3 {
+		t.Fatalf("expected 5 public symbols / 3 documented, got %d / %d", p.PublicAPISymbols, p.PublicAPIDocumented)
+	}
+	if p.PublicAPIDocPct != 0.6 {
+		t.Fatalf("expected 60%% coverage, got %f", p.PublicAPIDocPct)
+	}
+
+	out, err := outputadapter.NewTextRenderer().Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(stripANSI(out), "Public API: 5 symbols, 60% documented") {
+		t.Fatalf("summary line missing from text output")
+	}
+}
+
+func TestRunStatsOnlyWhenRequested(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"a.go": "package a\n\nfunc A() {}\n"})
+
+	plain := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	if plain.Stats != nil {
+		t.Fatalf("stats must not be collected by default")
+	}
+	out, err := outputadapter.NewTextRenderer().Render(plain)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if strings.Contains(out, "Run stats") {
+		t.Fatalf("stats footer rendered without --stats")
+	}
+	data, err := outputadapter.NewJSONRenderer().Render(plain)
+	if err != nil {
+		t.Fatalf("render json: %v", err)
+	}
+	if strings.Contains(data, `"stats"`) {
+		t.Fatalf("stats object serialized without --stats")
+	}
+
+	withStats := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, Stats: true})
+	if withStats.Stats == nil || withStats.Stats.FilesAnalyzed != 1 || withStats.Stats.PeakHeapBytes == 0 {
+		t.Fatalf("unexpected stats: %+v", withStats.Stats)
+	}
+	out, err = outputadapter.NewTextRenderer().Render(withStats)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(stripANSI(out), "== Run stats ==") {
+		t.Fatalf("stats footer missing with --stats")
+	}
+}
+
+func TestHighFanOutCountsDistinctFiles(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"hub.go": `package p
+
+func local1() {}
+func local2() {}
+func local3() {}
+
+func Hub() {
+	local1()
+	local2()
+	local3()
+	one()
+	two()
+	three()
+	threeAgain()
+}
+`,
+		"one.go":   "package p\n\nfunc one() {}\n",
+		"two.go":   "package p\n\nfunc two() {}\n",
+		"three.go": "package p\n\nfunc three() {}\n\nfunc threeAgain() {}\n",
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{
+		RootPath: root,
+		Smells:   usecase.SmellConfig{MaxFanOutFiles: 2},
+	})
+
+	var hub model.FileMetrics
+	for _, f := range report.Files {
+		if strings.HasSuffix(f.Path, "hub.go") {
+			hub = f
+		}
+	}
+	for _, fn := range hub.Functions {
+		if fn.Name == "Hub" && (fn.FanOutFiles != 3 || fn.InternalFanOut != 7) {
+			t.Fatalf("expected 3 files across 7 internal callees, got %d/%d", fn.FanOutFiles, fn.InternalFanOut)
+		}
+	}
+	smells := 0
+	for _, s := range hub.Smells {
+		if s.Kind == model.SmellHighFanOut {
+			smells++
+			if s.Function != "Hub" || s.Line != 7 {
+				t.Fatalf("unexpected smell location: %+v", s)
+			}
+		}
+	}
+	if smells != 1 {
+		t.Fatalf("expected one high fan-out smell, got %d", smells)
+	}
+	if report.Project.SmellCountsByKind[model.SmellHighFanOut] != 1 {
+		t.Fatalf("smell missing from project counts: %v", report.Project.SmellCountsByKind)
+	}
+
+	disabled := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	if disabled.Project.SmellCountsByKind[model.SmellHighFanOut] != 0 {
+		t.Fatalf("smell emitted with threshold disabled")
+	}
+}
+
+func TestEverySmellKindHasRemediation(t *testing.T) {
+	for _, kind := range model.AllCodeSmellKinds() {
+		if model.SmellRemediation(kind) == "" {
+			t.Errorf("smell kind %s has no remediation", kind)
+		}
+	}
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.go": "package a\n\nfunc Many(a, b, c, d, e, f, g, h int) int {\n\treturn a + b + c + d + e + f + g + h\n}\n",
+	})
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	if len(report.Files[0].Smells) == 0 {
+		t.Fatalf("expected smells in fixture")
+	}
+	for _, s := range report.Files[0].Smells {
+		if s.Remediation != model.SmellRemediation(s.Kind) {
+			t.Fatalf("smell %s missing remediation: %+v", s.Kind, s)
+		}
+	}
+
+	out, err := outputadapter.NewTextRenderer().Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(stripANSI(out), "fix: "+model.SmellRemediation(model.SmellManyParameters)) {
+		t.Fatalf("remediation missing from text output:\n%s", stripANSI(out))
+	}
+}
+
+func TestMixedIndentationIsOptIn(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"tabs.c":   "int f(void)\n{\n\t/*\n\t * aligned comment\n\t */\n\tif (1) {\n\t\treturn 1;\n\t}\n\treturn 0;\n}\n",
+		"spaces.c": "int g(void)\n{\n    if (1) {\n        return 1;\n    }\n    return 0;\n}\n",
+		"mixed.c":  "int h(void)\n{\n    int x = 0;\n\tif (x) {\n        return 1;\n    }\n    return 0;\n}\n",
+	})
+
+	mixedSmells := func(report *model.ProjectReport) map[string][]model.CodeSmell {
+		out := make(map[string][]model.CodeSmell)
+		for _, f := range report.Files {
+			for _, s := range f.Smells {
+				if s.Kind == model.SmellMixedIndentation {
+					name := f.Path[strings.LastIndex(f.Path, "/")+1:]
+					out[name] = append(out[name], s)
+				}
+			}
+		}
+		return out
+	}
+
+	if got := mixedSmells(analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})); len(got) != 0 {
+		t.Fatalf("mixed indentation reported without opting in: %v", got)
+	}
+
+	got := mixedSmells(analyzeTree(t, usecase.AnalyzeProjectRequest{
+		RootPath: root,
+		Smells:   usecase.SmellConfig{MixedIndentation: true},
+	}))
+	if len(got) != 1 || len(got["mixed.c"]) != 1 {
+		t.Fatalf("expected only mixed.c to be flagged, got %v", got)
+	}
+	if got["mixed.c"][0].Line != 4 {
+		t.Fatalf("expected first inconsistency at line 4, got %d", got["mixed.c"][0].Line)
+	}
+}
+
+func TestPackageInstability(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.22\n",
+		"core/core.go": `package core
+
+type Store interface {
+	Get(key string) string
+}
+
+type memStore struct{}
+
+func (memStore) Get(key string) string { return key }
+`,
+		"app/app.go": `package app
+
+import (
+	"fmt"
+
+	"example.com/m/core"
+)
+
+func Run(s core.Store) { fmt.Println(s.Get("k")) }
+`,
+		"api/api.go": `package api
+
+import "example.com/m/core"
+
+func Serve(s core.Store) string { return s.Get("x") }
+`,
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+
+	byPath := make(map[string]model.PackageMetrics)
+	for _, p := range report.Packages {
+		byPath[p.Path] = p
+	}
+	core := byPath["core"]
+	if core.Afferent != 2 || core.Efferent != 0 || core.Instability == nil || *core.Instability != 0 || core.Isolated {
+		t.Fatalf("core should be stable, got %+v", core)
+	}
+	if core.Abstractness == nil || *core.Abstractness != 0.5 || core.Distance == nil || *core.Distance != 0.5 {
+		t.Fatalf("core abstractness/distance: %+v", core)
+	}
+	app := byPath["app"]
+	if app.Afferent != 0 || app.Efferent != 1 || app.Instability == nil || *app.Instability != 1 || app.Distance != nil {
+		t.Fatalf("app should be unstable without distance, got %+v", app)
+	}
+	if report.Packages[len(report.Packages)-1].Path != "core" {
+		t.Fatalf("expected packages sorted by instability, got %+v", report.Packages)
+	}
+
+	out, err := outputadapter.NewTextRenderer().Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	plain := stripANSI(out)
+	if !strings.Contains(plain, "== Most unstable packages (top 3) ==") ||
+		!strings.Contains(plain, "I=1.00 [##########]") {
+		t.Fatalf("instability table missing:\n%s", plain)
+	}
+}
+
+func TestPackageImportsResolveAgainstModulePath(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"go.mod":       "module example.com/m // main module\n\ngo 1.22\n",
+		"root.go":      "package m\n\nfunc Version() string { return \"1\" }\n",
+		"core/core.go": "package core\n\nfunc One() int { return 1 }\n",
+		"app/app.go":   "package app\n\nimport (\n\t\"example.com/m\"\n\t\"github.com/vendor/core\"\n)\n\nfunc Run() string { return m.Version() + core.Name() }\n",
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	byPath := make(map[string]model.PackageMetrics)
+	for _, p := range report.Packages {
+		byPath[p.Path] = p
+	}
+	if core := byPath["core"]; core.Afferent != 0 || !core.Isolated {
+		t.Fatalf("a third-party package with the same suffix must not resolve to core, got %+v", core)
+	}
+	if top := byPath["."]; top.Afferent != 1 {
+		t.Fatalf("expected the module root package to be imported by app, got %+v", top)
+	}
+	if app := byPath["app"]; app.Efferent != 1 {
+		t.Fatalf("expected app to depend only on the module root, got %+v", app)
+	}
+}
+
+func TestIsolatedPackageInstability(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"go.mod":       "module example.com/m\n\ngo 1.22\n",
+		"lone/lone.go": "package lone\n\ntype Shape interface{ Area() float64 }\n\nfunc Lone() int { return 1 }\n",
+		"app/app.go":   "package app\n\nimport \"example.com/m/core\"\n\nfunc Run() int { return core.One() }\n",
+		"core/core.go": "package core\n\nfunc One() int { return 1 }\n",
+	})
+
+	lone := func(report *model.ProjectReport) model.PackageMetrics {
+		t.Helper()
+		for _, p := range report.Packages {
+			if p.Path == "lone" {
+				return p
+			}
+		}
+		t.Fatalf("lone package missing: %+v", report.Packages)
+		return model.PackageMetrics{}
+	}
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	p := lone(report)
+	if !p.Isolated || p.Instability == nil || *p.Instability != 0 || p.Distance == nil || *p.Distance != 0 {
+		t.Fatalf("isolated package should default to instability 0 (distance 0 with abstractness 1), got %+v", p)
+	}
+
+	report = analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, OmitIsolatedInstability: true})
+	p = lone(report)
+	if !p.Isolated || p.Instability != nil || p.Distance != nil {
+		t.Fatalf("omitted isolated package should have no instability or distance, got %+v", p)
+	}
+	if last := report.Packages[len(report.Packages)-1]; last.Path != "lone" {
+		t.Fatalf("packages without instability should sort last, got %+v", report.Packages)
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(data), "instability") || !strings.Contains(string(data), `"isolated":true`) {
+		t.Fatalf("unexpected JSON for an omitted package: %s", data)
+	}
+
+	out, err := outputadapter.NewTextRenderer().Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(stripANSI(out), "I=   - [..........]") {
+		t.Fatalf("text output should show a dash for the omitted value:\n%s", stripANSI(out))
+	}
+}
+
+func TestReportFilterRecomputesAggregates(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"core/a.go":      "package core\n\nfunc A(x int) int {\n\tif x > 0 {\n\t\treturn 1\n\t}\n\treturn 0\n}\n",
+		"core/deep/b.go": "package deep\n\nfunc B() {}\n",
+		"cmd/main.go":    "package main\n\nfunc main() {}\n\nfunc helper() {}\n",
+	})
+	storage := &memStorage{}
+	scanner := infrastructure.NewFSScanner()
+	_, err := usecase.NewAnalyzeProjectUseCase(scanner, scanner,
+		[]ports.CodeParser{parser.NewGoParser()}, gitadapter.NewGitCLI(), storage, 1,
+	).Execute(context.Background(), usecase.AnalyzeProjectRequest{RootPath: root})
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+
+	uc := usecase.NewGenerateReportUseCase(storage, outputadapter.NewRendererRegistry(
+		outputadapter.NewJSONRenderer(),
+		outputadapter.NewTextRenderer(),
+	))
+	out, err := uc.Execute(context.Background(), usecase.GenerateReportRequest{
+		RootPath: root,
+		Format:   "json",
+		Filter:   "core",
+	})
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+
+	var filtered model.ProjectReport
+	if err := json.Unmarshal([]byte(out), &filtered); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(filtered.Files) != 2 || filtered.Project.TotalFiles != 2 || filtered.Project.TotalFunctions != 2 {
+		t.Fatalf("expected the two core files, got %d files / %+v", len(filtered.Files), filtered.Project)
+	}
+	if filtered.Project.MaxCCNPerFunction != 2 || filtered.Project.AvgCCNPerFunction != 1.5 {
+		t.Fatalf("aggregates not recomputed: %+v", filtered.Project)
+	}
+	if filtered.Filter == nil || filtered.Filter.Matched != 2 || filtered.Filter.TotalFiles != 3 {
+		t.Fatalf("filter label missing: %+v", filtered.Filter)
+	}
+	if len(storage.report.Files) != 3 {
+		t.Fatalf("filtering must not modify the stored report")
+	}
+
+	text, err := uc.Execute(context.Background(), usecase.GenerateReportRequest{
+		RootPath: root,
+		Format:   "text",
+		Filter:   "core/deep/*.go",
+	})
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if !strings.Contains(stripANSI(text), "Filtered: core/deep/*.go (1 of 3 files") {
+		t.Fatalf("text output not labeled as filtered:\n%s", stripANSI(text))
+	}
+
+	if _, err := uc.Execute(context.Background(), usecase.GenerateReportRequest{
+		RootPath: root,
+		Format:   "json",
+		Filter:   "[",
+	}); err == nil {
+		t.Fatalf("expected an error for a malformed glob")
+	}
+}
+
+func TestBaselineReportDelta(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.go": "package a\n\nfunc A() {}\n",
+	})
+	analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+
+	baseline, err := infrastructure.LoadReportFile(filepath.Join(root, ".codeaudit", "report.json"))
+	if err != nil {
+		t.Fatalf("load baseline: %v", err)
+	}
+
+	writeTree(t, root, map[string]string{
+		"b.go": "package a\n\nfunc B(x int) int {\n\tif x > 0 {\n\t\treturn 1\n\t}\n\treturn 0\n}\n",
+	})
+	baselinePath := filepath.Join(root, "baseline.json")
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, Baseline: baseline, BaselinePath: baselinePath})
+
+	d := report.Delta
+	if d == nil {
+		t.Fatalf("expected a delta against the baseline")
+	}
+	if d.BaselinePath != baselinePath || !d.BaselineGeneratedAt.Equal(baseline.GeneratedAt) {
+		t.Fatalf("expected the delta to name its baseline, got %q at %v", d.BaselinePath, d.BaselineGeneratedAt)
+	}
+	saved, err := infrastructure.LoadReportFile(filepath.Join(root, ".codeaudit", "report.json"))
+	if err != nil || saved.Delta != nil {
+		t.Fatalf("the persisted report must not carry a baseline delta, got %+v (%v)", saved, err)
+	}
+	if d.Files.Change != 1 || d.Functions.Baseline != 1 || d.Functions.Current != 2 {
+		t.Fatalf("unexpected file/function delta: %+v %+v", d.Files, d.Functions)
+	}
+	if d.AvgCCNPerFunction.Baseline != 1 || d.AvgCCNPerFunction.Current != 1.5 || d.AvgCCNPerFunction.Change != 0.5 {
+		t.Fatalf("unexpected avg CCN delta: %+v", d.AvgCCNPerFunction)
+	}
+
+	out, err := outputadapter.NewTextRenderer().Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(stripANSI(out), "1.00 -> 1.50 (+0.50)") {
+		t.Fatalf("delta section missing from text output:\n%s", stripANSI(out))
+	}
+
+	plain := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	if plain.Delta != nil {
+		t.Fatalf("delta must only be computed with a baseline")
+	}
+}
+
+func TestBlankPaddedFunctionFlagged(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"padded.go": `package p
+
+func Padded(x int) int {
+
+	// step one
+
+
+	y := x + 1
+
+
+
+	// step two
+
+
+	return y
+
+}
+
+func Compact(x int) int {
+	y := x + 1
+	z := y * 2
+	w := z - 3
+	v := w + y
+	u := v * z
+	t := u - w
+	s := t + v
+	return s
+}
+`,
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{
+		RootPath: root,
+		Smells:   usecase.SmellConfig{MaxPaddingRatio: 2},
+	})
+
+	f := report.Files[0]
+	for _, fn := range f.Functions {
+		if fn.PhysicalLines != fn.EndLine-fn.StartLine+1 {
+			t.Fatalf("%s: physical lines %d do not match span %d-%d", fn.Name, fn.PhysicalLines, fn.StartLine, fn.EndLine)
+		}
+	}
+	var padded []model.CodeSmell
+	for _, s := range f.Smells {
+		if s.Kind == model.SmellBlankPadding {
+			padded = append(padded, s)
+		}
+	}
+	if len(padded) != 1 || padded[0].Function != "Padded" || padded[0].Line != 3 {
+		t.Fatalf("expected one padding smell on Padded, got %+v", padded)
+	}
+
+	report = analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	if n := report.Project.SmellCountsByKind[model.SmellBlankPadding]; n != 0 {
+		t.Fatalf("padding check should be disabled at ratio 0, got %d", n)
+	}
+}
+
+func TestLongFunctionSmellThreshold(t *testing.T) {
+	var body strings.Builder
+	for i := 0; i < 12; i++ {
+		fmt.Fprintf(&body, "\tx += %d\n", i)
+	}
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.go": "package a\n\nfunc Long(x int) int {\n" + body.String() + "\treturn x\n}\n\nfunc Short() int { return 1 }\n",
+		"b.c":  "int long_c(int x) {\n" + strings.ReplaceAll(body.String(), "\n", ";\n") + "\treturn x;\n}\n",
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{
+		RootPath: root,
+		Smells:   usecase.SmellConfig{MaxFunctionNLOC: 10},
+	})
+	long := map[string]model.CodeSmell{}
+	for _, f := range report.Files {
+		for _, s := range f.Smells {
+			if s.Kind == model.SmellLongFunction {
+				long[s.Function] = s
+			}
+		}
+	}
+	if len(long) != 2 || long["Long"].Line != 3 || long["long_c"].Line != 1 {
+		t.Fatalf("expected Long (line 3) and long_c (line 1) flagged, got %+v", long)
+	}
+	if !strings.Contains(long["Long"].Description, "(>10)") {
+		t.Fatalf("description should name the threshold: %q", long["Long"].Description)
+	}
+
+	report = analyzeTree(t, usecase.AnalyzeProjectRequest{
+		RootPath: root,
+		Smells:   usecase.SmellConfig{MaxFunctionNLOC: 20},
+	})
+	if n := report.Project.SmellCountsByKind[model.SmellLongFunction]; n != 0 {
+		t.Fatalf("no function exceeds 20 NLOC, got %d smells", n)
+	}
+}
+
+func TestGeneratedAtFromSourceDateEpoch(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"a.go": "package a\n\nfunc A() {}\n"})
+
+	t.Setenv(infrastructure.SourceDateEpochEnv, "1700000000")
+	at, err := infrastructure.ResolveGeneratedAt("")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, GeneratedAt: at})
+	if want := time.Unix(1700000000, 0).UTC(); !report.GeneratedAt.Equal(want) || report.GeneratedAt.Location() != time.UTC {
+		t.Fatalf("expected %s from the environment, got %s", want, report.GeneratedAt)
+	}
+
+	at, err = infrastructure.ResolveGeneratedAt("2024-05-01T12:00:00+02:00")
+	if err != nil || !at.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("explicit flag should win over the environment, got %s (%v)", at, err)
+	}
+
+	t.Setenv(infrastructure.SourceDateEpochEnv, "yesterday")
+	if _, err := infrastructure.ResolveGeneratedAt(""); err == nil {
+		t.Fatalf("expected an error for a malformed %s", infrastructure.SourceDateEpochEnv)
+	}
+
+	t.Setenv(infrastructure.SourceDateEpochEnv, "")
+	if at, err := infrastructure.ResolveGeneratedAt(""); err != nil || !at.IsZero() {
+		t.Fatalf("expected zero time (now) without overrides, got %s (%v)", at, err)
+	}
+}
+
+func TestIssuesFlattenFileSmells(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.go": `package a
+
+var counter int
+
+func Wide(a, b, c, d, e, f int) int {
+	if a > 0 {
+		if b > 0 {
+			if c > 0 {
+				if d > 0 {
+					return e + f
+				}
+			}
+		}
+	}
+	return 0
+}
+`,
+		"b.c": "int g;\n\nint many(int a, int b, int c, int d, int e, int f) {\n\treturn a;\n}\n",
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+
+	total := 0
+	for _, f := range report.Files {
+		total += len(f.Smells)
+	}
+	if total == 0 || len(report.Issues) != total {
+		t.Fatalf("expected %d flattened issues, got %d", total, len(report.Issues))
+	}
+	for i, issue := range report.Issues {
+		if issue.RuleID == "" || issue.Severity == "" || issue.FilePath == "" || issue.Message == "" {
+			t.Fatalf("incomplete issue: %+v", issue)
+		}
+		if issue.Severity != model.SmellSeverity(model.CodeSmellKind(issue.RuleID)) {
+			t.Fatalf("severity does not match rule: %+v", issue)
+		}
+		if i > 0 && issue.FilePath < report.Issues[i-1].FilePath {
+			t.Fatalf("issues not ordered by file: %+v", report.Issues)
+		}
+	}
+
+	storage := &memStorage{report: report}
+	out, err := usecase.NewGenerateReportUseCase(storage, outputadapter.NewRendererRegistry(outputadapter.NewJSONRenderer())).
+		Execute(context.Background(), usecase.GenerateReportRequest{RootPath: root, Format: "json", Filter: "*.go"})
+	if err != nil {
+		t.Fatalf("render filtered: %v", err)
+	}
+	var filtered model.ProjectReport
+	if err := json.Unmarshal([]byte(out), &filtered); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	for _, issue := range filtered.Issues {
+		if !strings.HasSuffix(issue.FilePath, ".go") {
+			t.Fatalf("filtered report kept issue from %s", issue.FilePath)
+		}
+	}
+	if len(filtered.Issues) != len(filtered.Files[0].Smells) {
+		t.Fatalf("filtered issues %d != smells %d", len(filtered.Issues), len(filtered.Files[0].Smells))
+	}
+}
+
+func TestRunSummaryWrittenOnSuccessAndGateFailure(t *testing.T) {
+	dir := t.TempDir()
+	readSummary := func(path string) model.RunSummary {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read summary: %v", err)
+		}
+		var s model.RunSummary
+		if err := json.Unmarshal(data, &s); err != nil {
+			t.Fatalf("decode summary: %v", err)
+		}
+		return s
+	}
+
+	okPath := filepath.Join(dir, "ok.json")
+	w := infrastructure.NewRunSummaryWriter(okPath, "analyze")
+	w.RecordReport(&model.ProjectReport{
+		Files:    []model.FileMetrics{{Path: "a.go"}, {Path: "b.go"}},
+		Warnings: []string{"skip c.go"},
+	})
+	if err := w.Finish(nil); err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+	ok := readSummary(okPath)
+	if ok.Command != "analyze" || ok.FilesAnalyzed != 2 || ok.Warnings != 1 || ok.ExitCode != 0 || ok.Error != "" {
+		t.Fatalf("unexpected success summary: %+v", ok)
+	}
+	if ok.ThresholdPassed != nil {
+		t.Fatalf("threshold should be omitted when not gated: %+v", ok)
+	}
+
+	failPath := filepath.Join(dir, "fail.json")
+	w = infrastructure.NewRunSummaryWriter(failPath, "report")
+	w.RecordFiles(3)
+	w.RecordThreshold([]string{"high.go"})
+	gateErr := fmt.Errorf("1 file(s) have functions above CCN 20: high.go")
+	if err := w.Finish(gateErr); err != gateErr {
+		t.Fatalf("finish should return the run error, got %v", err)
+	}
+	failed := readSummary(failPath)
+	if failed.ExitCode != 1 || failed.Error != gateErr.Error() || failed.FilesAnalyzed != 3 {
+		t.Fatalf("unexpected failure summary: %+v", failed)
+	}
+	if failed.ThresholdPassed == nil || *failed.ThresholdPassed || len(failed.Failing) != 1 {
+		t.Fatalf("expected failed threshold with high.go, got %+v", failed)
+	}
+}
+
+func TestPerFileReportsMirrorSourceTree(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"main.go":       "package main\n\nfunc main() {}\n",
+		"pkg/util.go":   "package pkg\n\nfunc Util(x int) int { return x }\n",
+		"native/math.c": "int twice(int x) {\n\treturn x * 2;\n}\n",
+	})
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+
+	out := t.TempDir()
+	n, err := infrastructure.WritePerFileReports(out, report, infrastructure.DefaultJSONIndent)
+	if err != nil {
+		t.Fatalf("write per-file reports: %v", err)
+	}
+	if n != len(report.Files) || n != 3 {
+		t.Fatalf("expected one output per analyzed file, wrote %d for %d files", n, len(report.Files))
+	}
+
+	for _, rel := range []string{"main.go", "pkg/util.go", "native/math.c"} {
+		data, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(rel)+".json"))
+		if err != nil {
+			t.Fatalf("missing per-file report for %s: %v", rel, err)
+		}
+		var fm model.FileMetrics
+		if err := json.Unmarshal(data, &fm); err != nil {
+			t.Fatalf("decode %s: %v", rel, err)
+		}
+		if filepath.ToSlash(fm.Path) != filepath.ToSlash(filepath.Join(root, rel)) || len(fm.Functions) != 1 {
+			t.Fatalf("unexpected per-file report for %s: path %s, %d functions", rel, fm.Path, len(fm.Functions))
+		}
+	}
+}
+
+func TestPerFileReportsWithRelativeRoot(t *testing.T) {
+	abs := t.TempDir()
+	writeTree(t, abs, map[string]string{
+		"main.go":     "package main\n\nfunc main() {}\n",
+		"pkg/util.go": "package pkg\n\nfunc Util(x int) int { return x }\n",
+	})
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	root, err := filepath.Rel(wd, abs)
+	if err != nil {
+		t.Skipf("temp dir not reachable relatively: %v", err)
+	}
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+
+	out := t.TempDir()
+	if _, err := infrastructure.WritePerFileReports(out, report, ""); err != nil {
+		t.Fatalf("write per-file reports: %v", err)
+	}
+	for _, rel := range []string{"main.go", "pkg/util.go"} {
+		if _, err := os.Stat(filepath.Join(out, filepath.FromSlash(rel)+".json")); err != nil {
+			t.Fatalf("missing per-file report for %s: %v", rel, err)
+		}
+	}
+
+	outside := &model.ProjectReport{RootPath: root, Files: []model.FileMetrics{{Path: filepath.Join(root, "..", "escape.go")}}}
+	if _, err := infrastructure.WritePerFileReports(out, outside, ""); err == nil {
+		t.Fatalf("expected a file outside the root to be rejected")
+	}
+}
+
+func TestReportPathsUseForwardSlashes(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"cmd/app/main.go": "package main\n\nfunc main() {}\n",
+		"pkg/deep/util.go": `package deep
+
+func Util(a, b, c, d, e, f, g int) int { return a + b + c + d + e + f + g }
+`,
+	})
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+
+	var paths []string
+	for _, f := range report.Files {
+		paths = append(paths, f.Path)
+		for _, fn := range f.Functions {
+			paths = append(paths, fn.FilePath)
+		}
+		for _, sm := range f.Smells {
+			paths = append(paths, sm.FilePath)
+		}
+	}
+	for _, issue := range report.Issues {
+		paths = append(paths, issue.FilePath)
+	}
+	if len(report.Issues) == 0 {
+		t.Fatalf("expected the many-parameter function to produce an issue")
+	}
+	for _, p := range paths {
+		if strings.Contains(p, `\`) || !strings.HasPrefix(p, filepath.ToSlash(root)+"/") {
+			t.Fatalf("expected a forward-slash path under %s, got %q", root, p)
+		}
+	}
+}
+
+func TestSurfaceAreaSmellOnCombinedTotals(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"a.go": `package a
+
+func Bloated(width, height, depth, scale int) (int, int, string) {
+	area := width * height
+	volume := area * depth
+	scaled := volume * scale
+	perimeter := 2 * (width + height)
+	ratio := width / height
+	label := "box"
+	padded := label + " "
+	total := scaled + perimeter
+	offset := ratio + depth
+	result := total - offset
+	return result, area, padded
+}
+
+func Lean(width, height int) int {
+	area := width * height
+	return area
+}
+`})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{
+		RootPath: root,
+		Smells:   usecase.SmellConfig{MaxSurfaceArea: 16},
+	})
+	fns := map[string]model.FunctionMetrics{}
+	for _, fn := range report.Files[0].Functions {
+		fns[fn.Name] = fn
+	}
+	bloated := fns["Bloated"]
+	if bloated.Parameters != 4 || bloated.ReturnValueCount != 3 || bloated.LocalVariables != 10 || bloated.SurfaceArea != 17 {
+		t.Fatalf("unexpected Bloated metrics: params %d, results %d, locals %d, surface %d",
+			bloated.Parameters, bloated.ReturnValueCount, bloated.LocalVariables, bloated.SurfaceArea)
+	}
+	if fns["Lean"].SurfaceArea != 4 {
+		t.Fatalf("expected Lean surface area 4, got %d", fns["Lean"].SurfaceArea)
+	}
+
+	var individual int
+	for _, s := range report.Files[0].Smells {
+		switch s.Kind {
+		case model.SmellManyParameters, model.SmellManyLocals, model.SmellManyReturns:
+			individual++
+		}
+	}
+	surface := smellsOfKind(&report.Files[0], model.SmellLargeSurface)
+	if individual != 0 || len(surface) != 1 || surface[0].Function != "Bloated" || surface[0].Line != 3 {
+		t.Fatalf("expected only the combined surface-area smell on Bloated, got individual %d, surface %+v", individual, surface)
+	}
+}
+
+func TestPublicDocCoverageGate(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.go": `package a
+
+// Documented explains itself.
+func Documented() {}
+
+func Bare() {}
+`,
+		"b.go": `package a
+
+func AlsoBare() {}
+
+// Fine is documented.
+func Fine() {}
+`,
+	})
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	if report.Project.PublicAPISymbols != 4 || report.Project.PublicAPIDocPct != 0.5 {
+		t.Fatalf("expected 2 of 4 public symbols documented, got %d symbols, %.2f",
+			report.Project.PublicAPISymbols, report.Project.PublicAPIDocPct)
+	}
+
+	strict, err := usecase.ParseGateRule("public-doc-coverage>=80")
+	if err != nil {
+		t.Fatalf("parse gate: %v", err)
+	}
+	failing := usecase.EvaluateGates(report, []usecase.GateRule{strict})
+	if len(failing) != 1 || !strings.Contains(failing[0], "public-doc-coverage>=80 (got 50.00)") {
+		t.Fatalf("expected the doc coverage gate to fire, got %v", failing)
+	}
+
+	lenient, err := usecase.ParseGateRule(" public-doc-coverage >= 50 ")
+	if err != nil {
+		t.Fatalf("parse gate: %v", err)
+	}
+	if failing := usecase.EvaluateGates(report, []usecase.GateRule{lenient}); len(failing) != 0 {
+		t.Fatalf("expected 50%% coverage to pass >=50, got %v", failing)
+	}
+
+	for _, spec := range []string{"doc-coverage>=80", "public-doc-coverage=80", "public-doc-coverage>=lots"} {
+		if _, err := usecase.ParseGateRule(spec); err == nil {
+			t.Fatalf("expected %q to be rejected", spec)
+		}
+	}
+}
+
+func TestGatesEvaluateTheFilteredReport(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"core/a.go":   "package core\n\n// Documented explains itself.\nfunc Documented() {}\n",
+		"legacy/b.go": "package legacy\n\nfunc Bare() {}\n",
+	})
+	analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+
+	rule, err := usecase.ParseGateRule("public-doc-coverage>=80")
+	if err != nil {
+		t.Fatalf("parse gate: %v", err)
+	}
+	uc := usecase.NewGenerateReportUseCase(infrastructure.NewFileStorage(), outputadapter.NewRendererRegistry(outputadapter.NewTextRenderer()))
+	for _, tc := range []struct {
+		filter  string
+		failing int
+	}{{"", 1}, {"core", 0}, {"legacy", 1}} {
+		report, err := uc.Load(context.Background(), usecase.GenerateReportRequest{RootPath: root, Filter: tc.filter})
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		if got := usecase.EvaluateGates(report, []usecase.GateRule{rule}); len(got) != tc.failing {
+			t.Fatalf("filter %q: expected %d failing gate(s), got %v", tc.filter, tc.failing, got)
+		}
+	}
+}
+
+func TestExcessiveCommentsSmell(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"dead.c": `/* Old implementation kept around "just in case".
+int total = 0;
+for (i = 0; i < n; i++) {
+    total += values[i];
+}
+*/
+// return legacy_sum(values, n);
+// if (n > 0) {
+int sum(int n) {
+	return n;
+}
+`,
+		"prose.go": `// Package prose is documented at length.
+// It explains the design in plain words
+// and the reasons behind each trade-off
+// without any code in the comments
+// so it reads as documentation only.
+package prose
+
+func Prose() {}
+`,
+		"code.go": "package a\n\n// Add adds.\nfunc Add(a, b int) int {\n\treturn a + b\n}\n",
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{
+		RootPath: root,
+		Smells:   usecase.SmellConfig{MaxCommentDensity: 0.6},
+	})
+	flagged := map[string]model.CodeSmell{}
+	for _, f := range report.Files {
+		for _, s := range smellsOfKind(&f, model.SmellExcessiveComments) {
+			flagged[filepath.Base(f.Path)] = s
+		}
+	}
+	dead, ok := flagged["dead.c"]
+	if len(flagged) != 1 || !ok || dead.Line != 2 || !strings.Contains(dead.Description, "6 commented-out code lines") {
+		t.Fatalf("expected only dead.c flagged from line 2, got %+v", flagged)
+	}
+	if model.SmellSeverity(model.SmellExcessiveComments) != model.SeverityInfo {
+		t.Fatalf("excessive comments should be an info-level note")
+	}
+
+	report = analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	if n := report.Project.SmellCountsByKind[model.SmellExcessiveComments]; n != 0 {
+		t.Fatalf("the check must be opt-in, got %d smells", n)
+	}
+}
+
+func TestDeclarationOnlyFilesCounted(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"types.go": `package shapes
+
+type Point struct {
+	X, Y int
+}
+
+type Shape interface {
+	Area() float64
+}
+
+const Origin = 0
+`,
+		"area.go": `package shapes
+
+func Scale(p Point, k int) Point {
+	return Point{X: p.X * k, Y: p.Y * k}
+}
+`,
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	if report.Project.TotalFiles != 2 || report.Project.DeclarationOnlyFiles != 1 {
+		t.Fatalf("expected 2 files with 1 declaration-only, got %d / %d",
+			report.Project.TotalFiles, report.Project.DeclarationOnlyFiles)
+	}
+
+	out, err := outputadapter.NewTextRendererWithOptions(outputadapter.TextRendererOptions{ListDeclarationOnly: true}).Render(report)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	plain := stripANSI(out)
+	if !strings.Contains(plain, "2 (1 declaration-only)") {
+		t.Fatalf("expected the files summary to mention declaration-only files:\n%s", plain)
+	}
+	section := plain[strings.Index(plain, "Declaration-only files"):]
+	if !strings.Contains(section, "types.go") || strings.Contains(section, "area.go") {
+		t.Fatalf("expected only types.go to be listed:\n%s", section)
+	}
+}
+
+func TestHealthGradeFollowsComplexityWeights(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"nested.go": `package nested
+
+func Route(a, b, c, d, e, f bool) int {
+	if a {
+		if b {
+			if c {
+				if d {
+					if e {
+						if f {
+							return 1
+						}
+					}
+				}
+			}
+		}
+	}
+	return 0
+}
+`,
+	})
+
+	grade := func(spec string) *model.HealthGrade {
+		t.Helper()
+		weights, err := usecase.ParseHealthWeights(spec)
+		if err != nil {
+			t.Fatalf("parse %q: %v", spec, err)
+		}
+		report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, HealthWeights: weights})
+		if report.Health == nil {
+			t.Fatalf("expected a health grade for %q", spec)
+		}
+		return report.Health
+	}
+
+	fn := findFunction(t, &analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root}).Files[0], "Route")
+	if fn.CognitiveComplexity <= fn.CCN {
+		t.Fatalf("fixture should be cognitively heavier than cyclomatic, got CCN %d / cognitive %d", fn.CCN, fn.CognitiveComplexity)
+	}
+
+	cyclomatic := grade("cyclomatic=1")
+	cognitive := grade("cognitive=1")
+	blended := grade("")
+
+	if cyclomatic.Score <= blended.Score || blended.Score <= cognitive.Score {
+		t.Fatalf("expected cyclomatic > blended > cognitive scores, got %.1f / %.1f / %.1f",
+			cyclomatic.Score, blended.Score, cognitive.Score)
+	}
+	if cyclomatic.Grade == cognitive.Grade {
+		t.Fatalf("expected the grade letter to shift with the weighting, both were %s", cyclomatic.Grade)
+	}
+	if blended.CyclomaticWeight != usecase.DefaultHealthCyclomaticWeight || blended.CognitiveWeight != usecase.DefaultHealthCognitiveWeight {
+		t.Fatalf("expected default weights, got %.2f/%.2f", blended.CyclomaticWeight, blended.CognitiveWeight)
+	}
+
+	if _, err := usecase.ParseHealthWeights("cyclomatic=0,cognitive=0"); err == nil {
+		t.Fatal("expected all-zero weights to be rejected")
+	}
+	if _, err := usecase.ParseHealthWeights("halstead=1"); err == nil {
+		t.Fatal("expected an unknown weight name to be rejected")
+	}
+}
+
+func TestComplexityDivergenceAtBothExtremes(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"div.go": `package div
+
+func Flat(code int) string {
+	switch code {
+	case 1:
+		return "one"
+	case 2:
+		return "two"
+	case 3:
+		return "three"
+	case 4:
+		return "four"
+	case 5:
+		return "five"
+	case 6:
+		return "six"
+	case 7:
+		return "seven"
+	default:
+		return "many"
+	}
+}
+
+func Nested(a, b, c, d, e, f, g bool) int {
+	if a {
+		if b {
+			if c {
+				if d {
+					if e {
+						if f {
+							if g {
+								return 1
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func Plain(a, b bool) int {
+	if a {
+		return 1
+	}
+	if b {
+		return 2
+	}
+	return 0
+}
+`,
+	})
+
+	parsers := []ports.CodeParser{parser.NewGoParserWithOptions(parser.GoParserOptions{CognitiveModel: parser.CognitiveModelSonar})}
+	report := analyzeWithParsers(t, parsers, usecase.AnalyzeProjectRequest{
+		RootPath: root,
+		Smells:   usecase.SmellConfig{MaxComplexityDivergence: 3},
+	})
+	f := &report.Files[0]
+
+	flat := findFunction(t, f, "Flat")
+	if flat.Divergence != model.DivergenceFlatBranching || flat.CognitiveToCCN >= 1.0/3 {
+		t.Fatalf("expected Flat to be noted as flat branching, got %q (ratio %.2f, CCN %d, cognitive %d)",
+			flat.Divergence, flat.CognitiveToCCN, flat.CCN, flat.CognitiveComplexity)
+	}
+	nested := findFunction(t, f, "Nested")
+	if nested.Divergence != model.DivergenceDeepNesting || nested.CognitiveToCCN <= 3 {
+		t.Fatalf("expected Nested to be noted as deep nesting, got %q (ratio %.2f, CCN %d, cognitive %d)",
+			nested.Divergence, nested.CognitiveToCCN, nested.CCN, nested.CognitiveComplexity)
+	}
+	if plain := findFunction(t, f, "Plain"); plain.Divergence != "" {
+		t.Fatalf("expected no divergence note for Plain, got %q", plain.Divergence)
+	}
+
+	disabled := analyzeWithParsers(t, parsers, usecase.AnalyzeProjectRequest{RootPath: root})
+	for _, fn := range disabled.Files[0].Functions {
+		if fn.Divergence != "" {
+			t.Fatalf("expected no notes with the threshold disabled, %s got %q", fn.Name, fn.Divergence)
+		}
+	}
+}
+
+func TestCIMetadataFromEnvAndFlags(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+
+	github := env(map[string]string{
+		"GITHUB_SHA":      "abc123",
+		"GITHUB_REF":      "refs/pull/42/merge",
+		"GITHUB_REF_NAME": "42/merge",
+		"GITHUB_HEAD_REF": "feature/ci",
+		"GITHUB_RUN_ID":   "9001",
+	})
+	ci := infrastructure.ResolveCIMetadata(model.CIMetadata{}, github)
+	want := model.CIMetadata{Branch: "feature/ci", Commit: "abc123", BuildID: "9001", PullRequest: "42"}
+	if ci == nil || *ci != want {
+		t.Fatalf("expected %+v from GitHub env vars, got %+v", want, ci)
+	}
+
+	gitlab := env(map[string]string{
+		"CI_COMMIT_SHA":        "def456",
+		"CI_COMMIT_REF_NAME":   "main",
+		"CI_PIPELINE_ID":       "77",
+		"CI_MERGE_REQUEST_IID": "5",
+	})
+	ci = infrastructure.ResolveCIMetadata(model.CIMetadata{Commit: "override", PullRequest: "6"}, gitlab)
+	want = model.CIMetadata{Branch: "main", Commit: "override", BuildID: "77", PullRequest: "6"}
+	if ci == nil || *ci != want {
+		t.Fatalf("expected flags to override GitLab env vars, want %+v, got %+v", want, ci)
+	}
+
+	if ci := infrastructure.ResolveCIMetadata(model.CIMetadata{}, env(nil)); ci != nil {
+		t.Fatalf("expected no CI metadata outside CI, got %+v", ci)
+	}
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"a.go": "package a\n\nfunc A() {}\n"})
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, NoSave: true})
+	if report.CI != nil {
+		t.Fatalf("expected no CI block, got %+v", report.CI)
+	}
+	report = analyzeTree(t, usecase.AnalyzeProjectRequest{
+		RootPath: root,
+		NoSave:   true,
+		CI:       infrastructure.ResolveCIMetadata(model.CIMetadata{}, github),
+	})
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"ci":{"branch":"feature/ci","commit":"abc123","buildId":"9001","pullRequest":"42"}`) {
+		t.Fatalf("expected the CI block in the JSON report, got %s", data)
+	}
+}
+
+func TestDataFunctionExemptFromSizeSmells(t *testing.T) {
+	var table strings.Builder
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&table, "\t\t{ID: %d, Name: \"entry-%d\", Weight: %d},\n", i, i, i*3)
+	}
+	var logic strings.Builder
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&logic, "\ttotal += x * %d\n", i)
+	}
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"table.go": "package table\n\ntype Entry struct {\n\tID     int\n\tName   string\n\tWeight int\n}\n\n" +
+			"func Entries() []Entry {\n\treturn []Entry{\n" + table.String() + "\t}\n}\n\n" +
+			"func Sum(x int) int {\n\ttotal := 0\n" + logic.String() + "\treturn total\n}\n",
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{
+		RootPath: root,
+		Smells:   usecase.SmellConfig{MaxFunctionNLOC: 30},
+	})
+	f := &report.Files[0]
+
+	entries := findFunction(t, f, "Entries")
+	if !entries.IsDataFunction || entries.NLOC <= 30 {
+		t.Fatalf("expected Entries to be a data function over the size limit, got data=%v nloc=%d", entries.IsDataFunction, entries.NLOC)
+	}
+	if sum := findFunction(t, f, "Sum"); sum.IsDataFunction {
+		t.Fatalf("expected Sum not to be a data function")
+	}
+
+	long := map[string]bool{}
+	for _, s := range smellsOfKind(f, model.SmellLongFunction) {
+		long[s.Function] = true
+	}
+	if long["Entries"] || !long["Sum"] {
+		t.Fatalf("expected only Sum to be flagged as a long function, got %v", long)
+	}
+}
+
+func TestMaxFunctionsPerFileKeepsMostComplex(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"many.go": `package many
+
+func A() int { return 1 }
+
+func B(x int) int {
+	if x > 0 {
+		return x
+	}
+	return 0
+}
+
+func C(x, y int) int { return x + y }
+
+func D(x int) int {
+	switch x {
+	case 1:
+		return 1
+	case 2:
+		return 2
+	}
+	return 0
+}
+`,
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, MaxFunctionsPerFile: 2})
+	f := report.Files[0]
+	if len(f.Functions) != 2 || f.Functions[0].Name != "B" || f.Functions[1].Name != "D" {
+		t.Fatalf("expected the two most complex functions B and D in source order, got %+v", f.Functions)
+	}
+	others := f.OtherFunctions
+	if others == nil || others.Count != 2 || others.CCNTotal != 2 || others.Parameters != 2 {
+		t.Fatalf("expected A and C folded into others, got %+v", others)
+	}
+	if report.Project.TotalFunctions != 4 || f.Summary.FunctionsCount != 4 {
+		t.Fatalf("expected project and file totals to count all 4 functions, got %d / %d",
+			report.Project.TotalFunctions, f.Summary.FunctionsCount)
+	}
+}
+
+func TestMaxFunctionsPerFileKeepsProjectMetrics(t *testing.T) {
+	var long strings.Builder
+	for i := 0; i < 60; i++ {
+		long.WriteString("\tx++\n")
+	}
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"many.go": "package many\n\nfunc Long(x int) int {\n" + long.String() + "\treturn x\n}\n\n" +
+			"func Wide(a, b, c, d, e int) int { return a }\n\n" +
+			"func B(x int) int {\n\tif x > 0 {\n\t\treturn x\n\t}\n\treturn 0\n}\n\n" +
+			"func D(x int) int {\n\tswitch x {\n\tcase 1:\n\t\treturn 1\n\tcase 2:\n\t\treturn 2\n\t}\n\treturn 0\n}\n",
+	})
+
+	full := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	capped := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, MaxFunctionsPerFile: 2})
+	if capped.Files[0].OtherFunctions == nil || capped.Files[0].OtherFunctions.Count != 2 {
+		t.Fatalf("expected Long and Wide to be folded, got %+v", capped.Files[0].OtherFunctions)
+	}
+	folded, err := json.Marshal(capped.Files[0].OtherFunctions)
+	if err != nil {
+		t.Fatalf("marshal folded functions: %v", err)
+	}
+	if strings.Contains(string(folded), "health") || strings.Contains(string(folded), `"sizes"`) {
+		t.Fatalf("folded functions should only carry raw metric shapes, got %s", folded)
+	}
+
+	reloaded, err := usecase.NewGenerateReportUseCase(infrastructure.NewFileStorage(), outputadapter.NewRendererRegistry(
+		outputadapter.NewJSONRenderer(),
+	)).Load(context.Background(), usecase.GenerateReportRequest{RootPath: root, Filter: "*"})
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+
+	for _, got := range []*model.ProjectReport{capped, reloaded} {
+		want, p := full.Project, got.Project
+		if p.TotalFunctions != want.TotalFunctions || p.MedianFunctionSize != want.MedianFunctionSize ||
+			p.P95FunctionSize != want.P95FunctionSize || p.FunctionsGt50Lines != want.FunctionsGt50Lines ||
+			p.FunctionsParamsGe5 != want.FunctionsParamsGe5 || p.AvgParamsPerFunction != want.AvgParamsPerFunction {
+			t.Fatalf("folding changed project metrics:\nwant %+v\ngot  %+v", want, p)
+		}
+		if want.FunctionsGt50Lines != 1 || want.FunctionsParamsGe5 != 1 {
+			t.Fatalf("fixture should have one long and one wide function, got %+v", want)
+		}
+		if got.Health == nil || full.Health == nil || math.Abs(got.Health.Score-full.Health.Score) > 1e-9 {
+			t.Fatalf("folding changed the health grade: want %+v, got %+v", full.Health, got.Health)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	parser "github.com/rafaelvolkmer/codeaudit/internal/adapter/parser"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/ports"
+	"github.com/rafaelvolkmer/codeaudit/internal/infrastructure"
+	"github.com/rafaelvolkmer/codeaudit/internal/usecase"
+)
+
+func TestScanIncludeVendored(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"main.go":                  "package main\n",
+		"vendor/dep/dep.go":        "package dep\n",
+		"node_modules/pkg/index.c": "int f(void) { return 0; }\n",
+		".git/hooks/hook.go":       "package hooks\n",
+	})
+
+	ctx := context.Background()
+	exts := []string{".go", ".c"}
+
+	files, err := infrastructure.NewFSScanner().Scan(ctx, root, exts)
+	if err != nil {
+		t.Fatalf("default scan: %v", err)
+	}
+	got := relPaths(t, root, files)
+	if !got["main.go"] || got["vendor/dep/dep.go"] || got["node_modules/pkg/index.c"] {
+		t.Fatalf("default scan should skip vendored code, got %v", got)
+	}
+
+	files, err = infrastructure.NewFSScannerWithOptions(infrastructure.FSScannerOptions{
+		IncludeVendored: true,
+	}).Scan(ctx, root, exts)
+	if err != nil {
+		t.Fatalf("include-vendored scan: %v", err)
+	}
+	got = relPaths(t, root, files)
+	if !got["vendor/dep/dep.go"] || !got["node_modules/pkg/index.c"] {
+		t.Fatalf("expected vendored files with IncludeVendored, got %v", got)
+	}
+	if got[".git/hooks/hook.go"] {
+		t.Fatalf("IncludeVendored must still skip .git, got %v", got)
+	}
+
+	files, err = infrastructure.NewFSScannerWithOptions(infrastructure.FSScannerOptions{
+		NoDefaultSkips: true,
+	}).Scan(ctx, root, exts)
+	if err != nil {
+		t.Fatalf("no-default-skips scan: %v", err)
+	}
+	got = relPaths(t, root, files)
+	if len(got) != 4 {
+		t.Fatalf("expected every file with NoDefaultSkips, got %v", got)
+	}
+}
+
+func TestScanSkipHidden(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"main.go":             "package main\n",
+		".scripts/gen.go":     "package scripts\n",
+		".hidden.go":          "package main\n",
+		"pkg/.local/tool.go":  "package local\n",
+		"pkg/visible.go":      "package pkg\n",
+		".github/tools/ci.go": "package tools\n",
+	})
+
+	ctx := context.Background()
+	exts := []string{".go"}
+
+	files, err := infrastructure.NewFSScanner().Scan(ctx, root, exts)
+	if err != nil {
+		t.Fatalf("default scan: %v", err)
+	}
+	got := relPaths(t, root, files)
+	if len(got) != 6 {
+		t.Fatalf("default scan should keep dot paths, got %v", got)
+	}
+
+	files, err = infrastructure.NewFSScannerWithOptions(infrastructure.FSScannerOptions{
+		SkipHidden: true,
+	}).Scan(ctx, root, exts)
+	if err != nil {
+		t.Fatalf("skip-hidden scan: %v", err)
+	}
+	got = relPaths(t, root, files)
+	if len(got) != 2 || !got["main.go"] || !got["pkg/visible.go"] {
+		t.Fatalf("expected only non-hidden files, got %v", got)
+	}
+
+	files, err = infrastructure.NewFSScannerWithOptions(infrastructure.FSScannerOptions{
+		SkipHidden: true,
+	}).Scan(ctx, filepath.Join(root, ".scripts"), exts)
+	if err != nil {
+		t.Fatalf("hidden root scan: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("an explicitly requested hidden root must still be scanned, got %v", files)
+	}
+}
+
+func TestScanMaxDepth(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"main.go":       "package main\n",
+		"a/a.go":        "package a\n",
+		"a/b/b.go":      "package b\n",
+		"a/b/c/deep.go": "package c\n",
+	})
+
+	ctx := context.Background()
+	scan := func(opts infrastructure.FSScannerOptions) map[string]bool {
+		t.Helper()
+		files, err := infrastructure.NewFSScannerWithOptions(opts).Scan(ctx, root, []string{".go"})
+		if err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		return relPaths(t, root, files)
+	}
+
+	if got := scan(infrastructure.FSScannerOptions{LimitDepth: true, MaxDepth: 0}); len(got) != 1 || !got["main.go"] {
+		t.Fatalf("depth 0 should keep only root files, got %v", got)
+	}
+	if got := scan(infrastructure.FSScannerOptions{LimitDepth: true, MaxDepth: 2}); len(got) != 3 || got["a/b/c/deep.go"] {
+		t.Fatalf("depth 2 should exclude a/b/c, got %v", got)
+	}
+	if got := scan(infrastructure.FSScannerOptions{MaxDepth: 0}); len(got) != 4 {
+		t.Fatalf("depth is unlimited unless LimitDepth is set, got %v", got)
+	}
+}
+
+func TestTestFileClassificationRespectsCustomNames(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"lib.go":         "package lib\n\nfunc Lib() {}\n",
+		"lib_test.go":    "package lib\n\nfunc TestLib() {}\n",
+		"qa/smoke.c":     "int smoke(void) {\n\treturn 0;\n}\n",
+		"tests/legacy.c": "int legacy(void) {\n\treturn 0;\n}\n",
+		"src/check_io.c": "int check_io(void) {\n\treturn 0;\n}\n",
+		"src/io.c":       "int io(void) {\n\treturn 0;\n}\n",
+	})
+	custom := usecase.TestFileConfig{
+		Dirs: []string{"qa"},
+		Patterns: map[model.Language][]string{
+			model.LanguageGo: {"*_test.go"},
+			model.LanguageC:  {"check_*.c"},
+		},
+	}
+
+	classify := func(mode string) (tests, regular []string) {
+		cfg := custom
+		cfg.Mode = mode
+		report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, Tests: cfg})
+		for _, f := range report.Files {
+			rel, _ := filepath.Rel(root, f.Path)
+			if f.Test {
+				tests = append(tests, filepath.ToSlash(rel))
+			} else {
+				regular = append(regular, filepath.ToSlash(rel))
+			}
+		}
+		sort.Strings(tests)
+		sort.Strings(regular)
+		return tests, regular
+	}
+
+	tests, regular := classify(usecase.TestsInclude)
+	if fmt.Sprint(tests) != "[lib_test.go qa/smoke.c src/check_io.c]" ||
+		fmt.Sprint(regular) != "[lib.go src/io.c tests/legacy.c]" {
+		t.Fatalf("unexpected classification: tests %v, regular %v", tests, regular)
+	}
+
+	if tests, regular = classify(usecase.TestsExclude); len(tests) != 0 || len(regular) != 3 {
+		t.Fatalf("exclude mode kept tests %v, regular %v", tests, regular)
+	}
+	if tests, regular = classify(usecase.TestsOnly); len(tests) != 3 || len(regular) != 0 {
+		t.Fatalf("only mode kept tests %v, regular %v", tests, regular)
+	}
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	for _, f := range report.Files {
+		if want := filepath.Base(filepath.Dir(f.Path)) == "tests" || filepath.Base(f.Path) == "lib_test.go"; f.Test != want {
+			t.Fatalf("default classification of %s: got test=%v", f.Path, f.Test)
+		}
+	}
+}
+
+func TestTestFilePatternsApplyToTheirLanguage(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"check_io.go": "package io\n\nfunc CheckIO() {}\n",
+		"check_io.c":  "int check_io(void) {\n\treturn 0;\n}\n",
+	})
+	cfg := usecase.TestFileConfig{Patterns: map[model.Language][]string{model.LanguageGo: {"check_*"}}}
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root, Tests: cfg})
+	for _, f := range report.Files {
+		if want := filepath.Ext(f.Path) == ".go"; f.Test != want {
+			t.Fatalf("%s: a Go pattern must only classify Go files, got test=%v", f.Path, f.Test)
+		}
+	}
+
+	cfg.Patterns = map[model.Language][]string{"rust": {"*_test.rs"}}
+	uc := usecase.NewAnalyzeProjectUseCase(infrastructure.NewFSScanner(), infrastructure.NewFSScanner(),
+		[]ports.CodeParser{parser.NewGoParser()}, noGitClient{}, &memStorage{}, 1)
+	if _, err := uc.Execute(context.Background(), usecase.AnalyzeProjectRequest{RootPath: root, IncludeExt: []string{".go"}, Tests: cfg}); err == nil {
+		t.Fatalf("expected an error for an unknown test pattern language")
+	}
+	if err := usecase.ValidateTestFileConfig(cfg); err == nil {
+		t.Fatalf("expected the config to be rejected before any analysis runs")
+	}
+}
+
+func TestReadFileTranscodesLatin1AndUTF16(t *testing.T) {
+	root := t.TempDir()
+	latin1 := []byte("/* r\xe9sum\xe9 helpers, \xa9 caf\xe9 */\nint accent(int c) {\n\tif (c == '\xe9') {\n\t\treturn 1;\n\t}\n\treturn 0;\n}\n")
+	writeTree(t, root, map[string]string{"legacy.c": string(latin1)})
+
+	raw, err := infrastructure.NewFSScanner().ReadFile(filepath.Join(root, "legacy.c"))
+	if err != nil {
+		t.Fatalf("default read: %v", err)
+	}
+	if !bytes.Equal(raw, latin1) {
+		t.Fatalf("expected UTF-8 passthrough by default")
+	}
+
+	scanner := infrastructure.NewFSScannerWithOptions(infrastructure.FSScannerOptions{Encoding: "latin1"})
+	src, err := scanner.ReadFile(filepath.Join(root, "legacy.c"))
+	if err != nil {
+		t.Fatalf("latin1 read: %v", err)
+	}
+	if !utf8.Valid(src) || !strings.Contains(string(src), "résumé helpers, © café") {
+		t.Fatalf("expected transcoded UTF-8, got %q", src)
+	}
+
+	fm, err := parser.NewCParser().ParseFile("legacy.c", src)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(fm.Functions) != 1 || fm.Functions[0].CCN != 2 {
+		t.Fatalf("unexpected functions after transcoding: %+v", fm.Functions)
+	}
+
+	utf16 := []byte{0xFF, 0xFE}
+	for _, r := range "int f(void) {\n\treturn 0;\n}\n" {
+		utf16 = append(utf16, byte(r), 0)
+	}
+	writeTree(t, root, map[string]string{"wide.c": string(utf16)})
+	src, err = infrastructure.NewFSScanner().ReadFile(filepath.Join(root, "wide.c"))
+	if err != nil || string(src) != "int f(void) {\n\treturn 0;\n}\n" {
+		t.Fatalf("expected a UTF-16 file with BOM to be decoded, got %q (%v)", src, err)
+	}
+
+	if err := infrastructure.ValidateEncoding("klingon"); err == nil {
+		t.Fatalf("expected an error for an unknown encoding")
+	}
+}
+
+func TestScanRestrictedToCodeOwner(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".github/CODEOWNERS": `# Default owners
+*                   @org/platform
+
+# Team ownership
+/services/billing/  @org/payments
+*.c                 @org/native @alice
+docs/               @org/writers
+/services/billing/legacy/**  @org/payments-legacy
+/services/billing/shared.go
+`,
+		"main.go":                           "package main\n",
+		"services/billing/invoice.go":       "package billing\n",
+		"services/billing/tax.c":            "int tax(void) { return 0; }\n",
+		"services/billing/legacy/old.go":    "package legacy\n",
+		"services/billing/shared.go":        "package billing\n",
+		"services/search/index.go":          "package search\n",
+		"lib/native/hash.c":                 "int hash(void) { return 0; }\n",
+		"services/billing/nested/ledger.go": "package nested\n",
+	})
+
+	owners, err := infrastructure.LoadCodeOwners(root)
+	if err != nil {
+		t.Fatalf("load CODEOWNERS: %v", err)
+	}
+	if got := owners.Owners("services/billing/tax.c"); fmt.Sprint(got) != "[@org/native @alice]" {
+		t.Fatalf("expected the later *.c rule to win for tax.c, got %v", got)
+	}
+	if got := owners.Owners("services/billing/shared.go"); len(got) != 0 {
+		t.Fatalf("expected a rule without owners to unassign shared.go, got %v", got)
+	}
+
+	scan := func(owner string) []string {
+		scanner := infrastructure.NewFSScannerWithOptions(infrastructure.FSScannerOptions{CodeOwners: owners, Owner: owner})
+		files, err := scanner.Scan(context.Background(), root, []string{".go", ".c"})
+		if err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		var got []string
+		for rel := range relPaths(t, root, files) {
+			got = append(got, rel)
+		}
+		sort.Strings(got)
+		return got
+	}
+
+	if got := scan("@org/payments"); fmt.Sprint(got) != "[services/billing/invoice.go services/billing/nested/ledger.go]" {
+		t.Fatalf("unexpected files for @org/payments: %v", got)
+	}
+	if got := scan("@ORG/Native"); fmt.Sprint(got) != "[lib/native/hash.c services/billing/tax.c]" {
+		t.Fatalf("unexpected files for @org/native: %v", got)
+	}
+	if got := scan("@org/platform"); fmt.Sprint(got) != "[main.go services/search/index.go]" {
+		t.Fatalf("unexpected files for @org/platform: %v", got)
+	}
+	if got := scan("@org/payments-legacy"); fmt.Sprint(got) != "[services/billing/legacy/old.go]" {
+		t.Fatalf("unexpected files for @org/payments-legacy: %v", got)
+	}
+}
+
+func TestCodeOwnersWildcardSegmentDoesNotRecurse(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".github/CODEOWNERS": "docs/*  @org/writers\n/src/*.go  @org/go\n/lib/  @org/lib\n",
+	})
+	owners, err := infrastructure.LoadCodeOwners(root)
+	if err != nil {
+		t.Fatalf("load CODEOWNERS: %v", err)
+	}
+
+	for path, want := range map[string]string{
+		"docs/intro.md":          "[@org/writers]",
+		"docs/guides/setup.md":   "[]",
+		"src/main.go":            "[@org/go]",
+		"src/main.go/nested.txt": "[]",
+		"src/pkg/util.go":        "[]",
+		"lib/a/b.c":              "[@org/lib]",
+	} {
+		if got := fmt.Sprint(owners.Owners(path)); got != want {
+			t.Fatalf("%s: expected owners %s, got %s", path, want, got)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/infrastructure"
+	"github.com/rafaelvolkmer/codeaudit/internal/usecase"
+)
+
+func TestSQLiteExportAccumulatesRuns(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.go": `package a
+
+func A(x int) int {
+	if x > 0 {
+		return B(x)
+	}
+	return 0
+}
+
+func B(x int) int { return x }
+`,
+		"b.c": "int twice(int x) {\n\treturn x * 2;\n}\n",
+	})
+
+	report := analyzeTree(t, usecase.AnalyzeProjectRequest{RootPath: root})
+	dbPath := filepath.Join(t.TempDir(), "audit.sqlite")
+	ctx := context.Background()
+
+	if err := infrastructure.ExportToSQLite(ctx, dbPath, report); err != nil {
+		t.Fatalf("first export: %v", err)
+	}
+	if err := infrastructure.ExportToSQLite(ctx, dbPath, report); err != nil {
+		t.Fatalf("re-export of same run: %v", err)
+	}
+	report.GeneratedAt = report.GeneratedAt.Add(time.Minute)
+	if err := infrastructure.ExportToSQLite(ctx, dbPath, report); err != nil {
+		t.Fatalf("second export: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+
+	counts := map[string]int{}
+	for _, table := range []string{"project", "files", "functions", "smells"} {
+		var n int
+		if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		counts[table] = n
+	}
+	if counts["project"] != 2 || counts["files"] != 4 || counts["functions"] != 6 {
+		t.Fatalf("unexpected row counts: %v", counts)
+	}
+
+	var maxCCN int
+	err = db.QueryRow(
+		"SELECT MAX(ccn) FROM functions WHERE root_path = ? AND name = 'A'", report.RootPath,
+	).Scan(&maxCCN)
+	if err != nil || maxCCN != 2 {
+		t.Fatalf("expected A to have ccn 2, got %d (%v)", maxCCN, err)
+	}
+}
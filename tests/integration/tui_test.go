@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2024-2025 Rafael V. Volkmer <rafael.v.volkmer@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/rafaelvolkmer/codeaudit/internal/adapter/tui"
+	"github.com/rafaelvolkmer/codeaudit/internal/domain/model"
+)
+
+func TestTUIBrowserNavigation(t *testing.T) {
+	report := &model.ProjectReport{
+		RootPath: "/repo",
+		Files: []model.FileMetrics{
+			{
+				Path:    "a.go",
+				Summary: model.FileSummaryMetrics{CCNTotal: 5},
+				Git:     &model.GitFileMetrics{LinesAdded: 100},
+				Functions: []model.FunctionMetrics{
+					{Name: "small", CCN: 1, StartLine: 3},
+					{Name: "big", CCN: 4, StartLine: 10},
+				},
+			},
+			{
+				Path:    "b.go",
+				Summary: model.FileSummaryMetrics{CCNTotal: 20},
+				Git:     &model.GitFileMetrics{LinesAdded: 5},
+				Smells:  []model.CodeSmell{{Kind: model.SmellDeepNesting}},
+			},
+			{
+				Path:    "c.c",
+				Summary: model.FileSummaryMetrics{CCNTotal: 9},
+				Smells:  []model.CodeSmell{{Kind: model.SmellDuplicateInclude}},
+			},
+		},
+	}
+	paths := func(b *tui.Browser) string {
+		var out []string
+		for _, f := range b.Files() {
+			out = append(out, f.Path)
+		}
+		return strings.Join(out, ",")
+	}
+
+	b := tui.NewBrowser(report)
+	if got := paths(b); got != "b.go,c.c,a.go" {
+		t.Fatalf("default CCN order: %s", got)
+	}
+	b.CycleSort()
+	if b.SortBy() != tui.SortChurn || paths(b) != "a.go,b.go,c.c" {
+		t.Fatalf("churn order: %s by %s", paths(b), b.SortBy())
+	}
+	b.CycleSort()
+	if b.SortBy() != tui.SortPath || paths(b) != "a.go,b.go,c.c" {
+		t.Fatalf("path order: %s by %s", paths(b), b.SortBy())
+	}
+
+	b.Move(-5)
+	b.Enter()
+	if !b.InFile() || b.Selected().Path != "a.go" {
+		t.Fatalf("expected to drill into a.go")
+	}
+	if fns := b.Functions(); len(fns) != 2 || fns[0].Name != "big" {
+		t.Fatalf("functions should be sorted by CCN: %+v", fns)
+	}
+	if view := b.View(20); !strings.Contains(view, "> big") || !strings.Contains(view, "a.go (2 functions, 0 smells)") {
+		t.Fatalf("unexpected file view:\n%s", view)
+	}
+	b.Back()
+
+	b.CycleSmellFilter()
+	if b.SmellFilter() != model.SmellDeepNesting || paths(b) != "b.go" {
+		t.Fatalf("first filter: %s -> %s", b.SmellFilter(), paths(b))
+	}
+	b.CycleSmellFilter()
+	if b.SmellFilter() != model.SmellDuplicateInclude || paths(b) != "c.c" {
+		t.Fatalf("second filter: %s -> %s", b.SmellFilter(), paths(b))
+	}
+	b.CycleSmellFilter()
+	if b.SmellFilter() != "" || len(b.Files()) != 3 {
+		t.Fatalf("filter should wrap back to all files, got %s", b.SmellFilter())
+	}
+
+	b.Move(100)
+	if b.Selected().Path != "c.c" {
+		t.Fatalf("cursor should clamp to the last file, got %s", b.Selected().Path)
+	}
+}
+
+func TestTUIBrowserTruncatesOnRuneBoundaries(t *testing.T) {
+	path := strings.Repeat("é", 60) + "/ab.go"
+	report := &model.ProjectReport{
+		Files: []model.FileMetrics{{
+			Path:      path,
+			Functions: []model.FunctionMetrics{{Name: strings.Repeat("é", 50), CCN: 1}},
+		}},
+	}
+
+	b := tui.NewBrowser(report)
+	view := b.View(20)
+	if !utf8.ValidString(view) || !strings.Contains(view, "…") || !strings.Contains(view, "é/ab.go") {
+		t.Fatalf("expected a valid UTF-8 path truncated from the left:\n%s", view)
+	}
+	b.Enter()
+	if view := b.View(20); !utf8.ValidString(view) {
+		t.Fatalf("expected a valid UTF-8 function list:\n%q", view)
+	}
+}